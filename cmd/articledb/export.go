@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/Br0ce/articleDB/pkg/archive"
+)
+
+// export downloads an archive of all articles of the server.
+func export(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	server := fs.String("server", "http://localhost:8080", "url of the articledb server")
+	out := fs.String("o", "articledb.ndjson.gz", "file to write the archive to")
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(*server, "/")+"/articles:export", nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	// The archive is written to a temporary file first, so an interrupted export
+	// does not replace an earlier archive.
+	tmp := *out + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+
+	n, err := io.Copy(f, resp.Body)
+	if err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+	err = f.Close()
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+
+	// A failing export may still end the response cleanly, so the archive is read
+	// completely before it replaces an earlier one.
+	articles, err := verifyArchive(tmp)
+	if err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("incomplete archive, %w", err)
+	}
+
+	err = os.Rename(tmp, *out)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s: %d articles, %d bytes\n", *out, articles, n)
+	return nil
+}
+
+// verifyArchive reads the archive in file to its end and returns the number of
+// its articles.
+func verifyArchive(file string) (int, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	r, err := archive.NewReader(f)
+	if err != nil {
+		return 0, err
+	}
+	defer r.Close()
+
+	var n int
+	for {
+		_, err := r.Next()
+		if errors.Is(err, io.EOF) {
+			return n, nil
+		}
+		if err != nil {
+			return n, err
+		}
+		n++
+	}
+}
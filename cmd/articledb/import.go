@@ -0,0 +1,256 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/Br0ce/articleDB/pkg/bulk"
+)
+
+type importResult struct {
+	Line    int    `json:"line"`
+	ID      string `json:"id"`
+	Error   string `json:"error"`
+	Summary *struct {
+		Read    int    `json:"read"`
+		Added   int    `json:"added"`
+		Skipped int    `json:"skipped"`
+		Failed  int    `json:"failed"`
+		Error   string `json:"error"`
+	} `json:"summary"`
+}
+
+// importFiles streams every file to POST /articles:bulk of the server and prints
+// the errors of the lines, that could not be imported. With -restore the files are
+// archives written by export and are sent to POST /articles:restore. WARC files are
+// sent to POST /articles:warc.
+func importFiles(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	server := fs.String("server", "http://localhost:8080", "url of the articledb server")
+	format := fs.String("format", "", "format of the files, ndjson, csv or warc (default by file extension)")
+	mapping := fs.String("map", "", "column mapping, e.g. title=headline,url=link")
+	parallelism := fs.Int("parallelism", bulk.DefaultParallelism, "number of articles enriched concurrently")
+	skip := fs.Bool("skip-enrichment", false, "store the articles without enrichment")
+	restoreArchive := fs.Bool("restore", false, "restore archives written by export with their original IDs")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: articledb import [flags] file...\n\n")
+		fs.PrintDefaults()
+	}
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return errors.New("no files given")
+	}
+
+	if *restoreArchive {
+		for _, file := range fs.Args() {
+			err := restore(ctx, strings.TrimSuffix(*server, "/")+"/articles:restore", file)
+			if err != nil {
+				return fmt.Errorf("%s, %w", file, err)
+			}
+		}
+		return nil
+	}
+
+	// The mapping is validated before the first upload.
+	_, err = bulk.ParseMapping(*mapping)
+	if err != nil {
+		return err
+	}
+
+	var failed int
+	for _, file := range fs.Args() {
+		if isWARC(file, *format) {
+			n, err := uploadWARC(ctx, strings.TrimSuffix(*server, "/")+"/articles:warc", file)
+			if err != nil {
+				return fmt.Errorf("%s, %w", file, err)
+			}
+			failed += n
+			continue
+		}
+
+		f, err := fileFormat(file, *format)
+		if err != nil {
+			return err
+		}
+
+		query := url.Values{}
+		query.Set("format", string(f))
+		query.Set("parallelism", strconv.Itoa(*parallelism))
+		if *mapping != "" {
+			query.Set("map", *mapping)
+		}
+		if *skip {
+			query.Set("enrich", "false")
+		}
+
+		n, err := upload(ctx, strings.TrimSuffix(*server, "/")+"/articles:bulk?"+query.Encode(), file)
+		if err != nil {
+			return fmt.Errorf("%s, %w", file, err)
+		}
+		failed += n
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d lines or pages could not be imported", failed)
+	}
+	return nil
+}
+
+// isWARC reports whether file is a WARC file by the given format or its extension.
+func isWARC(file, format string) bool {
+	if format != "" {
+		return strings.EqualFold(format, "warc")
+	}
+	return strings.HasSuffix(file, ".warc") || strings.HasSuffix(file, ".warc.gz")
+}
+
+// fileFormat returns the given format or the format matching the extension of file.
+func fileFormat(file, format string) (bulk.Format, error) {
+	if format != "" {
+		return bulk.ParseFormat(format)
+	}
+	return bulk.ParseFormat(strings.TrimPrefix(filepath.Ext(file), "."))
+}
+
+// upload posts the file and prints the results. It returns the number of failed
+// lines.
+func upload(ctx context.Context, addr, file string) (int, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, addr, f)
+	if err != nil {
+		return 0, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<10))
+		return 0, fmt.Errorf("unexpected status %s, %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+
+	var failed int
+	sc := bufio.NewScanner(resp.Body)
+	for sc.Scan() {
+		var res importResult
+		err := json.Unmarshal(sc.Bytes(), &res)
+		if err != nil {
+			return failed, fmt.Errorf("invalid response, %w", err)
+		}
+
+		if s := res.Summary; s != nil {
+			fmt.Printf("%s: read %d, added %d, skipped %d, failed %d\n", file, s.Read, s.Added, s.Skipped, s.Failed)
+			if s.Error != "" {
+				return failed, errors.New(s.Error)
+			}
+			continue
+		}
+		if res.Error != "" {
+			failed++
+			fmt.Fprintf(os.Stderr, "%s:%d: %s\n", file, res.Line, res.Error)
+		}
+	}
+
+	return failed, sc.Err()
+}
+
+// restore posts the archive and prints the number of restored articles.
+func restore(ctx context.Context, addr, file string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, addr, f)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/gzip")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var dto struct {
+		Restored int    `json:"restored"`
+		Error    string `json:"error"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&dto)
+	if err != nil {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %s, %s", resp.Status, dto.Error)
+	}
+
+	fmt.Printf("%s: restored %d\n", file, dto.Restored)
+	return nil
+}
+
+// uploadWARC posts the WARC file and prints the summary. It returns the number of
+// pages, that could not be added.
+func uploadWARC(ctx context.Context, addr, file string) (int, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, addr, f)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/warc")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	var dto struct {
+		Records int    `json:"records"`
+		Pages   int    `json:"pages"`
+		Added   int    `json:"added"`
+		Skipped int    `json:"skipped"`
+		Failed  int    `json:"failed"`
+		Error   string `json:"error"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&dto)
+	if err != nil {
+		return 0, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("unexpected status %s, %s", resp.Status, dto.Error)
+	}
+
+	fmt.Printf("%s: records %d, pages %d, added %d, skipped %d, failed %d\n",
+		file, dto.Records, dto.Pages, dto.Added, dto.Skipped, dto.Failed)
+	return dto.Failed, nil
+}
@@ -0,0 +1,56 @@
+// Command articledb runs the articleDB server and imports articles into it.
+//
+// Usage:
+//
+//	articledb serve [flags]
+//	articledb import [flags] file...
+//	articledb export [flags]
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+const usage = `usage: articledb <command> [flags]
+
+commands:
+  serve    run the http api
+  import   import NDJSON or CSV files or restore archives into a running server
+  export   download an archive of all articles of a running server
+
+Run "articledb <command> -h" for the flags of a command.
+`
+
+type command func(ctx context.Context, args []string) error
+
+var commands = map[string]command{
+	"serve":  serve,
+	"import": importFiles,
+	"export": export,
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	cmd, ok := commands[os.Args[1]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := cmd(ctx, os.Args[2:]); err != nil {
+		fmt.Fprintf(os.Stderr, "articledb %s: %s\n", os.Args[1], err.Error())
+		stop()
+		os.Exit(1)
+	}
+}
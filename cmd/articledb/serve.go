@@ -0,0 +1,256 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/Br0ce/articleDB/pkg/api"
+	"github.com/Br0ce/articleDB/pkg/article"
+	"github.com/Br0ce/articleDB/pkg/crawl"
+	"github.com/Br0ce/articleDB/pkg/db"
+	"github.com/Br0ce/articleDB/pkg/db/inmem"
+	"github.com/Br0ce/articleDB/pkg/extract/ngram"
+	openai "github.com/Br0ce/articleDB/pkg/extract/openAI"
+	"github.com/Br0ce/articleDB/pkg/feed"
+	"github.com/Br0ce/articleDB/pkg/fetch"
+	"github.com/Br0ce/articleDB/pkg/ids"
+	"github.com/Br0ce/articleDB/pkg/logger"
+	"github.com/Br0ce/articleDB/pkg/query"
+	"github.com/Br0ce/articleDB/pkg/sitemap"
+	"github.com/Br0ce/articleDB/pkg/vector/hnsw"
+)
+
+const shutdownTimeout = 10 * time.Second
+
+// serve runs the http api until ctx is done.
+func serve(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := fs.String("addr", ":8080", "address to listen on")
+	dev := fs.Bool("dev", false, "log debug messages")
+	openAIKey := fs.String("openai-key", "", "api key of openAI to recognize named entities (default $OPENAI_API_KEY)")
+	entityTypes := fs.String("entity-types", "", "recognized entity types, e.g. person,event,weapon=weapons and weapon systems (default person,location,organisation)")
+	retention := fs.Int("revisions", inmem.DefaultRetention, "number of revisions kept per article, 0 keeps all")
+	timeIDs := fs.Bool("time-ids", false, "assign time-ordered ids to new articles, so they are listed in the order they were added")
+	summarize := fs.Bool("summarize", false, "summarize added articles with openAI, requires an api key")
+	summaryLang := fs.String("summary-lang", "", "language of the summaries, e.g. en or de (default the language of the article)")
+	contentIDs := fs.String("content-ids", "", "derive the ids of added articles from their content: addr or addr+published")
+	embed := fs.String("embed", "", "compute embeddings of added articles for the semantic search: ngram (local) or openai, requires an api key")
+	vectorIndex := fs.String("vector-index", "flat", "index of the embeddings: flat (exact) or hnsw (approximate, for large collections)")
+	hnswM := fs.Int("hnsw-m", hnsw.DefaultM, "neighbours per node of the hnsw index")
+	hnswEfConstruction := fs.Int("hnsw-ef-construction", hnsw.DefaultEfConstruction, "candidate list size of the hnsw index while inserting")
+	hnswEfSearch := fs.Int("hnsw-ef-search", hnsw.DefaultEfSearch, "candidate list size of the hnsw index while searching")
+	dupThreshold := fs.Float64("reuse-duplicates", 0, "copy the features of a stored near-duplicate with at least this similarity in (0, 1] instead of extracting them, 0 disables it")
+	feedsFile := fs.String("feeds", "", "file of RSS or Atom feeds to poll, one addr per line, optionally followed by the interval, e.g. 5m")
+	sitemapRoot := fs.String("sitemap", "", "url of a sitemap or sitemap index, whose articles are ingested in the background")
+	sitemapProgress := fs.String("sitemap-progress", "", "file of the sitemap backfill progress, so a restarted backfill resumes (default kept in memory)")
+	synonymsFile := fs.String("synonyms", "", "file of synonyms expanding search queries, one comma-separated group per line")
+	userAgent := fs.String("user-agent", crawl.DefaultUserAgent, "User-Agent of fetched pages, feeds and sitemaps, also matched against robots.txt")
+	crawlDelay := fs.Duration("crawl-delay", 0, "minimal delay between two requests to the same host, a larger crawl delay of its robots.txt takes precedence")
+	crawlConcurrency := fs.Int("crawl-concurrency", crawl.DefaultConcurrency, "maximal number of concurrent requests per host")
+	crawlTimeout := fs.Duration("crawl-timeout", crawl.DefaultTimeout, "maximal duration of a request to a host including reading the response")
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	if *openAIKey == "" {
+		*openAIKey = os.Getenv("OPENAI_API_KEY")
+	}
+	types, err := article.ParseTypes(*entityTypes)
+	if err != nil {
+		return err
+	}
+	sumLang, err := article.ParseLanguage(*summaryLang)
+	if err != nil {
+		return err
+	}
+	if *summarize && *openAIKey == "" {
+		return errors.New("summarize requires an openAI api key")
+	}
+	if *embed == "openai" && *openAIKey == "" {
+		return errors.New("openai embeddings require an openAI api key")
+	}
+	if *userAgent == "" {
+		return errors.New("user agent must not be empty")
+	}
+	if *crawlDelay < 0 {
+		return errors.New("crawl delay must not be negative")
+	}
+	if *crawlConcurrency < 1 || *crawlTimeout <= 0 {
+		return errors.New("crawl concurrency and timeout must be positive")
+	}
+
+	log := logger.New(*dev)
+
+	// Fetching pages, feeds and sitemaps shares one transport, so the limits per
+	// host apply to all of them.
+	client := crawl.NewClient(crawl.NewTransport(
+		crawl.WithUserAgent(*userAgent),
+		crawl.WithDelay(*crawlDelay),
+		crawl.WithConcurrency(*crawlConcurrency),
+		crawl.WithTimeout(*crawlTimeout),
+	))
+	fetcher := fetch.New(log.With("name", "fetcher"), fetch.WithHTTPClient(client))
+
+	opts := []api.Option{
+		api.WithFetcher(fetcher),
+		api.WithRevisions(inmem.NewRevision(inmem.WithRetention(*retention))),
+		api.WithSummaryLanguage(sumLang),
+		api.WithNearDuplicateReuse(*dupThreshold),
+	}
+	var dbOpts []inmem.ArticleOption
+	if *timeIDs {
+		dbOpts = append(dbOpts, inmem.WithIDGenerator(ids.NewTimeOrdered()))
+	}
+	switch *contentIDs {
+	case "":
+	case "addr":
+		dbOpts = append(dbOpts, inmem.WithProvidedIDs())
+		opts = append(opts, api.WithContentIDs(article.AddrID))
+	case "addr+published":
+		// Articles published at the same addr on different dates are different
+		// articles, so they must not be rejected as duplicates.
+		dbOpts = append(dbOpts, inmem.WithProvidedIDs(), inmem.WithDuplicatePolicy(db.DuplicateKeepBoth))
+		opts = append(opts, api.WithContentIDs(article.AddrPublishedID))
+	default:
+		return fmt.Errorf("unknown content ids %q, want addr or addr+published", *contentIDs)
+	}
+	if *synonymsFile != "" {
+		synonyms, err := readSynonyms(*synonymsFile)
+		if err != nil {
+			return err
+		}
+		opts = append(opts, api.WithSynonyms(synonyms))
+	}
+	if len(dbOpts) > 0 {
+		opts = append(opts, api.WithDB(inmem.NewArticle(dbOpts...)))
+	}
+	if *openAIKey != "" {
+		client := openai.NewClient(*openAIKey, log.With("name", "openAI"), openai.WithEntityTypes(types...))
+		opts = append(opts, api.WithNamedEntityRecognizer(client))
+		if *summarize {
+			opts = append(opts, api.WithSummarizer(client))
+		}
+		if *embed == "openai" {
+			opts = append(opts, api.WithEmbedder(client))
+		}
+	}
+	switch *embed {
+	case "", "openai":
+	case "ngram":
+		opts = append(opts, api.WithEmbedder(ngram.NewClient(ngram.DefaultDim)))
+	default:
+		return fmt.Errorf("unknown embedder %q, want ngram or openai", *embed)
+	}
+	switch *vectorIndex {
+	case "flat":
+	case "hnsw":
+		opts = append(opts, api.WithVectorIndex(hnsw.New(
+			hnsw.WithM(*hnswM),
+			hnsw.WithEfConstruction(*hnswEfConstruction),
+			hnsw.WithEfSearch(*hnswEfSearch),
+		)))
+	default:
+		return fmt.Errorf("unknown vector index %q, want flat or hnsw", *vectorIndex)
+	}
+
+	a, err := api.New(log.With("name", "api"), opts...)
+	if err != nil {
+		return err
+	}
+
+	if *feedsFile != "" {
+		feeds, err := readFeeds(*feedsFile)
+		if err != nil {
+			return err
+		}
+		pollerOpts := make([]feed.PollerOption, 0, len(feeds))
+		for _, f := range feeds {
+			pollerOpts = append(pollerOpts, feed.WithFeed(f))
+		}
+		pollerOpts = append(pollerOpts, feed.WithHTTPClient(client))
+		poller := feed.NewPoller(a.Adder(), log.With("name", "feed"), pollerOpts...)
+		go func() {
+			err := poller.Run(ctx)
+			log.Info("stopped polling feeds", "method", "serve", "err", err)
+		}()
+	}
+
+	if *sitemapRoot != "" {
+		backfillOpts := []sitemap.Option{sitemap.WithHTTPClient(client)}
+		if *sitemapProgress != "" {
+			backfillOpts = append(backfillOpts, sitemap.WithProgressStore(sitemap.NewFileStore(*sitemapProgress)))
+		}
+		backfill := sitemap.New(fetcher, a.Adder(), log.With("name", "sitemap"), backfillOpts...)
+		go func() {
+			added, err := backfill.Run(ctx, *sitemapRoot)
+			log.Info("stopped sitemap backfill", "method", "serve", "added", added, "err", err)
+		}()
+	}
+
+	svr := &http.Server{
+		Addr:              *addr,
+		Handler:           a,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		log.Info("start server", "method", "serve", "addr", *addr)
+		errs <- svr.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+	}
+
+	log.Info("shutdown server", "method", "serve")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	err = svr.Shutdown(shutdownCtx)
+	if err != nil {
+		return err
+	}
+	if err := <-errs; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// readSynonyms reads the synonyms of the file, see query.ParseSynonyms.
+func readSynonyms(name string) (*query.Synonyms, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	synonyms, err := query.ParseSynonyms(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s, %w", name, err)
+	}
+	return synonyms, nil
+}
+
+// readFeeds reads the feeds of the file, see feed.ParseFeeds.
+func readFeeds(name string) ([]feed.Feed, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	feeds, err := feed.ParseFeeds(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s, %w", name, err)
+	}
+	return feeds, nil
+}
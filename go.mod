@@ -4,5 +4,7 @@ go 1.21
 
 require (
 	github.com/google/uuid v1.3.1
+	golang.org/x/net v0.17.0
 	golang.org/x/sync v0.3.0
+	golang.org/x/text v0.13.0
 )
@@ -4,18 +4,57 @@ import (
 	"context"
 	"errors"
 	"log/slog"
+	"net/url"
+	"time"
 
 	"golang.org/x/sync/errgroup"
 
+	"github.com/Br0ce/articleDB/pkg/analysis"
 	"github.com/Br0ce/articleDB/pkg/article"
+	"github.com/Br0ce/articleDB/pkg/db"
+	"github.com/Br0ce/articleDB/pkg/simhash"
+	"github.com/Br0ce/articleDB/pkg/validate"
+	"github.com/Br0ce/articleDB/pkg/vector"
 )
 
+// ErrUpdateUnsupported is returned by Update, if the db can not update articles.
+var ErrUpdateUnsupported = errors.New("update not supported")
+
+// keywordCount is the number of keywords extracted from an article.
+const keywordCount = 10
+
+// Summarizer summarizes a text in the given language. The language is
+// article.LanguageUnknown, if it could not be detected.
 type Summarizer interface {
-	Summarize(ctx context.Context, text string) (string, error)
+	Summarize(ctx context.Context, text string, lang article.Language) (string, error)
 }
 
+// NamedEntityRecognizer finds the named entities of a text in the given language.
+// The language is article.LanguageUnknown, if it could not be detected.
 type NamedEntityRecognizer interface {
-	NER(ctx context.Context, text string) (article.NER, error)
+	NER(ctx context.Context, text string, lang article.Language) (article.NER, error)
+}
+
+// Updater replaces a stored article, e.g. the inmem.Article.
+type Updater interface {
+	Update(ctx context.Context, ar article.Article) error
+}
+
+// AddrFinder finds a stored article by its canonical addr, e.g. the inmem.Article.
+type AddrFinder interface {
+	GetByAddr(ctx context.Context, addr url.URL) (article.Article, error)
+}
+
+// VectorIndex indexes the embeddings of added articles, e.g. a vector.Flat.
+type VectorIndex interface {
+	Add(id string, vec []float32) error
+	Remove(id string)
+}
+
+// NearDuplicateFinder finds the canonical article of the near-duplicates of
+// a fingerprint.
+type NearDuplicateFinder interface {
+	Canonical(ctx context.Context, fingerprint uint64, threshold float64) (article.Article, bool, error)
 }
 
 type Adder struct {
@@ -23,12 +62,35 @@ type Adder struct {
 	sum Summarizer
 	db  article.DB
 	log *slog.Logger
+	// dup is optional. If set, the features of a near-identical article are reused
+	// instead of extracting them again.
+	dup          NearDuplicateFinder
+	dupThreshold float64
+	// emb and vec are optional. If emb is set, an embedding is computed for every
+	// article. If vec is set as well, the embedding is added to the index.
+	emb vector.Embedder
+	vec VectorIndex
+	// val validates the normalized article before the features are extracted. New
+	// sets a validator with the default limits.
+	val *validate.Validator
+	// rev is optional. If set, every added article and every change is recorded as
+	// a revision.
+	rev article.RevisionStore
+	// contentID is optional. If set, the ID of an added article is derived from its
+	// content instead of being generated by the db.
+	contentID article.IDFunc
+	// summaryLang is optional. If set, summaries are written in this language instead
+	// of the language of the article.
+	summaryLang article.Language
+	// skipEnrichment stores articles without running the extractors, e.g. for a
+	// fast import.
+	skipEnrichment bool
 }
 
 type AdderOption func(a *Adder)
 
 func New(opts ...AdderOption) (*Adder, error) {
-	adder := &Adder{}
+	adder := &Adder{val: validate.New()}
 
 	for _, opt := range opts {
 		opt(adder)
@@ -61,6 +123,18 @@ func WithNamedEntityRecognizer(ner NamedEntityRecognizer) AdderOption {
 	}
 }
 
+func WithEmbedder(emb vector.Embedder) AdderOption {
+	return func(a *Adder) {
+		a.emb = emb
+	}
+}
+
+func WithVectorIndex(vec VectorIndex) AdderOption {
+	return func(a *Adder) {
+		a.vec = vec
+	}
+}
+
 func WithDB(db article.DB) AdderOption {
 	return func(a *Adder) {
 		a.db = db
@@ -73,29 +147,375 @@ func WithLogger(log *slog.Logger) AdderOption {
 	}
 }
 
+// WithValidator sets the validator of the added articles. Without it, a validator
+// with the default limits is used.
+func WithValidator(val *validate.Validator) AdderOption {
+	return func(a *Adder) {
+		a.val = val
+	}
+}
+
+// WithRevisions records a revision for every added article and every change of an
+// article. An added article with the canonical addr of a stored article is treated
+// as a new version of the stored article, if the db implements AddrFinder and
+// Updater. It is only rejected with db.ErrAlreadyExists, if nothing changed.
+func WithRevisions(rev article.RevisionStore) AdderOption {
+	return func(a *Adder) {
+		a.rev = rev
+	}
+}
+
+// WithContentIDs derives the ID of every added article with fn, e.g. with
+// article.AddrID, so adding the same article again yields the same ID, even on
+// another replica. The db must keep the provided IDs, e.g. an inmem.Article with
+// inmem.WithProvidedIDs. Adding a stored article again returns its ID along with
+// db.ErrAlreadyExists, without extracting the features. With revisions, an added
+// article with the ID of a stored article is treated as a new version of the stored
+// article.
+func WithContentIDs(fn article.IDFunc) AdderOption {
+	return func(a *Adder) {
+		a.contentID = fn
+	}
+}
+
+// WithSummaryLanguage requests the summaries in lang, e.g. article.English, instead
+// of the language of the article.
+func WithSummaryLanguage(lang article.Language) AdderOption {
+	return func(a *Adder) {
+		a.summaryLang = lang
+	}
+}
+
+// WithoutEnrichment stores the articles without summary, named entities and
+// embedding. The articles are still normalized, validated, fingerprinted and
+// recorded as revisions, and their keywords are extracted locally.
+func WithoutEnrichment() AdderOption {
+	return func(a *Adder) {
+		a.skipEnrichment = true
+	}
+}
+
+// WithNearDuplicateReuse skips the feature extraction for an article, that is a
+// near-duplicate of an already stored article with a similarity of at least threshold.
+// The features are copied from the canonical article of the near-duplicates instead.
+func WithNearDuplicateReuse(dup NearDuplicateFinder, threshold float64) AdderOption {
+	return func(a *Adder) {
+		a.dup = dup
+		a.dupThreshold = threshold
+	}
+}
+
+// Add normalizes and validates the article, extracts its features and stores it.
+// The creation time is set to now and the language is detected, unless they are
+// already set, e.g. by an import.
+// An invalid article is rejected with a *validate.Error before any feature is
+// extracted. With revisions, a new version of a stored article updates the stored
+// article and its ID is returned. An article, that is already stored unchanged, is
+// rejected with db.ErrAlreadyExists before any feature is extracted. The ID of the
+// stored article is returned along with the error, if it is known.
 func (a *Adder) Add(ctx context.Context, ar article.Article) (string, error) {
 	a.log.Info("add article", "method", "Add", "articleID", ar.ID)
 
-	ar, err := a.addFeatures(ctx, ar)
+	if a.val != nil {
+		ar = validate.Normalize(ar)
+		err := a.val.Validate(ar)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if a.contentID != nil {
+		ar.ID = a.contentID(ar)
+	}
+
+	present, ok, err := a.presentVersion(ctx, ar)
+	if err != nil {
+		return "", err
+	}
+	if ok {
+		ar.ID = present.ID
+		ar.Addr = present.Addr
+		diff, err := a.update(ctx, present, ar)
+		if err != nil {
+			return "", err
+		}
+		if diff.Empty() {
+			return present.ID, db.ErrAlreadyExists
+		}
+		return present.ID, nil
+	}
+
+	// Without revisions, a stored article with the content ID is not extracted again.
+	if a.contentID != nil && ar.ID != "" {
+		_, err := a.db.Get(ctx, ar.ID)
+		if err == nil {
+			a.log.Info("article already present", "method", "Add", "articleID", ar.ID)
+			return ar.ID, db.ErrAlreadyExists
+		}
+		if !errors.Is(err, db.ErrNotFound) {
+			return "", err
+		}
+	}
+
+	ar.Fingerprint = simhash.Fingerprint(ar.Body)
+	ar.Language = language(ar)
+
+	reused, err := a.reuseFeatures(ctx, &ar)
 	if err != nil {
 		return "", err
 	}
 
+	switch {
+	case reused:
+	case a.skipEnrichment:
+		ar.Keywords = keywords(ar)
+	default:
+		ar, err = a.addFeatures(ctx, ar)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if ar.Created.IsZero() {
+		ar.Created = time.Now().UTC()
+	}
+
 	id, err := a.db.Add(ctx, ar)
 	if err != nil {
 		return "", err
 	}
 
+	if a.vec != nil && len(ar.Embedding) > 0 {
+		err = a.vec.Add(id, ar.Embedding)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	ar.ID = id
+	a.addRevision(ctx, ar, article.Diff{})
+
 	return id, nil
 }
 
+// presentVersion returns the stored article with the canonical addr of ar, if
+// revisions are recorded and the db supports updates. With content IDs, the stored
+// article with the content ID of ar is returned instead.
+func (a *Adder) presentVersion(ctx context.Context, ar article.Article) (article.Article, bool, error) {
+	if a.rev == nil {
+		return article.Article{}, false, nil
+	}
+	if _, ok := a.db.(Updater); !ok {
+		return article.Article{}, false, nil
+	}
+
+	var present article.Article
+	var err error
+	switch finder, ok := a.db.(AddrFinder); {
+	case a.contentID != nil && ar.ID != "":
+		present, err = a.db.Get(ctx, ar.ID)
+	case ok:
+		present, err = finder.GetByAddr(ctx, ar.Addr)
+	default:
+		return article.Article{}, false, nil
+	}
+	if errors.Is(err, db.ErrNotFound) {
+		return article.Article{}, false, nil
+	}
+	if err != nil {
+		return article.Article{}, false, err
+	}
+
+	return present, true, nil
+}
+
+// addRevision records ar as new revision. The article is already stored, so a
+// failure is only logged.
+func (a *Adder) addRevision(ctx context.Context, ar article.Article, diff article.Diff) {
+	if a.rev == nil {
+		return
+	}
+
+	rev, err := a.rev.Add(ctx, article.Revision{ArticleID: ar.ID, Article: ar, Diff: diff})
+	if err != nil {
+		a.log.Error("could not add revision", "method", "addRevision", "articleID", ar.ID, "err", err.Error())
+		return
+	}
+	a.log.Info("revision added", "method", "addRevision", "articleID", ar.ID, "revision", rev.Number)
+}
+
+// Update applies the original fields of ar to the stored article with the ID of ar
+// and returns the changes. The features are only extracted again, if the body or
+// the detected language changed. A changed title renews the keywords. If nothing
+// changed, the stored article is left untouched. Update returns
+// ErrUpdateUnsupported, if the db does not implement Updater.
+func (a *Adder) Update(ctx context.Context, ar article.Article) (article.Diff, error) {
+	a.log.Info("update article", "method", "Update", "articleID", ar.ID)
+
+	if _, ok := a.db.(Updater); !ok {
+		return article.Diff{}, ErrUpdateUnsupported
+	}
+
+	if a.val != nil {
+		ar = validate.Normalize(ar)
+		err := a.val.Validate(ar)
+		if err != nil {
+			return article.Diff{}, err
+		}
+	}
+
+	old, err := a.db.Get(ctx, ar.ID)
+	if err != nil {
+		return article.Diff{}, err
+	}
+
+	return a.update(ctx, old, ar)
+}
+
+// update applies the original fields of ar to the stored article old. The db must
+// implement Updater.
+func (a *Adder) update(ctx context.Context, old, ar article.Article) (article.Diff, error) {
+	diff := old.Diff(ar)
+	if diff.Empty() {
+		a.log.Info("article unchanged", "method", "update", "articleID", old.ID)
+		return diff, nil
+	}
+
+	updated := old
+	updated.Title = ar.Title
+	updated.Addr = ar.Addr
+	updated.Author = ar.Author
+	updated.Published = ar.Published
+	updated.Body = ar.Body
+	updated.Updated = time.Now().UTC()
+
+	// The title is part of the language detection and the keywords. The other
+	// features are extracted from the body in its language.
+	retitled := diff.Changed(article.FieldTitle)
+	if retitled || diff.Changed(article.FieldBody) {
+		// A short text may not reveal its language, so the stored one is kept.
+		if lang := language(ar); lang != article.LanguageUnknown {
+			updated.Language = lang
+		}
+	}
+	reenrich := diff.Changed(article.FieldBody) || updated.Language != old.Language
+	a.log.Info("article changed", "method", "update", "articleID", old.ID,
+		"changes", len(diff.Changes), "reenrich", reenrich)
+
+	var err error
+	if retitled && !reenrich {
+		updated.Keywords = keywords(updated)
+	}
+	if reenrich {
+		updated.Fingerprint = simhash.Fingerprint(updated.Body)
+		if a.skipEnrichment {
+			// The features of the old body do not describe the new one.
+			updated.Keywords = keywords(updated)
+			updated.Summary = ""
+			updated.NER = article.NER{}
+			updated.Embedding = nil
+		} else {
+			updated, err = a.addFeatures(ctx, updated)
+			if err != nil {
+				return article.Diff{}, err
+			}
+		}
+	}
+
+	err = a.db.(Updater).Update(ctx, updated)
+	if err != nil {
+		return article.Diff{}, err
+	}
+
+	if reenrich && a.vec != nil {
+		// The embedding of the old body must not be found anymore, even if the
+		// updated article has none.
+		a.vec.Remove(updated.ID)
+		if len(updated.Embedding) > 0 {
+			err = a.vec.Add(updated.ID, updated.Embedding)
+			if err != nil {
+				return article.Diff{}, err
+			}
+		}
+	}
+
+	a.addRevision(ctx, updated, diff)
+
+	return diff, nil
+}
+
+// reuseFeatures copies the features of the canonical near-duplicate into ar.
+// It reports whether features have been copied.
+func (a *Adder) reuseFeatures(ctx context.Context, ar *article.Article) (bool, error) {
+	if a.dup == nil || ar.Fingerprint == 0 {
+		return false, nil
+	}
+
+	canonical, ok, err := a.dup.Canonical(ctx, ar.Fingerprint, a.dupThreshold)
+	if err != nil {
+		return false, err
+	}
+	if !ok {
+		return false, nil
+	}
+
+	a.log.Info("reuse features of near-duplicate", "method", "reuseFeatures",
+		"articleID", ar.ID,
+		"canonicalID", canonical.ID)
+
+	ar.Summary = canonical.Summary
+	ar.Keywords = canonical.Keywords
+	ar.NER = relocate(canonical.NER, ar.Body)
+	if a.emb != nil {
+		ar.Embedding = canonical.Embedding
+	}
+
+	return true, nil
+}
+
+// relocate returns the entities of ner with their mentions located in body. The
+// mentions of a near-duplicate are at other offsets than in the canonical body.
+func relocate(ner article.NER, body string) article.NER {
+	entities := make([]article.Entity, 0, len(ner.Entities))
+	for _, e := range ner.Entities {
+		entities = append(entities, article.Entity{Name: e.Name, Type: e.Type, Confidence: e.Confidence})
+	}
+	return article.NewNER(body, entities...)
+}
+
+// language returns the language of the article. Unless it is set, it is detected
+// from the title and the body.
+func language(ar article.Article) article.Language {
+	if ar.Language != article.LanguageUnknown {
+		return ar.Language
+	}
+	return analysis.Detect(ar.Title + "\n" + ar.Body)
+}
+
+// keywords extracts the keywords of the article in its language.
+func keywords(ar article.Article) []string {
+	return analysis.For(ar.Language).Keywords(ar.Title+"\n"+ar.Body, keywordCount)
+}
+
+// addFeatures extracts the features of the article in its language. The keywords
+// are extracted locally, the other features by the extractors.
 func (a *Adder) addFeatures(ctx context.Context, ar article.Article) (article.Article, error) {
-	a.log.Info("extract features and add to article", "method", "addFeatures", "articleID", ar.ID)
+	a.log.Info("extract features and add to article", "method", "addFeatures",
+		"articleID", ar.ID,
+		"lang", ar.Language)
+
+	ar.Keywords = keywords(ar)
+
+	sumLang := ar.Language
+	if a.summaryLang != article.LanguageUnknown {
+		sumLang = a.summaryLang
+	}
+
 	g, ctx := errgroup.WithContext(ctx)
 
 	a.log.Debug("start extracting features ...", "method", "addFeatures", "articleID", ar.ID)
 	g.Go(func() error {
-		sum, err := a.sum.Summarize(ctx, ar.Body)
+		sum, err := a.sum.Summarize(ctx, ar.Body, sumLang)
 		if err != nil {
 			return err
 		}
@@ -104,7 +524,7 @@ func (a *Adder) addFeatures(ctx context.Context, ar article.Article) (article.Ar
 	})
 
 	g.Go(func() error {
-		ner, err := a.ner.NER(ctx, ar.Body)
+		ner, err := a.ner.NER(ctx, ar.Body, ar.Language)
 		if err != nil {
 			return err
 		}
@@ -112,6 +532,17 @@ func (a *Adder) addFeatures(ctx context.Context, ar article.Article) (article.Ar
 		return nil
 	})
 
+	if a.emb != nil {
+		g.Go(func() error {
+			emb, err := a.emb.Embed(ctx, ar.Body)
+			if err != nil {
+				return err
+			}
+			ar.Embedding = emb
+			return nil
+		})
+	}
+
 	a.log.Debug("wait for feature extraction to finish", "method", "addFeatures", "articleID", ar.ID)
 	if err := g.Wait(); err != nil {
 		return article.Article{}, err
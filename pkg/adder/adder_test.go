@@ -4,13 +4,20 @@ import (
 	"context"
 	"errors"
 	"log/slog"
+	"net/url"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/Br0ce/articleDB/pkg/article"
+	"github.com/Br0ce/articleDB/pkg/db"
+	"github.com/Br0ce/articleDB/pkg/db/inmem"
 	"github.com/Br0ce/articleDB/pkg/extract/noop"
 	"github.com/Br0ce/articleDB/pkg/logger"
 	"github.com/Br0ce/articleDB/pkg/mock"
+	"github.com/Br0ce/articleDB/pkg/validate"
+	"github.com/Br0ce/articleDB/pkg/vector"
 )
 
 func TestAdder_Add(t *testing.T) {
@@ -18,8 +25,8 @@ func TestAdder_Add(t *testing.T) {
 
 	type fields struct {
 		log   *slog.Logger
-		sumFn func(ctx context.Context, text string) (string, error)
-		nerFn func(ctx context.Context, text string) (article.NER, error)
+		sumFn func(ctx context.Context, text string, lang article.Language) (string, error)
+		nerFn func(ctx context.Context, text string, lang article.Language) (article.NER, error)
 		addFn func(ctx context.Context, ar article.Article) (string, error)
 	}
 
@@ -42,13 +49,13 @@ func TestAdder_Add(t *testing.T) {
 		{
 			name: "pass",
 			fields: fields{
-				sumFn: func(ctx context.Context, txt string) (string, error) {
+				sumFn: func(ctx context.Context, txt string, lang article.Language) (string, error) {
 					if txt != body {
 						t.Fatalf("summarizer text not equal, want %s got %s", body, txt)
 					}
 					return "Summary of text.", nil
 				},
-				nerFn: func(ctx context.Context, txt string) (article.NER, error) {
+				nerFn: func(ctx context.Context, txt string, lang article.Language) (article.NER, error) {
 					if txt != body {
 						t.Fatalf("ner text not equal, want %s got %s", body, txt)
 					}
@@ -71,10 +78,10 @@ func TestAdder_Add(t *testing.T) {
 		{
 			name: "summarizer error",
 			fields: fields{
-				sumFn: func(ctx context.Context, txt string) (string, error) {
+				sumFn: func(ctx context.Context, txt string, lang article.Language) (string, error) {
 					return "", errors.New("summarizer error")
 				},
-				nerFn: func(ctx context.Context, txt string) (article.NER, error) {
+				nerFn: func(ctx context.Context, txt string, lang article.Language) (article.NER, error) {
 					return article.NER{}, nil
 				},
 				addFn: func(ctx context.Context, ar article.Article) (string, error) {
@@ -92,10 +99,10 @@ func TestAdder_Add(t *testing.T) {
 		{
 			name: "ner error",
 			fields: fields{
-				sumFn: func(ctx context.Context, txt string) (string, error) {
+				sumFn: func(ctx context.Context, txt string, lang article.Language) (string, error) {
 					return "Summary of text.", nil
 				},
-				nerFn: func(ctx context.Context, txt string) (article.NER, error) {
+				nerFn: func(ctx context.Context, txt string, lang article.Language) (article.NER, error) {
 					return article.NER{}, errors.New("ner error")
 				},
 				addFn: func(ctx context.Context, ar article.Article) (string, error) {
@@ -115,10 +122,10 @@ func TestAdder_Add(t *testing.T) {
 		{
 			name: "db error",
 			fields: fields{
-				sumFn: func(ctx context.Context, txt string) (string, error) {
+				sumFn: func(ctx context.Context, txt string, lang article.Language) (string, error) {
 					return "Summary of text.", nil
 				},
-				nerFn: func(ctx context.Context, txt string) (article.NER, error) {
+				nerFn: func(ctx context.Context, txt string, lang article.Language) (article.NER, error) {
 					return article.NER{}, nil
 				},
 				addFn: func(ctx context.Context, ar article.Article) (string, error) {
@@ -169,11 +176,748 @@ func TestAdder_Add(t *testing.T) {
 	}
 }
 
+func TestAdder_Add_nearDuplicateReuse(t *testing.T) {
+	t.Parallel()
+
+	log := logger.NewTest(false)
+	body := "This is a test body of a wire story."
+	canonical := article.Article{
+		ID:       "canonical",
+		Summary:  "Summary of canonical.",
+		Keywords: []string{"wire"},
+		NER:      article.NER{Entities: []article.Entity{{Name: "Reuters", Type: article.Organisation, Count: 1}}},
+	}
+
+	tests := []struct {
+		name        string
+		canonicalFn func(ctx context.Context, fingerprint uint64, threshold float64) (article.Article, bool, error)
+		wantExtract bool
+		wantSummary string
+		wantErr     bool
+	}{
+		{
+			name: "reuse",
+			canonicalFn: func(ctx context.Context, fingerprint uint64, threshold float64) (article.Article, bool, error) {
+				return canonical, true, nil
+			},
+			wantExtract: false,
+			wantSummary: canonical.Summary,
+		},
+		{
+			name: "no near-duplicate",
+			canonicalFn: func(ctx context.Context, fingerprint uint64, threshold float64) (article.Article, bool, error) {
+				return article.Article{}, false, nil
+			},
+			wantExtract: true,
+			wantSummary: "Summary of text.",
+		},
+		{
+			name: "finder error",
+			canonicalFn: func(ctx context.Context, fingerprint uint64, threshold float64) (article.Article, bool, error) {
+				return article.Article{}, false, errors.New("finder error")
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sum := &mock.Summarizer{SummarizeFn: func(ctx context.Context, text string, lang article.Language) (string, error) {
+				return "Summary of text.", nil
+			}}
+			ner := &mock.NER{NERFn: func(ctx context.Context, text string, lang article.Language) (article.NER, error) {
+				return article.NER{}, nil
+			}}
+			var stored article.Article
+			db := &mock.DB{AddFn: func(ctx context.Context, ar article.Article) (string, error) {
+				stored = ar
+				return "1234", nil
+			}}
+			dup := &mock.NearDuplicateFinder{CanonicalFn: tt.canonicalFn}
+
+			a := Adder{
+				sum:          sum,
+				ner:          ner,
+				db:           db,
+				log:          log,
+				dup:          dup,
+				dupThreshold: 0.9,
+			}
+
+			_, err := a.Add(context.TODO(), article.Article{Body: body})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Adder.Add() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if sum.SummarizerInvoked != tt.wantExtract || ner.NERInvoked != tt.wantExtract {
+				t.Errorf("extractors invoked = %v, want %v", sum.SummarizerInvoked, tt.wantExtract)
+			}
+			if stored.Summary != tt.wantSummary {
+				t.Errorf("summary = %v, want %v", stored.Summary, tt.wantSummary)
+			}
+			if stored.Fingerprint == 0 {
+				t.Error("fingerprint not set")
+			}
+		})
+	}
+
+	t.Run("mentions located in shifted body", func(t *testing.T) {
+		canonical := article.Article{
+			ID:   "canonical",
+			Body: "Reuters reports a story.",
+			NER: article.NER{Entities: []article.Entity{{
+				Name:       "Reuters",
+				Type:       article.Organisation,
+				Mentions:   []article.Mention{{Start: 0, End: 7}},
+				Count:      1,
+				Confidence: 0.8,
+			}}},
+		}
+		var stored article.Article
+		a := Adder{
+			sum: &mock.Summarizer{},
+			ner: &mock.NER{},
+			db: &mock.DB{AddFn: func(ctx context.Context, ar article.Article) (string, error) {
+				stored = ar
+				return "1234", nil
+			}},
+			log: log,
+			dup: &mock.NearDuplicateFinder{CanonicalFn: func(ctx context.Context, fingerprint uint64, threshold float64) (article.Article, bool, error) {
+				return canonical, true, nil
+			}},
+			dupThreshold: 0.9,
+		}
+
+		_, err := a.Add(context.TODO(), article.Article{Body: "Update: Reuters reports a story. Reuters again."})
+		if err != nil {
+			t.Fatalf("Adder.Add() error = %v", err)
+		}
+
+		want := []article.Entity{{
+			Name:       "Reuters",
+			Type:       article.Organisation,
+			Mentions:   []article.Mention{{Start: 8, End: 15}, {Start: 33, End: 40}},
+			Count:      2,
+			Confidence: 0.8,
+		}}
+		if !reflect.DeepEqual(stored.NER.Entities, want) {
+			t.Errorf("entities = %+v, want %+v", stored.NER.Entities, want)
+		}
+	})
+}
+
+func TestAdder_Add_embedding(t *testing.T) {
+	t.Parallel()
+
+	embedding := []float32{0.6, 0.8}
+	sum := &mock.Summarizer{SummarizeFn: func(ctx context.Context, text string, lang article.Language) (string, error) {
+		return "Summary of text.", nil
+	}}
+	ner := &mock.NER{NERFn: func(ctx context.Context, text string, lang article.Language) (article.NER, error) {
+		return article.NER{}, nil
+	}}
+	emb := &mock.Embedder{EmbedFn: func(ctx context.Context, text string) ([]float32, error) {
+		return embedding, nil
+	}}
+	var stored article.Article
+	db := &mock.DB{AddFn: func(ctx context.Context, ar article.Article) (string, error) {
+		stored = ar
+		return "1234", nil
+	}}
+	idx := vector.NewFlat()
+
+	a := Adder{
+		sum: sum,
+		ner: ner,
+		emb: emb,
+		vec: idx,
+		db:  db,
+		log: logger.NewTest(false),
+	}
+
+	id, err := a.Add(context.TODO(), article.Article{Body: "This is a test body."})
+	if err != nil {
+		t.Fatalf("Adder.Add() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(stored.Embedding, embedding) {
+		t.Errorf("embedding = %v, want %v", stored.Embedding, embedding)
+	}
+
+	hits, err := idx.Search(embedding, 1)
+	if err != nil {
+		t.Fatalf("could not search index, %s", err.Error())
+	}
+	if len(hits) != 1 || hits[0].ID != id {
+		t.Errorf("index hits = %v, want %v", hits, id)
+	}
+}
+
+func TestAdder_Update_embedding(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.TODO()
+	emb := &mock.Embedder{EmbedFn: func(ctx context.Context, text string) ([]float32, error) {
+		if strings.Contains(text, "snow") {
+			return []float32{0, 1}, nil
+		}
+		return []float32{1, 0}, nil
+	}}
+	store := inmem.NewArticle()
+	idx := vector.NewFlat()
+	newAdder := func(opts ...AdderOption) *Adder {
+		opts = append(opts, WithSummarizer(noop.Client{}), WithNamedEntityRecognizer(noop.Client{}),
+			WithEmbedder(emb), WithVectorIndex(idx), WithDB(store), WithLogger(logger.NewTest(false)))
+		a, err := New(opts...)
+		if err != nil {
+			t.Fatalf("could not create adder, %s", err.Error())
+		}
+		return a
+	}
+	a := newAdder()
+
+	addr := url.URL{Scheme: "https", Host: "news.example.com", Path: "/storm"}
+	id, err := a.Add(ctx, article.Article{Title: "Storm", Addr: addr, Body: "Heavy rain."})
+	if err != nil {
+		t.Fatalf("Adder.Add() error = %v", err)
+	}
+
+	// The embedding of the new body replaces the old one.
+	_, err = a.Update(ctx, article.Article{ID: id, Title: "Storm", Addr: addr, Body: "Heavy snow."})
+	if err != nil {
+		t.Fatalf("Adder.Update() error = %v", err)
+	}
+	hits, err := idx.Search([]float32{0, 1}, 2)
+	if err != nil {
+		t.Fatalf("could not search index, %s", err.Error())
+	}
+	if len(hits) != 1 || hits[0].ID != id || hits[0].Score < 0.99 {
+		t.Errorf("index hits = %v, want %v with the new embedding", hits, id)
+	}
+
+	// Without enrichment, the updated article has no embedding anymore.
+	_, err = newAdder(WithoutEnrichment()).Update(ctx, article.Article{ID: id, Title: "Storm", Addr: addr, Body: "Heavy hail."})
+	if err != nil {
+		t.Fatalf("Adder.Update() error = %v", err)
+	}
+	if idx.Len() != 0 {
+		t.Errorf("index len = %v, want 0", idx.Len())
+	}
+}
+
+func TestAdder_Add_withoutEnrichment(t *testing.T) {
+	t.Parallel()
+
+	sum := &mock.Summarizer{}
+	ner := &mock.NER{}
+	db := inmem.NewArticle()
+	rev := inmem.NewRevision()
+
+	a, err := New(WithSummarizer(sum), WithNamedEntityRecognizer(ner), WithDB(db), WithRevisions(rev),
+		WithLogger(logger.NewTest(false)), WithoutEnrichment())
+	if err != nil {
+		t.Fatalf("could not create adder, %s", err.Error())
+	}
+
+	addr := url.URL{Scheme: "https", Host: "news.example.com", Path: "/storm"}
+	body := "Heavy rain and storms are expected over the weekend in the north of the country."
+	id, err := a.Add(context.TODO(), article.Article{Title: " Storm warning ", Addr: addr, Body: body})
+	if err != nil {
+		t.Fatalf("Adder.Add() error = %v", err)
+	}
+
+	if sum.SummarizerInvoked || ner.NERInvoked {
+		t.Error("extractors invoked without enrichment")
+	}
+	stored, err := db.Get(context.TODO(), id)
+	if err != nil {
+		t.Fatalf("could not get article, %s", err.Error())
+	}
+	if stored.Title != "Storm warning" || stored.Created.IsZero() || stored.Fingerprint == 0 ||
+		stored.Language != article.English || len(stored.Keywords) == 0 {
+		t.Errorf("stored = %+v, want normalized article with created, fingerprint, language and keywords", stored)
+	}
+	if revs, err := rev.List(context.TODO(), id); err != nil || len(revs) != 1 {
+		t.Errorf("revisions = %v, %v, want one", revs, err)
+	}
+
+	_, err = a.Add(context.TODO(), article.Article{Title: "Storm warning", Addr: url.URL{Path: "/storm"}, Body: body})
+	if !errors.Is(err, validate.ErrInvalidArticle) {
+		t.Errorf("Adder.Add() error = %v, want %v", err, validate.ErrInvalidArticle)
+	}
+}
+
+func TestAdder_Add_validation(t *testing.T) {
+	t.Parallel()
+
+	addr := url.URL{Scheme: "https", Host: "news.example.com", Path: "/storm"}
+
+	tests := []struct {
+		name      string
+		ar        article.Article
+		wantErr   error
+		wantTitle string
+		wantBody  string
+	}{
+		{
+			name:      "normalized",
+			ar:        article.Article{Title: "  Storm &amp; rain ", Addr: addr, Body: "<p>Heavy  rain.</p>\n\n\n<p>Floods.</p>"},
+			wantTitle: "Storm & rain",
+			wantBody:  "Heavy rain.\n\nFloods.",
+		},
+		{
+			name:    "empty body",
+			ar:      article.Article{Title: "Storm", Addr: addr, Body: " <br> "},
+			wantErr: validate.ErrInvalidArticle,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sum := &mock.Summarizer{SummarizeFn: func(ctx context.Context, text string, lang article.Language) (string, error) {
+				return "Summary of text.", nil
+			}}
+			ner := &mock.NER{NERFn: func(ctx context.Context, text string, lang article.Language) (article.NER, error) {
+				return article.NER{}, nil
+			}}
+			var stored article.Article
+			db := &mock.DB{AddFn: func(ctx context.Context, ar article.Article) (string, error) {
+				stored = ar
+				return "1234", nil
+			}}
+
+			a, err := New(WithSummarizer(sum), WithNamedEntityRecognizer(ner), WithDB(db), WithLogger(logger.NewTest(false)))
+			if err != nil {
+				t.Fatalf("could not create adder, %s", err.Error())
+			}
+
+			_, err = a.Add(context.TODO(), tt.ar)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Adder.Add() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				if sum.SummarizerInvoked || ner.NERInvoked {
+					t.Error("extractors invoked for invalid article")
+				}
+				return
+			}
+
+			if stored.Title != tt.wantTitle || stored.Body != tt.wantBody {
+				t.Errorf("stored = %q, %q, want %q, %q", stored.Title, stored.Body, tt.wantTitle, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestAdder_Update(t *testing.T) {
+	t.Parallel()
+
+	stored := article.Article{
+		ID:       "1234",
+		Title:    "Storm warning",
+		Addr:     url.URL{Scheme: "https", Host: "weather.example.com", Path: "/storm"},
+		Body:     "Heavy rain is expected.",
+		Language: article.English,
+		Summary:  "Rain.",
+		Keywords: []string{"weather"},
+	}
+
+	tests := []struct {
+		name         string
+		modify       func(ar *article.Article)
+		wantChanges  []article.Field
+		wantExtract  bool
+		wantUpdate   bool
+		wantKeywords []string
+	}{
+		{
+			name:   "unchanged",
+			modify: func(ar *article.Article) { ar.Body = " Heavy rain is  expected. " },
+		},
+		{
+			name:         "title",
+			modify:       func(ar *article.Article) { ar.Title = "Storm warning extended" },
+			wantChanges:  []article.Field{article.FieldTitle},
+			wantUpdate:   true,
+			wantKeywords: []string{"storm", "warning", "extended", "heavy", "rain", "expected"},
+		},
+		{
+			name:         "body",
+			modify:       func(ar *article.Article) { ar.Body = "Heavy snow is expected." },
+			wantChanges:  []article.Field{article.FieldBody},
+			wantExtract:  true,
+			wantUpdate:   true,
+			wantKeywords: []string{"storm", "warning", "heavy", "snow", "expected"},
+		},
+		{
+			name: "title in another language",
+			modify: func(ar *article.Article) {
+				ar.Title = "Die Unwetterwarnung für die Stadt und das ganze Land ist nicht aufgehoben"
+			},
+			wantChanges:  []article.Field{article.FieldTitle},
+			wantExtract:  true,
+			wantUpdate:   true,
+			wantKeywords: []string{"unwetterwarnung", "stadt", "ganze", "land", "aufgehoben", "heavy", "rain", "expected"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sum := &mock.Summarizer{SummarizeFn: func(ctx context.Context, text string, lang article.Language) (string, error) {
+				return "Snow.", nil
+			}}
+			ner := &mock.NER{NERFn: func(ctx context.Context, text string, lang article.Language) (article.NER, error) {
+				return article.NER{}, nil
+			}}
+			var updated article.Article
+			db := &mock.DB{
+				GetFn: func(ctx context.Context, id string) (article.Article, error) {
+					return stored, nil
+				},
+				UpdateFn: func(ctx context.Context, ar article.Article) error {
+					updated = ar
+					return nil
+				},
+			}
+
+			a, err := New(WithSummarizer(sum), WithNamedEntityRecognizer(ner), WithDB(db), WithLogger(logger.NewTest(false)))
+			if err != nil {
+				t.Fatalf("could not create adder, %s", err.Error())
+			}
+
+			ar := article.Article{ID: stored.ID, Title: stored.Title, Addr: stored.Addr, Body: stored.Body}
+			tt.modify(&ar)
+
+			diff, err := a.Update(context.TODO(), ar)
+			if err != nil {
+				t.Fatalf("Adder.Update() error = %v", err)
+			}
+
+			var changes []article.Field
+			for _, c := range diff.Changes {
+				changes = append(changes, c.Field)
+			}
+			if !reflect.DeepEqual(changes, tt.wantChanges) {
+				t.Errorf("Adder.Update() changes = %v, want %v", changes, tt.wantChanges)
+			}
+			if sum.SummarizerInvoked != tt.wantExtract || ner.NERInvoked != tt.wantExtract {
+				t.Errorf("extractors invoked = %v, want %v", sum.SummarizerInvoked, tt.wantExtract)
+			}
+			if db.UpdateInvoked != tt.wantUpdate {
+				t.Fatalf("db updated = %v, want %v", db.UpdateInvoked, tt.wantUpdate)
+			}
+			if !tt.wantUpdate {
+				return
+			}
+
+			if updated.Title != ar.Title || updated.Body != ar.Body || updated.Updated.IsZero() {
+				t.Errorf("updated = %+v", updated)
+			}
+			wantSummary := stored.Summary
+			if tt.wantExtract {
+				wantSummary = "Snow."
+			}
+			if updated.Summary != wantSummary || !reflect.DeepEqual(updated.Keywords, tt.wantKeywords) {
+				t.Errorf("updated features = %q, %v, want %q, %v", updated.Summary, updated.Keywords, wantSummary, tt.wantKeywords)
+			}
+		})
+	}
+}
+
+func TestAdder_Add_revisions(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.TODO()
+	store := inmem.NewArticle()
+	revs := inmem.NewRevision()
+	a, err := New(
+		WithSummarizer(noop.Client{}),
+		WithNamedEntityRecognizer(noop.Client{}),
+		WithDB(store),
+		WithRevisions(revs),
+		WithLogger(logger.NewTest(false)),
+	)
+	if err != nil {
+		t.Fatalf("could not create adder, %s", err.Error())
+	}
+
+	addr := url.URL{Scheme: "https", Host: "news.example.com", Path: "/storm"}
+	id, err := a.Add(ctx, article.Article{Title: "Storm", Addr: addr, Body: "Heavy rain."})
+	if err != nil {
+		t.Fatalf("Adder.Add() error = %v", err)
+	}
+
+	// The same version at a tracking addr is a duplicate.
+	tracked := addr
+	tracked.RawQuery = "utm_source=feed"
+	_, err = a.Add(ctx, article.Article{Title: "Storm", Addr: tracked, Body: "Heavy rain."})
+	if !errors.Is(err, db.ErrAlreadyExists) {
+		t.Fatalf("Adder.Add() error = %v, want %v", err, db.ErrAlreadyExists)
+	}
+
+	// An edited version updates the stored article.
+	got, err := a.Add(ctx, article.Article{Title: "Storm warning", Addr: tracked, Body: "Heavy rain."})
+	if err != nil {
+		t.Fatalf("Adder.Add() error = %v", err)
+	}
+	if got != id {
+		t.Errorf("Adder.Add() = %v, want %v", got, id)
+	}
+
+	_, err = a.Update(ctx, article.Article{ID: id, Title: "Storm warning", Addr: addr, Body: "Heavy snow."})
+	if err != nil {
+		t.Fatalf("Adder.Update() error = %v", err)
+	}
+
+	list, err := revs.List(ctx, id)
+	if err != nil {
+		t.Fatalf("could not list revisions, %s", err.Error())
+	}
+	var changes [][]article.Field
+	for _, rev := range list {
+		var fields []article.Field
+		for _, c := range rev.Diff.Changes {
+			fields = append(fields, c.Field)
+		}
+		changes = append(changes, fields)
+	}
+	want := [][]article.Field{nil, {article.FieldTitle}, {article.FieldBody}}
+	if !reflect.DeepEqual(changes, want) {
+		t.Errorf("revision changes = %v, want %v", changes, want)
+	}
+	if list[0].Article.Title != "Storm" || list[2].Article.Body != "Heavy snow." {
+		t.Errorf("revisions = %+v", list)
+	}
+
+	stored, err := store.Get(ctx, id)
+	if err != nil {
+		t.Fatalf("could not get article, %s", err.Error())
+	}
+	if stored.Addr != addr {
+		t.Errorf("stored addr = %v, want %v", stored.Addr.String(), addr.String())
+	}
+}
+
+func TestAdder_Add_contentIDs(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.TODO()
+	newAdder := func(store *inmem.Article) *Adder {
+		a, err := New(
+			WithSummarizer(noop.Client{}),
+			WithNamedEntityRecognizer(noop.Client{}),
+			WithDB(store),
+			WithRevisions(inmem.NewRevision()),
+			WithContentIDs(article.AddrPublishedID),
+			WithLogger(logger.NewTest(false)),
+		)
+		if err != nil {
+			t.Fatalf("could not create adder, %s", err.Error())
+		}
+		return a
+	}
+	store := inmem.NewArticle(inmem.WithProvidedIDs(), inmem.WithDuplicatePolicy(db.DuplicateKeepBoth))
+	a := newAdder(store)
+
+	addr := url.URL{Scheme: "https", Host: "news.example.com", Path: "/live"}
+	monday := time.Date(2023, 6, 5, 8, 0, 0, 0, time.UTC)
+	ar := article.Article{Title: "Live", Addr: addr, Published: monday, Body: "Morning."}
+
+	id, err := a.Add(ctx, ar)
+	if err != nil {
+		t.Fatalf("Adder.Add() error = %v", err)
+	}
+	if want := article.AddrPublishedID(ar); id != want {
+		t.Errorf("Adder.Add() = %v, want %v", id, want)
+	}
+
+	// Another replica derives the same ID.
+	replica, err := newAdder(inmem.NewArticle(inmem.WithProvidedIDs())).Add(ctx, ar)
+	if err != nil {
+		t.Fatalf("Adder.Add() error = %v", err)
+	}
+	if replica != id {
+		t.Errorf("Adder.Add() on replica = %v, want %v", replica, id)
+	}
+
+	got, err := a.Add(ctx, ar)
+	if !errors.Is(err, db.ErrAlreadyExists) {
+		t.Fatalf("Adder.Add() error = %v, want %v", err, db.ErrAlreadyExists)
+	}
+	if got != id {
+		t.Errorf("Adder.Add() = %v, want %v", got, id)
+	}
+
+	// An edited version updates the stored article.
+	edited := ar
+	edited.Body = "Morning. Noon."
+	got, err = a.Add(ctx, edited)
+	if err != nil {
+		t.Fatalf("Adder.Add() error = %v", err)
+	}
+	if got != id {
+		t.Errorf("Adder.Add() = %v, want %v", got, id)
+	}
+
+	// The same addr published on another day is another article.
+	tuesday := ar
+	tuesday.Published = monday.AddDate(0, 0, 1)
+	got, err = a.Add(ctx, tuesday)
+	if err != nil {
+		t.Fatalf("Adder.Add() error = %v", err)
+	}
+	if got == id {
+		t.Errorf("Adder.Add() = %v, want another id", got)
+	}
+
+	list, err := store.List(ctx)
+	if err != nil {
+		t.Fatalf("could not list articles, %s", err.Error())
+	}
+	if len(list) != 2 {
+		t.Errorf("stored articles = %v, want 2", len(list))
+	}
+}
+
+func TestAdder_Add_contentIDsPresent(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.TODO()
+	sum := &mock.Summarizer{SummarizeFn: func(ctx context.Context, text string, lang article.Language) (string, error) {
+		return "Morning.", nil
+	}}
+	a, err := New(
+		WithSummarizer(sum),
+		WithNamedEntityRecognizer(noop.Client{}),
+		WithDB(inmem.NewArticle(inmem.WithProvidedIDs())),
+		WithContentIDs(article.AddrID),
+		WithLogger(logger.NewTest(false)),
+	)
+	if err != nil {
+		t.Fatalf("could not create adder, %s", err.Error())
+	}
+
+	ar := article.Article{Title: "Live", Addr: url.URL{Scheme: "https", Host: "news.example.com", Path: "/live"}, Body: "Morning."}
+	id, err := a.Add(ctx, ar)
+	if err != nil {
+		t.Fatalf("Adder.Add() error = %v", err)
+	}
+
+	// The stored article is found by its content ID before the features are extracted.
+	sum.SummarizerInvoked = false
+	got, err := a.Add(ctx, ar)
+	if !errors.Is(err, db.ErrAlreadyExists) {
+		t.Fatalf("Adder.Add() error = %v, want %v", err, db.ErrAlreadyExists)
+	}
+	if got != id {
+		t.Errorf("Adder.Add() = %v, want %v", got, id)
+	}
+	if sum.SummarizerInvoked {
+		t.Error("summarizer invoked for a stored article")
+	}
+}
+
+func TestAdder_Add_language(t *testing.T) {
+	t.Parallel()
+
+	german := "Die Bundesregierung will die Preise für Strom senken, weil die Preise für Strom und Gas hoch sind."
+
+	tests := []struct {
+		name         string
+		ar           article.Article
+		summaryLang  article.Language
+		wantLang     article.Language
+		wantSumLang  article.Language
+		wantKeywords []string
+	}{
+		{
+			name:         "detected",
+			ar:           article.Article{Title: "Strompreise", Body: german},
+			wantLang:     article.German,
+			wantSumLang:  article.German,
+			wantKeywords: []string{"preise", "strom", "strompreise", "bundesregierung", "senken", "gas", "hoch"},
+		},
+		{
+			name:         "target language of summaries",
+			ar:           article.Article{Title: "Strompreise", Body: german},
+			summaryLang:  article.English,
+			wantLang:     article.German,
+			wantSumLang:  article.English,
+			wantKeywords: []string{"preise", "strom", "strompreise", "bundesregierung", "senken", "gas", "hoch"},
+		},
+		{
+			name:         "set language is kept",
+			ar:           article.Article{Title: "Berlin", Body: "Berlin Berlin Mitte.", Language: article.German},
+			wantLang:     article.German,
+			wantSumLang:  article.German,
+			wantKeywords: []string{"berlin", "mitte"},
+		},
+		{
+			name:         "unknown",
+			ar:           article.Article{Title: "Berlin", Body: "Berlin Mitte."},
+			wantLang:     article.LanguageUnknown,
+			wantSumLang:  article.LanguageUnknown,
+			wantKeywords: []string{"berlin", "mitte"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sumLang, nerLang article.Language
+			sum := &mock.Summarizer{SummarizeFn: func(ctx context.Context, text string, lang article.Language) (string, error) {
+				sumLang = lang
+				return "summary", nil
+			}}
+			ner := &mock.NER{NERFn: func(ctx context.Context, text string, lang article.Language) (article.NER, error) {
+				nerLang = lang
+				return article.NER{}, nil
+			}}
+			var added article.Article
+			db := &mock.DB{AddFn: func(ctx context.Context, ar article.Article) (string, error) {
+				added = ar
+				return "id", nil
+			}}
+
+			a, err := New(
+				WithSummarizer(sum),
+				WithNamedEntityRecognizer(ner),
+				WithDB(db),
+				WithSummaryLanguage(tt.summaryLang),
+				WithLogger(logger.NewTest(false)),
+			)
+			if err != nil {
+				t.Fatalf("could not create adder, %s", err.Error())
+			}
+
+			ar := tt.ar
+			ar.Addr = url.URL{Scheme: "https", Host: "news.example.com", Path: "/strom"}
+			_, err = a.Add(context.TODO(), ar)
+			if err != nil {
+				t.Fatalf("Adder.Add() error = %v", err)
+			}
+
+			if added.Language != tt.wantLang || nerLang != tt.wantLang {
+				t.Errorf("language = %q, ner language = %q, want %q", added.Language, nerLang, tt.wantLang)
+			}
+			if sumLang != tt.wantSumLang {
+				t.Errorf("summary language = %q, want %q", sumLang, tt.wantSumLang)
+			}
+			if !reflect.DeepEqual(added.Keywords, tt.wantKeywords) {
+				t.Errorf("keywords = %q, want %q", added.Keywords, tt.wantKeywords)
+			}
+		})
+	}
+}
+
 func TestNewWith(t *testing.T) {
 	t.Parallel()
 
 	log := logger.NewTest(false)
 	noop := noop.Client{}
+	val := validate.New()
 
 	tests := []struct {
 		opts    []AdderOption
@@ -187,12 +931,14 @@ func TestNewWith(t *testing.T) {
 				WithSummarizer(noop),
 				WithNamedEntityRecognizer(noop),
 				WithLogger(log),
+				WithValidator(val),
 			},
 			wantErr: false,
 			want: &Adder{
 				sum: noop,
 				ner: noop,
 				log: log,
+				val: val,
 			},
 		},
 		{
@@ -0,0 +1,205 @@
+// Package analysis splits texts into the terms, that are indexed and searched.
+// Every term keeps its offsets in the text, so matches can be highlighted. The
+// Analyzer of a language drops its stopwords and stems the words, so different
+// forms of a word result in the same term.
+package analysis
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/Br0ce/articleDB/pkg/article"
+)
+
+// Token is a term of a text.
+type Token struct {
+	// Term is the normalized word, e.g. lowercased.
+	Term string
+	// Pos is the position of the word among the words of the text. Dropped
+	// stopwords count, so the gaps of a phrase are kept.
+	Pos int
+	// Start and End are the byte offsets of the word in the text. End is
+	// exclusive, so text[Start:End] is the word.
+	Start int
+	End   int
+}
+
+// Tokenize splits text into words of letters and digits and lowercases them. An
+// apostrophe between two letters is part of the word, e.g. in Europe's.
+func Tokenize(text string) []Token {
+	var tokens []Token
+	start := -1
+	for i, r := range text {
+		word := wordRune(r) || (start >= 0 && apostrophe(r) && wordRune(next(text, i)))
+		switch {
+		case word && start < 0:
+			start = i
+		case !word && start >= 0:
+			tokens = append(tokens, token(text, start, i, len(tokens)))
+			start = -1
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, token(text, start, len(text), len(tokens)))
+	}
+	return tokens
+}
+
+func wordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsNumber(r) || unicode.Is(unicode.Mn, r)
+}
+
+func apostrophe(r rune) bool {
+	return r == '\'' || r == '’'
+}
+
+// next returns the rune after the rune at offset i.
+func next(text string, i int) rune {
+	_, size := utf8.DecodeRuneInString(text[i:])
+	r, _ := utf8.DecodeRuneInString(text[i+size:])
+	return r
+}
+
+// Terms returns the terms of the tokens of text.
+func Terms(text string) []string {
+	tokens := Tokenize(text)
+	terms := make([]string, 0, len(tokens))
+	for _, t := range tokens {
+		terms = append(terms, t.Term)
+	}
+	return terms
+}
+
+func token(text string, start, end, pos int) Token {
+	return Token{Term: strings.ToLower(text[start:end]), Pos: pos, Start: start, End: end}
+}
+
+// words returns the set of the words separated by white space.
+func words(s string) map[string]bool {
+	set := make(map[string]bool)
+	for _, w := range strings.Fields(s) {
+		set[w] = true
+	}
+	return set
+}
+
+// Analyzer turns the texts of a language into terms.
+type Analyzer struct {
+	lang article.Language
+	// normalize normalizes a lowercased word before it is checked against the
+	// stopwords and stemmed.
+	normalize func(word string) string
+	stopwords map[string]bool
+	stem      func(word string) string
+}
+
+var analyzers = map[article.Language]*Analyzer{
+	article.English: {
+		lang:      article.English,
+		normalize: normalizeEnglish,
+		stopwords: englishStopwords,
+		stem:      stemEnglish,
+	},
+	article.German: {
+		lang:      article.German,
+		normalize: removeApostrophes,
+		stopwords: germanStopwords,
+		stem:      stemGerman,
+	},
+}
+
+// neutral analyzes the texts of an unknown language. It keeps every word as it is.
+var neutral = &Analyzer{
+	normalize: func(word string) string { return word },
+	stopwords: map[string]bool{},
+	stem:      func(word string) string { return word },
+}
+
+// For returns the Analyzer of the language. An unknown or unsupported language
+// returns an Analyzer, that neither drops stopwords nor stems.
+func For(lang article.Language) *Analyzer {
+	if an, ok := analyzers[lang]; ok {
+		return an
+	}
+	return neutral
+}
+
+// Language returns the language of the Analyzer. It is LanguageUnknown, if the
+// Analyzer neither drops stopwords nor stems.
+func (an *Analyzer) Language() article.Language {
+	return an.lang
+}
+
+// Analyze tokenizes the text, drops the stopwords and stems the remaining words.
+func (an *Analyzer) Analyze(text string) []Token {
+	var tokens []Token
+	for _, t := range Tokenize(text) {
+		word := an.normalize(t.Term)
+		if an.stopwords[word] {
+			continue
+		}
+		t.Term = an.stem(word)
+		tokens = append(tokens, t)
+	}
+	return tokens
+}
+
+// Keywords returns up to n of the most frequent words of the text, that are no
+// stopwords, the most frequent first. Words with the same stem count as one word,
+// the first form in the text is returned. Numbers and words shorter than three
+// letters are skipped.
+func (an *Analyzer) Keywords(text string, n int) []string {
+	type keyword struct {
+		word  string
+		count int
+	}
+	byStem := make(map[string]*keyword)
+	var keywords []*keyword
+	for _, t := range Tokenize(text) {
+		word := an.normalize(t.Term)
+		if an.stopwords[word] || utf8.RuneCountInString(word) < 3 || strings.IndexFunc(word, unicode.IsLetter) < 0 {
+			continue
+		}
+		stem := an.stem(word)
+		kw, ok := byStem[stem]
+		if !ok {
+			kw = &keyword{word: word}
+			byStem[stem] = kw
+			keywords = append(keywords, kw)
+		}
+		kw.count++
+	}
+
+	sort.SliceStable(keywords, func(i, j int) bool {
+		return keywords[i].count > keywords[j].count
+	})
+	if len(keywords) > n {
+		keywords = keywords[:n]
+	}
+
+	result := make([]string, 0, len(keywords))
+	for _, kw := range keywords {
+		result = append(result, kw.word)
+	}
+	return result
+}
+
+// normalizeEnglish removes the possessive 's and other apostrophes, e.g. Europe's
+// is normalized to europe and don't to dont.
+func normalizeEnglish(word string) string {
+	for _, s := range []string{"'s", "’s"} {
+		word = strings.TrimSuffix(word, s)
+	}
+	return removeApostrophes(word)
+}
+
+func removeApostrophes(word string) string {
+	return strings.Map(func(r rune) rune {
+		if apostrophe(r) {
+			return -1
+		}
+		return r
+	}, word)
+}
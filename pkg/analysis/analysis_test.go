@@ -0,0 +1,171 @@
+package analysis
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Br0ce/articleDB/pkg/article"
+)
+
+func TestTokenize(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		text string
+		want []Token
+	}{
+		{
+			name: "words",
+			text: "Olaf Scholz met the EU.",
+			want: []Token{
+				{Term: "olaf", Pos: 0, Start: 0, End: 4},
+				{Term: "scholz", Pos: 1, Start: 5, End: 11},
+				{Term: "met", Pos: 2, Start: 12, End: 15},
+				{Term: "the", Pos: 3, Start: 16, End: 19},
+				{Term: "eu", Pos: 4, Start: 20, End: 22},
+			},
+		},
+		{
+			name: "punctuation and digits",
+			text: "(2023) energy-crisis",
+			want: []Token{
+				{Term: "2023", Pos: 0, Start: 1, End: 5},
+				{Term: "energy", Pos: 1, Start: 7, End: 13},
+				{Term: "crisis", Pos: 2, Start: 14, End: 20},
+			},
+		},
+		{
+			name: "multi byte runes",
+			text: "Élysée Straße",
+			want: []Token{
+				{Term: "élysée", Pos: 0, Start: 0, End: 8},
+				{Term: "straße", Pos: 1, Start: 9, End: 16},
+			},
+		},
+		{
+			name: "apostrophes",
+			text: "Europe's 'energy' don’t",
+			want: []Token{
+				{Term: "europe's", Pos: 0, Start: 0, End: 8},
+				{Term: "energy", Pos: 1, Start: 10, End: 16},
+				{Term: "don’t", Pos: 2, Start: 18, End: 25},
+			},
+		},
+		{
+			name: "no words",
+			text: " -- ",
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Tokenize(tt.text)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Tokenize() = %v, want %v", got, tt.want)
+			}
+			for _, tok := range got {
+				if Terms(tt.text[tok.Start:tok.End])[0] != tok.Term {
+					t.Errorf("offsets of %v do not match the text", tok)
+				}
+			}
+		})
+	}
+}
+
+func TestAnalyzer_Analyze(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		lang article.Language
+		text string
+		want []Token
+	}{
+		{
+			name: "english",
+			lang: article.English,
+			text: "The prices of Europe's energies",
+			want: []Token{
+				{Term: "price", Pos: 1, Start: 4, End: 10},
+				{Term: "europ", Pos: 3, Start: 14, End: 22},
+				{Term: "energi", Pos: 4, Start: 23, End: 31},
+			},
+		},
+		{
+			name: "german",
+			lang: article.German,
+			text: "Die Preise für Häuser",
+			want: []Token{
+				{Term: "preis", Pos: 1, Start: 4, End: 10},
+				{Term: "haus", Pos: 3, Start: 16, End: 23},
+			},
+		},
+		{
+			name: "unknown",
+			lang: article.LanguageUnknown,
+			text: "The prices",
+			want: []Token{
+				{Term: "the", Pos: 0, Start: 0, End: 3},
+				{Term: "prices", Pos: 1, Start: 4, End: 10},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			an := For(tt.lang)
+			if an.Language() != tt.lang {
+				t.Errorf("Language() = %q, want %q", an.Language(), tt.lang)
+			}
+			got := an.Analyze(tt.text)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Analyze() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnalyzer_Keywords(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		lang article.Language
+		text string
+		n    int
+		want []string
+	}{
+		{
+			name: "english",
+			lang: article.English,
+			text: "Energy prices rise. The price of energy is high, as prices of gas rose in 2023.",
+			n:    3,
+			want: []string{"prices", "energy", "rise"},
+		},
+		{
+			name: "german",
+			lang: article.German,
+			text: "Die Preise steigen. Der Preis für Energie ist hoch, weil die Preise für Gas steigen.",
+			n:    2,
+			want: []string{"preise", "steigen"},
+		},
+		{
+			name: "no words",
+			lang: article.English,
+			text: "The 2023 of it.",
+			n:    3,
+			want: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := For(tt.lang).Keywords(tt.text, tt.n)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Keywords() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
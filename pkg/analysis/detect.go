@@ -0,0 +1,65 @@
+package analysis
+
+import "github.com/Br0ce/articleDB/pkg/article"
+
+// minEvidence is the number of distinctive stopwords a text needs at least to
+// detect its language.
+const minEvidence = 2
+
+// ambiguous are stopwords, that are common words of other languages as well, e.g.
+// "die" is German, but also an English verb.
+var ambiguous = words("a i s t die man war hat bin hin also")
+
+// distinctive are the stopwords of each language, that are neither stopwords of
+// another language nor ambiguous.
+var distinctive = func() map[article.Language]map[string]bool {
+	result := make(map[article.Language]map[string]bool)
+	for lang, an := range analyzers {
+		set := make(map[string]bool)
+		for w := range an.stopwords {
+			set[w] = true
+		}
+		for other, otherAn := range analyzers {
+			if other == lang {
+				continue
+			}
+			for w := range otherAn.stopwords {
+				delete(set, w)
+			}
+		}
+		for w := range ambiguous {
+			delete(set, w)
+		}
+		result[lang] = set
+	}
+	return result
+}()
+
+// Detect returns the language of the text. It counts the distinctive stopwords of
+// every supported language in the text and returns the language with the most. If
+// the text has less than two of them or two languages are equally likely,
+// LanguageUnknown is returned. Detect runs offline.
+func Detect(text string) article.Language {
+	counts := make(map[article.Language]int)
+	for _, t := range Tokenize(text) {
+		for lang, set := range distinctive {
+			if set[t.Term] {
+				counts[lang]++
+			}
+		}
+	}
+
+	best, bestCount, tie := article.LanguageUnknown, 0, false
+	for _, lang := range article.Languages {
+		switch c := counts[lang]; {
+		case c > bestCount:
+			best, bestCount, tie = lang, c, false
+		case c == bestCount:
+			tie = true
+		}
+	}
+	if tie || bestCount < minEvidence {
+		return article.LanguageUnknown
+	}
+	return best
+}
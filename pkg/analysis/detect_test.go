@@ -0,0 +1,51 @@
+package analysis
+
+import (
+	"testing"
+
+	"github.com/Br0ce/articleDB/pkg/article"
+)
+
+func TestDetect(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		text string
+		want article.Language
+	}{
+		{
+			name: "english",
+			text: "The government wants to lower the prices of gas and electricity before the winter.",
+			want: article.English,
+		},
+		{
+			name: "german",
+			text: "Die Bundesregierung will die Preise für Gas und Strom vor dem Winter senken.",
+			want: article.German,
+		},
+		{
+			name: "ambiguous words only",
+			text: "Die man war also in Berlin.",
+			want: article.LanguageUnknown,
+		},
+		{
+			name: "too short",
+			text: "Olaf Scholz",
+			want: article.LanguageUnknown,
+		},
+		{
+			name: "empty",
+			text: "",
+			want: article.LanguageUnknown,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Detect(tt.text); got != tt.want {
+				t.Errorf("Detect() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package analysis
+
+import "unicode/utf8"
+
+// MaxDistance is the largest edit distance of a fuzzy match.
+const MaxDistance = 2
+
+// EditDistance returns the number of runes, that are inserted, deleted, substituted
+// or swapped with their neighbour to turn a into b. The counting stops after max,
+// so any larger distance is returned as max+1.
+func EditDistance(a, b string, max int) int {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra)-len(rb) > max || len(rb)-len(ra) > max {
+		return max + 1
+	}
+
+	// The rows of the distances of the prefixes of a to the prefixes of b.
+	prev2 := make([]int, len(rb)+1)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		rowMin := i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				cur[j] = min(cur[j], prev2[j-2]+1)
+			}
+			rowMin = min(rowMin, cur[j])
+		}
+		if rowMin > max {
+			return max + 1
+		}
+		prev2, prev, cur = prev, cur, prev2
+	}
+
+	return min(prev[len(rb)], max+1)
+}
+
+// Fuzziness returns the edit distance allowed for the term, but at most max. Short
+// terms allow fewer edits, so they do not match every other short term: terms of
+// up to two runes must match exactly, terms of up to five runes allow one edit.
+func Fuzziness(term string, max int) int {
+	switch n := utf8.RuneCountInString(term); {
+	case n <= 2:
+		return 0
+	case n <= 5:
+		return min(max, 1)
+	default:
+		return min(max, MaxDistance)
+	}
+}
@@ -0,0 +1,59 @@
+package analysis
+
+import "testing"
+
+func TestEditDistance(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		a    string
+		b    string
+		max  int
+		want int
+	}{
+		{name: "equal", a: "scholz", b: "scholz", max: 2, want: 0},
+		{name: "insertion", a: "scholz", b: "scholtz", max: 2, want: 1},
+		{name: "deletion", a: "energy", b: "enrgy", max: 2, want: 1},
+		{name: "substitution", a: "merkel", b: "merkal", max: 2, want: 1},
+		{name: "transposition", a: "crisis", b: "crsiis", max: 2, want: 1},
+		{name: "two edits", a: "macron", b: "makrom", max: 2, want: 2},
+		{name: "more than max", a: "berlin", b: "paris", max: 2, want: 3},
+		{name: "length difference", a: "eu", b: "european", max: 2, want: 3},
+		{name: "multi byte runes", a: "münchen", b: "munchen", max: 2, want: 1},
+		{name: "empty", a: "", b: "ab", max: 2, want: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EditDistance(tt.a, tt.b, tt.max); got != tt.want {
+				t.Errorf("EditDistance() = %v, want %v", got, tt.want)
+			}
+			if got := EditDistance(tt.b, tt.a, tt.max); got != tt.want {
+				t.Errorf("EditDistance() swapped = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFuzziness(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		term string
+		max  int
+		want int
+	}{
+		{term: "eu", max: 2, want: 0},
+		{term: "merz", max: 2, want: 1},
+		{term: "scholz", max: 2, want: 2},
+		{term: "scholz", max: 1, want: 1},
+		{term: "straße", max: 0, want: 0},
+	}
+
+	for _, tt := range tests {
+		if got := Fuzziness(tt.term, tt.max); got != tt.want {
+			t.Errorf("Fuzziness(%q, %v) = %v, want %v", tt.term, tt.max, got, tt.want)
+		}
+	}
+}
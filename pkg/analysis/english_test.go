@@ -0,0 +1,35 @@
+package analysis
+
+import "testing"
+
+func TestStemEnglish(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"caresses":        "caress",
+		"ponies":          "poni",
+		"cats":            "cat",
+		"agreed":          "agre",
+		"plastered":       "plaster",
+		"motoring":        "motor",
+		"hopping":         "hop",
+		"falling":         "fall",
+		"filing":          "file",
+		"happy":           "happi",
+		"relational":      "relat",
+		"generalizations": "gener",
+		"electrical":      "electr",
+		"adjustment":      "adjust",
+		"controlling":     "control",
+		"crisis":          "crisi",
+		"energy":          "energi",
+		"is":              "is",
+		"café":            "café",
+	}
+
+	for word, want := range tests {
+		if got := stemEnglish(word); got != want {
+			t.Errorf("stemEnglish(%q) = %q, want %q", word, got, want)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package analysis
+
+import (
+	"strings"
+	"unicode"
+)
+
+var germanStopwords = words(`aber alle allem allen aller alles als also am an ander andere anderem
+anderen anderer anderes auch auf aus bei bin bis bist da damit dann das dass dein deine dem den denn der
+des dich die dies diese diesem diesen dieser dieses dir du durch ein eine einem einen einer eines er es
+etwas euch euer eure für gegen hab habe haben hat hatte hatten hier hin hinter ich ihm ihn ihnen ihr
+ihre im in ins ist jede jedem jeden jeder jedes jetzt kann kein keine können man manche mich mir mit
+muss nach nicht nichts noch nun nur ob oder ohne sehr sein seine sich sie sind so solche soll sollte
+sondern sonst über um und uns unser unter vom von vor war waren warum was weil welche wenn wer werde
+werden wie wieder will wir wird wo zu zum zur zwar zwischen`)
+
+// stemGerman stems the lowercased word with the German stemmer of the Snowball
+// project. The umlauts are removed, e.g. häuser is stemmed to haus.
+func stemGerman(word string) string {
+	w := []rune(strings.ReplaceAll(word, "ß", "ss"))
+
+	// u and y between vowels are consonants.
+	for i := 1; i+1 < len(w); i++ {
+		if (w[i] == 'u' || w[i] == 'y') && germanVowel(w[i-1]) && germanVowel(w[i+1]) {
+			w[i] = unicode.ToUpper(w[i])
+		}
+	}
+
+	r1 := max(germanRegion(w, 0), 3)
+	r2 := germanRegion(w, r1)
+
+	w = germanStep1(w, r1)
+	w = germanStep2(w, r1)
+	w = germanStep3(w, r1, r2)
+
+	for i, r := range w {
+		switch r {
+		case 'U', 'ü':
+			w[i] = 'u'
+		case 'Y':
+			w[i] = 'y'
+		case 'ä':
+			w[i] = 'a'
+		case 'ö':
+			w[i] = 'o'
+		}
+	}
+	return string(w)
+}
+
+func germanVowel(r rune) bool {
+	return strings.ContainsRune("aeiouyäöü", r)
+}
+
+// germanRegion returns the offset after the first non-vowel following a vowel at
+// or after from. It is the length of w, if there is none.
+func germanRegion(w []rune, from int) int {
+	for i := from + 1; i < len(w); i++ {
+		if !germanVowel(w[i]) && germanVowel(w[i-1]) {
+			return i + 1
+		}
+	}
+	return len(w)
+}
+
+// suffix returns the first of the suffixes w ends with and its offset. The offset
+// is -1, if w ends with none.
+func suffix(w []rune, suffixes ...string) (string, int) {
+	for _, s := range suffixes {
+		if strings.HasSuffix(string(w), s) {
+			return s, len(w) - len([]rune(s))
+		}
+	}
+	return "", -1
+}
+
+// sEnding reports whether r may precede a removed s.
+func sEnding(r rune) bool {
+	return strings.ContainsRune("bdfghklmnrt", r)
+}
+
+// stEnding reports whether r may precede a removed st.
+func stEnding(r rune) bool {
+	return strings.ContainsRune("bdfghklmnt", r)
+}
+
+func germanStep1(w []rune, r1 int) []rune {
+	s, at := suffix(w, "ern", "em", "er", "en", "es", "e", "s")
+	if at < r1 {
+		return w
+	}
+	switch s {
+	case "ern", "em", "er":
+		return w[:at]
+	case "en", "es", "e":
+		w = w[:at]
+		if strings.HasSuffix(string(w), "niss") {
+			w = w[:len(w)-1]
+		}
+		return w
+	default:
+		if at > 0 && sEnding(w[at-1]) {
+			return w[:at]
+		}
+		return w
+	}
+}
+
+func germanStep2(w []rune, r1 int) []rune {
+	s, at := suffix(w, "est", "en", "er", "st")
+	if at < r1 {
+		return w
+	}
+	if s == "st" && (at-1 < 3 || !stEnding(w[at-1])) {
+		return w
+	}
+	return w[:at]
+}
+
+func germanStep3(w []rune, r1, r2 int) []rune {
+	s, at := suffix(w, "end", "ung", "isch", "ig", "ik", "lich", "heit", "keit")
+	if at < r2 {
+		return w
+	}
+	switch s {
+	case "end", "ung":
+		w = w[:at]
+		if _, at := suffix(w, "ig"); at >= r2 && w[at-1] != 'e' {
+			w = w[:at]
+		}
+	case "isch", "ig", "ik":
+		if w[at-1] != 'e' {
+			w = w[:at]
+		}
+	case "lich", "heit":
+		w = w[:at]
+		if _, at := suffix(w, "er", "en"); at >= r1 {
+			w = w[:at]
+		}
+	case "keit":
+		w = w[:at]
+		if _, at := suffix(w, "lich", "ig"); at >= r2 {
+			w = w[:at]
+		}
+	}
+	return w
+}
@@ -0,0 +1,28 @@
+package analysis
+
+import "testing"
+
+func TestStemGerman(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"katzen":               "katz",
+		"häuser":               "haus",
+		"preise":               "preis",
+		"straße":               "strass",
+		"kenntnisse":           "kenntnis",
+		"zeitung":              "zeitung",
+		"bundesregierung":      "bundesregier",
+		"freundlichkeit":       "freundlich",
+		"energiekrise":         "energiekris",
+		"aufeinanderfolgenden": "aufeinanderfolg",
+		"bauen":                "bau",
+		"ab":                   "ab",
+	}
+
+	for word, want := range tests {
+		if got := stemGerman(word); got != want {
+			t.Errorf("stemGerman(%q) = %q, want %q", word, got, want)
+		}
+	}
+}
@@ -1,34 +1,228 @@
 package api
 
 import (
+	"context"
 	"log/slog"
 	"net/http"
 
 	"github.com/Br0ce/articleDB/pkg/adder"
+	"github.com/Br0ce/articleDB/pkg/article"
 	"github.com/Br0ce/articleDB/pkg/db/inmem"
+	"github.com/Br0ce/articleDB/pkg/dedup"
 	"github.com/Br0ce/articleDB/pkg/extract/noop"
+	"github.com/Br0ce/articleDB/pkg/fetch"
+	"github.com/Br0ce/articleDB/pkg/query"
+	"github.com/Br0ce/articleDB/pkg/related"
+	"github.com/Br0ce/articleDB/pkg/semantic"
+	"github.com/Br0ce/articleDB/pkg/vector"
 )
 
 type Api struct {
 	handler http.Handler
-	log     *slog.Logger
+	db      article.DB
+	adder   *adder.Adder
+	// plainAdder stores articles without enrichment, e.g. for a bulk import.
+	plainAdder *adder.Adder
+	related    *related.Ranker
+	fetcher    *fetch.Fetcher
+	ner        adder.NamedEntityRecognizer
+	sum        adder.Summarizer
+	sumLang    article.Language
+	revs       article.RevisionStore
+	idFunc     article.IDFunc
+	// emb and vec are optional. If emb is set, the embeddings of added articles are
+	// indexed in vec for the semantic search.
+	emb      vector.Embedder
+	vec      vector.Index
+	semantic *semantic.Searcher
+	// dupThreshold enables the reuse of the features of near-duplicates, if it is
+	// greater than 0.
+	dupThreshold float64
+	// synonyms expand the texts and names of search queries.
+	synonyms *query.Synonyms
+	log      *slog.Logger
 }
 
-func New(log *slog.Logger) (*Api, error) {
-	db := inmem.NewArticle()
-	noop := noop.Client{}
+type Option func(a *Api)
+
+// WithDB sets the db, the api works on. Without it, an empty inmem db is used.
+func WithDB(db article.DB) Option {
+	return func(a *Api) {
+		a.db = db
+	}
+}
+
+// WithFetcher sets the fetcher for pages. Without it, a fetcher with the default
+// http client is used.
+func WithFetcher(f *fetch.Fetcher) Option {
+	return func(a *Api) {
+		a.fetcher = f
+	}
+}
+
+// WithNamedEntityRecognizer sets the recognizer of the named entities of added
+// articles. Without it, no entities are recognized.
+func WithNamedEntityRecognizer(ner adder.NamedEntityRecognizer) Option {
+	return func(a *Api) {
+		a.ner = ner
+	}
+}
+
+// WithSummarizer sets the summarizer of added articles. Without it, no summaries
+// are written.
+func WithSummarizer(sum adder.Summarizer) Option {
+	return func(a *Api) {
+		a.sum = sum
+	}
+}
+
+// WithSummaryLanguage requests the summaries in lang instead of the language of the
+// added articles.
+func WithSummaryLanguage(lang article.Language) Option {
+	return func(a *Api) {
+		a.sumLang = lang
+	}
+}
+
+// WithRevisions sets the store of the revisions of the articles. Without it, an
+// inmem store with the default retention is used.
+func WithRevisions(revs article.RevisionStore) Option {
+	return func(a *Api) {
+		a.revs = revs
+	}
+}
+
+// WithContentIDs derives the IDs of added articles with fn, e.g. with
+// article.AddrID. A db set with WithDB must keep the provided IDs, e.g. an
+// inmem.Article with inmem.WithProvidedIDs.
+func WithContentIDs(fn article.IDFunc) Option {
+	return func(a *Api) {
+		a.idFunc = fn
+	}
+}
+
+// WithEmbedder computes the embeddings of added articles with emb and enables the
+// semantic search. Without it, no embeddings are computed.
+func WithEmbedder(emb vector.Embedder) Option {
+	return func(a *Api) {
+		a.emb = emb
+	}
+}
+
+// WithVectorIndex sets the index of the embeddings, e.g. a hnsw.Index. Without it,
+// an exact vector.Flat index is used. It is only used with WithEmbedder.
+func WithVectorIndex(vec vector.Index) Option {
+	return func(a *Api) {
+		a.vec = vec
+	}
+}
 
-	_, err := adder.New(
-		adder.WithSummarizer(noop),
-		adder.WithNamedEntityRecognizer(noop),
-		adder.WithDB(db),
+// WithNearDuplicateReuse copies the features of a stored near-duplicate with a
+// similarity of at least threshold into an added article instead of extracting
+// them again, see adder.WithNearDuplicateReuse.
+func WithNearDuplicateReuse(threshold float64) Option {
+	return func(a *Api) {
+		a.dupThreshold = threshold
+	}
+}
+
+// WithSynonyms expands the texts and the names of search queries to their
+// synonyms. Without it, queries are not expanded.
+func WithSynonyms(s *query.Synonyms) Option {
+	return func(a *Api) {
+		a.synonyms = s
+	}
+}
+
+func New(log *slog.Logger, opts ...Option) (*Api, error) {
+	a := &Api{log: log}
+
+	for _, opt := range opts {
+		opt(a)
+	}
+
+	if a.db == nil && a.idFunc != nil {
+		a.db = inmem.NewArticle(inmem.WithProvidedIDs())
+	}
+	if a.db == nil {
+		a.db = inmem.NewArticle()
+	}
+	if a.revs == nil {
+		a.revs = inmem.NewRevision()
+	}
+
+	noop := noop.Client{}
+	if a.ner == nil {
+		a.ner = noop
+	}
+	if a.sum == nil {
+		a.sum = noop
+	}
+	adderOpts := []adder.AdderOption{
+		adder.WithSummarizer(a.sum),
+		adder.WithSummaryLanguage(a.sumLang),
+		adder.WithNamedEntityRecognizer(a.ner),
+		adder.WithDB(a.db),
+		adder.WithRevisions(a.revs),
 		adder.WithLogger(log.With("name", "api")),
-	)
+	}
+	if a.idFunc != nil {
+		adderOpts = append(adderOpts, adder.WithContentIDs(a.idFunc))
+	}
+	if a.dupThreshold != 0 {
+		if a.dupThreshold < 0 || a.dupThreshold > 1 {
+			return nil, dedup.ErrInvalidThreshold
+		}
+		adderOpts = append(adderOpts, adder.WithNearDuplicateReuse(dedup.New(a.db), a.dupThreshold))
+	}
+	if a.emb != nil {
+		if a.vec == nil {
+			a.vec = vector.NewFlat()
+		}
+		a.semantic = semantic.New(a.emb, a.vec, a.db, log.With("name", "semantic"))
+		if a.vec.Len() == 0 {
+			err := a.semantic.Reindex(context.Background())
+			if err != nil {
+				return nil, err
+			}
+		}
+		adderOpts = append(adderOpts, adder.WithEmbedder(a.emb), adder.WithVectorIndex(a.vec))
+	}
+	ad, err := adder.New(adderOpts...)
+	if err != nil {
+		return nil, err
+	}
+	a.adder = ad
+	plain, err := adder.New(append(adderOpts[:len(adderOpts):len(adderOpts)], adder.WithoutEnrichment())...)
 	if err != nil {
 		return nil, err
 	}
+	a.plainAdder = plain
+	a.related = related.New(a.db)
+
+	if a.fetcher == nil {
+		a.fetcher = fetch.New(log.With("name", "fetcher"))
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/articles/fetch", a.fetchArticle)
+	mux.HandleFunc("/articles:bulk", a.bulkImport)
+	mux.HandleFunc("/articles:export", a.exportArticles)
+	mux.HandleFunc("/articles:restore", a.restoreArticles)
+	mux.HandleFunc("/articles:warc", a.importWARC)
+	mux.HandleFunc("/articles:clusters", a.getClusters)
+	mux.HandleFunc("/articles", a.articles)
+	mux.HandleFunc("/articles/", a.articles)
+	mux.HandleFunc("/suggest", a.suggest)
+	a.handler = mux
+
+	return a, nil
+}
 
-	return &Api{log: log}, nil
+// Adder returns the adder of the api, e.g. to ingest the articles of feeds with the
+// same enrichment as the articles added by the api.
+func (a *Api) Adder() *adder.Adder {
+	return a.adder
 }
 
 func (a *Api) ServeHTTP(w http.ResponseWriter, r *http.Request) {
@@ -0,0 +1,119 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Br0ce/articleDB/pkg/encoding"
+)
+
+const (
+	defaultRelated = 10
+	maxRelated     = 100
+)
+
+type reasonDTO struct {
+	Kind   string   `json:"kind"`
+	Shared []string `json:"shared,omitempty"`
+	Score  float64  `json:"score"`
+}
+
+type relatedDTO struct {
+	ID      string      `json:"id"`
+	Title   string      `json:"title"`
+	Score   float64     `json:"score"`
+	Reasons []reasonDTO `json:"reasons"`
+}
+
+// articles routes the requests to /articles and below /articles/.
+func (a *Api) articles(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/articles"), "/"), "/")
+
+	switch {
+	case len(parts) == 1 && parts[0] == "":
+		a.allow(w, r, http.MethodGet, func() { a.listArticles(w, r) })
+	case len(parts) == 1 && parts[0] == "search":
+		a.allow(w, r, http.MethodGet, func() { a.searchArticles(w, r) })
+	case len(parts) == 1 && parts[0] == "semantic":
+		a.allow(w, r, http.MethodGet, func() { a.semanticSearch(w, r) })
+	case len(parts) == 1 && parts[0] != "" && r.Method == http.MethodPut:
+		a.updateArticle(w, r, parts[0])
+	case len(parts) == 1 && parts[0] != "":
+		a.allow(w, r, http.MethodGet, func() { a.getArticle(w, r, parts[0]) })
+	case len(parts) == 2 && parts[1] == "related":
+		a.allow(w, r, http.MethodGet, func() { a.getRelated(w, r, parts[0]) })
+	case len(parts) == 2 && parts[1] == "duplicates":
+		a.allow(w, r, http.MethodGet, func() { a.getDuplicates(w, r, parts[0]) })
+	case len(parts) == 2 && parts[1] == "revisions":
+		a.allow(w, r, http.MethodGet, func() { a.listRevisions(w, r, parts[0]) })
+	case len(parts) == 3 && parts[1] == "revisions":
+		a.allow(w, r, http.MethodGet, func() { a.getRevision(w, r, parts[0], parts[2]) })
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// allow calls handle, if the request has the given method.
+func (a *Api) allow(w http.ResponseWriter, r *http.Request, method string, handle func()) {
+	if r.Method != method {
+		w.Header().Set("Allow", method)
+		a.writeJSON(w, http.StatusMethodNotAllowed, errorDTO{Error: http.StatusText(http.StatusMethodNotAllowed)})
+		return
+	}
+	handle()
+}
+
+// getArticle handles GET /articles/{id}.
+func (a *Api) getArticle(w http.ResponseWriter, r *http.Request, id string) {
+	a.log.Info("get article", "method", "getArticle", "articleID", id)
+
+	ar, err := a.db.Get(r.Context(), id)
+	if err != nil {
+		a.writeError(w, err)
+		return
+	}
+
+	a.writeJSON(w, http.StatusOK, encoding.FromArticle(ar))
+}
+
+// getRelated handles GET /articles/{id}/related. The number of related articles can
+// be set with the query parameter n.
+func (a *Api) getRelated(w http.ResponseWriter, r *http.Request, id string) {
+	a.log.Info("get related articles", "method", "getRelated", "articleID", id)
+
+	n := defaultRelated
+	if param := r.URL.Query().Get("n"); param != "" {
+		var err error
+		n, err = strconv.Atoi(param)
+		if err != nil || n < 1 || n > maxRelated {
+			a.writeBadRequest(w, "n must be a number between 1 and "+strconv.Itoa(maxRelated))
+			return
+		}
+	}
+
+	results, err := a.related.Related(r.Context(), id, n)
+	if err != nil {
+		a.writeError(w, err)
+		return
+	}
+
+	dtos := make([]relatedDTO, 0, len(results))
+	for _, res := range results {
+		dto := relatedDTO{
+			ID:    res.Article.ID,
+			Title: res.Article.Title,
+			Score: res.Score,
+		}
+		for _, reason := range res.Reasons {
+			dto.Reasons = append(dto.Reasons, reasonDTO{
+				Kind:   reason.Kind,
+				Shared: reason.Shared,
+				Score:  reason.Score,
+			})
+		}
+		dtos = append(dtos, dto)
+	}
+
+	a.writeJSON(w, http.StatusOK, dtos)
+}
@@ -0,0 +1,161 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/Br0ce/articleDB/pkg/article"
+	"github.com/Br0ce/articleDB/pkg/db/inmem"
+	"github.com/Br0ce/articleDB/pkg/encoding"
+	"github.com/Br0ce/articleDB/pkg/ids"
+	"github.com/Br0ce/articleDB/pkg/logger"
+)
+
+func TestApi_getRelated(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.TODO()
+	db := inmem.NewArticle()
+
+	baseID, err := db.Add(ctx, article.Article{
+		Title: "base",
+		Addr:  url.URL{Scheme: "https", Host: "news.example.com", Path: "/base"},
+		NER:   article.NER{Entities: []article.Entity{{Name: "Olaf Scholz", Type: article.Person, Count: 1}, {Name: "EU", Type: article.Organisation, Count: 1}}},
+	})
+	if err != nil {
+		t.Fatalf("could not add article, %s", err.Error())
+	}
+	relatedID, err := db.Add(ctx, article.Article{
+		Title: "related",
+		Addr:  url.URL{Scheme: "https", Host: "news.example.com", Path: "/related"},
+		NER:   article.NER{Entities: []article.Entity{{Name: "Olaf Scholz", Type: article.Person, Count: 1}}},
+	})
+	if err != nil {
+		t.Fatalf("could not add article, %s", err.Error())
+	}
+	_, err = db.Add(ctx, article.Article{
+		Title: "unrelated",
+		Addr:  url.URL{Scheme: "https", Host: "news.example.com", Path: "/unrelated"},
+		NER:   article.NER{Entities: []article.Entity{{Name: "Paris", Type: article.Location, Count: 1}}},
+	})
+	if err != nil {
+		t.Fatalf("could not add article, %s", err.Error())
+	}
+
+	a, err := New(logger.NewTest(false), WithDB(db))
+	if err != nil {
+		t.Fatalf("could not create api, %s", err.Error())
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		target     string
+		wantStatus int
+		wantIDs    []string
+	}{
+		{
+			name:       "pass",
+			method:     http.MethodGet,
+			target:     "/articles/" + baseID + "/related",
+			wantStatus: http.StatusOK,
+			wantIDs:    []string{relatedID},
+		},
+		{
+			name:       "invalid n",
+			method:     http.MethodGet,
+			target:     "/articles/" + baseID + "/related?n=0",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "invalid id",
+			method:     http.MethodGet,
+			target:     "/articles/1234/related",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "not found",
+			method:     http.MethodGet,
+			target:     "/articles/" + ids.UniqueID() + "/related",
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "method not allowed",
+			method:     http.MethodPost,
+			target:     "/articles/" + baseID + "/related",
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			a.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.target, nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %v, want %v", rec.Code, tt.wantStatus)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var got []relatedDTO
+			if err := encoding.DecodeJSON(rec.Body, &got); err != nil {
+				t.Fatalf("could not decode body, %s", err.Error())
+			}
+			if len(got) != len(tt.wantIDs) {
+				t.Fatalf("related len = %v, want %v", len(got), len(tt.wantIDs))
+			}
+			for i, id := range tt.wantIDs {
+				if got[i].ID != id {
+					t.Errorf("related[%d] = %v, want %v", i, got[i].ID, id)
+				}
+			}
+			if len(got[0].Reasons) == 0 || got[0].Reasons[0].Shared[0] != "Olaf Scholz" {
+				t.Errorf("reasons = %v, want shared entity Olaf Scholz", got[0].Reasons)
+			}
+		})
+	}
+}
+
+func TestApi_getArticle(t *testing.T) {
+	t.Parallel()
+
+	db := inmem.NewArticle()
+	id, err := db.Add(context.TODO(), article.Article{
+		Title: "test title",
+		Addr:  url.URL{Scheme: "https", Host: "news.example.com", Path: "/test"},
+	})
+	if err != nil {
+		t.Fatalf("could not add article, %s", err.Error())
+	}
+
+	a, err := New(logger.NewTest(false), WithDB(db))
+	if err != nil {
+		t.Fatalf("could not create api, %s", err.Error())
+	}
+
+	rec := httptest.NewRecorder()
+	a.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/articles/"+id, nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %v, want %v", rec.Code, http.StatusOK)
+	}
+
+	var got encoding.Article
+	if err := encoding.DecodeJSON(rec.Body, &got); err != nil {
+		t.Fatalf("could not decode body, %s", err.Error())
+	}
+	if got.Version != encoding.ArticleVersion || got.ID != id || got.Title != "test title" ||
+		got.URL != "https://news.example.com/test" {
+		t.Errorf("article = %+v", got)
+	}
+
+	rec = httptest.NewRecorder()
+	a.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/articles/"+ids.UniqueID(), nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("status = %v, want %v", rec.Code, http.StatusNotFound)
+	}
+}
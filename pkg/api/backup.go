@@ -0,0 +1,75 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/Br0ce/articleDB/pkg/archive"
+)
+
+var errRestoreUnsupported = errors.New("restore is not supported by the db")
+
+type restoreDTO struct {
+	Restored int `json:"restored"`
+}
+
+// exportArticles handles GET /articles:export. All articles are streamed as archive
+// with their revisions.
+func (a *Api) exportArticles(w http.ResponseWriter, r *http.Request) {
+	a.allow(w, r, http.MethodGet, func() {
+		a.log.Info("export articles", "method", "exportArticles")
+
+		name := "articledb-" + time.Now().UTC().Format("20060102T150405Z") + ".ndjson.gz"
+		w.Header().Set("Content-Type", "application/gzip")
+		w.Header().Set("Content-Disposition", `attachment; filename="`+name+`"`)
+
+		// The status is sent with the first bytes of the archive, so a failing export
+		// aborts the response. The client sees a broken stream instead of a complete
+		// but truncated archive.
+		n, err := archive.Export(r.Context(), w, a.db, a.revs)
+		if err != nil {
+			a.log.Error("could not export articles", "method", "exportArticles", "exported", n, "err", err.Error())
+			panic(http.ErrAbortHandler)
+		}
+
+		a.log.Info("articles exported", "method", "exportArticles", "exported", n)
+	})
+}
+
+// restoreArticles handles POST /articles:restore. The body is an archive written by
+// GET /articles:export, its articles are stored with their original IDs and their
+// revisions with their original numbers, if the revision store supports it. The
+// embeddings of the restored articles are added to the vector index.
+func (a *Api) restoreArticles(w http.ResponseWriter, r *http.Request) {
+	a.allow(w, r, http.MethodPost, func() {
+		a.log.Info("restore articles", "method", "restoreArticles")
+
+		store, ok := a.db.(archive.Restorer)
+		if !ok {
+			a.writeError(w, errRestoreUnsupported)
+			return
+		}
+
+		revs, _ := a.revs.(archive.RevisionRestorer)
+		n, err := archive.Restore(r.Context(), r.Body, store, revs)
+		if err != nil {
+			a.log.Error("could not restore articles", "method", "restoreArticles", "restored", n, "err", err.Error())
+			a.writeError(w, err)
+			return
+		}
+
+		// The restored articles bypass the adder, so their embeddings are added to
+		// the index afterwards.
+		if a.semantic != nil {
+			err = a.semantic.Reindex(r.Context())
+			if err != nil {
+				a.log.Error("could not reindex restored articles", "method", "restoreArticles", "restored", n, "err", err.Error())
+				a.writeError(w, err)
+				return
+			}
+		}
+
+		a.writeJSON(w, http.StatusOK, restoreDTO{Restored: n})
+	})
+}
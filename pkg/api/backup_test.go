@@ -0,0 +1,147 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Br0ce/articleDB/pkg/article"
+	"github.com/Br0ce/articleDB/pkg/db/inmem"
+	"github.com/Br0ce/articleDB/pkg/logger"
+	"github.com/Br0ce/articleDB/pkg/mock"
+)
+
+func TestApi_exportRestore(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.TODO()
+	src := inmem.NewArticle()
+	srcRevs := inmem.NewRevision()
+	var revised string
+	for _, path := range []string{"/energy", "/storm"} {
+		id, err := src.Add(ctx, article.Article{
+			Title:    "title of " + path,
+			Addr:     url.URL{Scheme: "https", Host: "news.example.com", Path: path},
+			Body:     "body of " + path,
+			Summary:  "summary of " + path,
+			Keywords: []string{"keyword"},
+			NER:      article.NER{Entities: []article.Entity{{Name: "Jane Doe", Type: article.Person, Count: 1}}},
+		})
+		if err != nil {
+			t.Fatalf("could not add article, %s", err.Error())
+		}
+		revised = id
+	}
+	stored, err := src.Get(ctx, revised)
+	if err != nil {
+		t.Fatalf("could not get article, %s", err.Error())
+	}
+	if _, err := srcRevs.Add(ctx, article.Revision{ArticleID: revised, Article: stored}); err != nil {
+		t.Fatalf("could not add revision, %s", err.Error())
+	}
+
+	a, err := New(logger.NewTest(false), WithDB(src), WithRevisions(srcRevs))
+	if err != nil {
+		t.Fatalf("could not create api, %s", err.Error())
+	}
+
+	rec := httptest.NewRecorder()
+	a.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/articles:export", nil))
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "application/gzip" {
+		t.Fatalf("export status = %v, content type %v", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	backup := rec.Body.Bytes()
+
+	dst := inmem.NewArticle()
+	dstRevs := inmem.NewRevision()
+	b, err := New(logger.NewTest(false), WithDB(dst), WithRevisions(dstRevs))
+	if err != nil {
+		t.Fatalf("could not create api, %s", err.Error())
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		body       []byte
+		wantStatus int
+	}{
+		{
+			name:       "restore",
+			method:     http.MethodPost,
+			body:       backup,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "restore again",
+			method:     http.MethodPost,
+			body:       backup,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "invalid archive",
+			method:     http.MethodPost,
+			body:       []byte("no archive"),
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "method not allowed",
+			method:     http.MethodGet,
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		b.ServeHTTP(rec, httptest.NewRequest(tt.method, "/articles:restore", bytes.NewReader(tt.body)))
+		if rec.Code != tt.wantStatus {
+			t.Fatalf("%s: status = %v, want %v, body %s", tt.name, rec.Code, tt.wantStatus, rec.Body.String())
+		}
+		if tt.wantStatus == http.StatusOK && !strings.Contains(rec.Body.String(), `"restored":2`) {
+			t.Errorf("%s: body = %s", tt.name, rec.Body.String())
+		}
+	}
+
+	want, err := src.List(ctx)
+	if err != nil {
+		t.Fatalf("could not list articles, %s", err.Error())
+	}
+	got, err := dst.List(ctx)
+	if err != nil {
+		t.Fatalf("could not list articles, %s", err.Error())
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("restored = %+v, want %+v", got, want)
+	}
+
+	wantRevs, err := srcRevs.List(ctx, revised)
+	if err != nil {
+		t.Fatalf("could not list revisions, %s", err.Error())
+	}
+	gotRevs, err := dstRevs.List(ctx, revised)
+	if err != nil {
+		t.Fatalf("could not list revisions, %s", err.Error())
+	}
+	if len(gotRevs) != 1 || !reflect.DeepEqual(gotRevs, wantRevs) {
+		t.Errorf("restored revisions = %+v, want %+v", gotRevs, wantRevs)
+	}
+}
+
+func TestApi_restoreArticles_unsupported(t *testing.T) {
+	t.Parallel()
+
+	a, err := New(logger.NewTest(false), WithDB(&mock.DB{}))
+	if err != nil {
+		t.Fatalf("could not create api, %s", err.Error())
+	}
+
+	rec := httptest.NewRecorder()
+	a.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/articles:restore", nil))
+	if rec.Code != http.StatusNotImplemented {
+		t.Errorf("status = %v, want %v", rec.Code, http.StatusNotImplemented)
+	}
+}
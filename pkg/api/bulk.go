@@ -0,0 +1,138 @@
+package api
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/Br0ce/articleDB/pkg/bulk"
+)
+
+const maxBulkParallelism = 32
+
+type bulkResultDTO struct {
+	Line    int    `json:"line"`
+	ID      string `json:"id,omitempty"`
+	Skipped bool   `json:"skipped,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+type bulkSummaryDTO struct {
+	Read    int    `json:"read"`
+	Added   int    `json:"added"`
+	Skipped int    `json:"skipped"`
+	Failed  int    `json:"failed"`
+	Error   string `json:"error,omitempty"`
+}
+
+type bulkDoneDTO struct {
+	Summary bulkSummaryDTO `json:"summary"`
+}
+
+// bulkImport handles POST /articles:bulk. The body is an NDJSON or CSV file, the
+// format is taken from the query parameter format or the Content-Type. The query
+// parameter map sets the column mapping, parallelism the number of concurrent
+// adds and enrich=false stores the articles without summary, named entities and
+// embedding.
+// The response is streamed as NDJSON with one result per line of the body,
+// followed by a summary. Articles, that are already stored, are reported as
+// skipped.
+func (a *Api) bulkImport(w http.ResponseWriter, r *http.Request) {
+	a.allow(w, r, http.MethodPost, func() {
+		query := r.URL.Query()
+
+		format, err := bulkFormat(r)
+		if err != nil {
+			a.writeBadRequest(w, err.Error())
+			return
+		}
+
+		mapping, err := bulk.ParseMapping(query.Get("map"))
+		if err != nil {
+			a.writeBadRequest(w, err.Error())
+			return
+		}
+
+		parallelism := bulk.DefaultParallelism
+		if param := query.Get("parallelism"); param != "" {
+			parallelism, err = strconv.Atoi(param)
+			if err != nil || parallelism < 1 || parallelism > maxBulkParallelism {
+				a.writeBadRequest(w, "parallelism must be a number between 1 and "+strconv.Itoa(maxBulkParallelism))
+				return
+			}
+		}
+
+		var adder bulk.Adder = a.adder
+		if enrich := query.Get("enrich"); enrich != "" {
+			ok, err := strconv.ParseBool(enrich)
+			if err != nil {
+				a.writeBadRequest(w, "enrich must be a boolean")
+				return
+			}
+			if !ok {
+				adder = a.plainAdder
+			}
+		}
+
+		reader, err := bulk.NewReader(r.Body, format, mapping)
+		if err != nil {
+			a.writeBadRequest(w, err.Error())
+			return
+		}
+
+		a.log.Info("bulk import", "method", "bulkImport", "format", format, "parallelism", parallelism)
+
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.WriteHeader(http.StatusOK)
+		flusher, _ := w.(http.Flusher)
+		enc := json.NewEncoder(w)
+
+		importer := bulk.New(adder, a.log.With("name", "bulk"), bulk.WithParallelism(parallelism))
+		sum, err := importer.Import(r.Context(), reader, func(res bulk.Result) {
+			dto := bulkResultDTO{Line: res.Line, ID: res.ID, Skipped: res.Skipped}
+			if res.Err != nil {
+				dto.Error = res.Err.Error()
+			}
+			if err := enc.Encode(dto); err != nil {
+				a.log.Error("could not write result", "method", "bulkImport", "err", err.Error())
+				return
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+		})
+
+		done := bulkDoneDTO{Summary: bulkSummaryDTO{Read: sum.Read, Added: sum.Added, Skipped: sum.Skipped, Failed: sum.Failed}}
+		if err != nil {
+			a.log.Error("could not import", "method", "bulkImport", "err", err.Error())
+			done.Summary.Error = err.Error()
+		}
+		err = enc.Encode(done)
+		if err != nil {
+			a.log.Error("could not write summary", "method", "bulkImport", "err", err.Error())
+		}
+	})
+}
+
+// bulkFormat returns the format of the bulk body. The query parameter format takes
+// precedence over the Content-Type.
+func bulkFormat(r *http.Request) (bulk.Format, error) {
+	if f := r.URL.Query().Get("format"); f != "" {
+		return bulk.ParseFormat(f)
+	}
+
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		return "", bulk.ErrUnknownFormat
+	}
+
+	switch mediaType {
+	case "text/csv":
+		return bulk.CSV, nil
+	case "application/x-ndjson", "application/jsonl", "application/json":
+		return bulk.NDJSON, nil
+	default:
+		return "", bulk.ErrUnknownFormat
+	}
+}
@@ -0,0 +1,172 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/Br0ce/articleDB/pkg/db/inmem"
+	"github.com/Br0ce/articleDB/pkg/logger"
+)
+
+func TestApi_bulkImport(t *testing.T) {
+	t.Parallel()
+
+	csv, err := os.ReadFile("../bulk/testdata/articles.csv")
+	if err != nil {
+		t.Fatalf("could not read fixture, %s", err.Error())
+	}
+	ndjson, err := os.ReadFile("../bulk/testdata/articles.ndjson")
+	if err != nil {
+		t.Fatalf("could not read fixture, %s", err.Error())
+	}
+
+	tests := []struct {
+		name        string
+		method      string
+		target      string
+		contentType string
+		body        string
+		wantStatus  int
+		wantAdded   int
+		wantFailed  int
+	}{
+		{
+			name:        "csv",
+			method:      http.MethodPost,
+			target:      "/articles:bulk",
+			contentType: "text/csv",
+			body:        string(csv),
+			wantStatus:  http.StatusOK,
+			wantAdded:   3,
+			wantFailed:  2,
+		},
+		{
+			name:       "ndjson without enrichment",
+			method:     http.MethodPost,
+			target:     "/articles:bulk?format=ndjson&map=title=headline,url=link&enrich=false&parallelism=2",
+			body:       string(ndjson),
+			wantStatus: http.StatusOK,
+			wantAdded:  2,
+			wantFailed: 5,
+		},
+		{
+			name:        "unknown format",
+			method:      http.MethodPost,
+			target:      "/articles:bulk",
+			contentType: "application/xml",
+			wantStatus:  http.StatusBadRequest,
+		},
+		{
+			name:       "invalid mapping",
+			method:     http.MethodPost,
+			target:     "/articles:bulk?format=csv&map=summary=teaser",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "invalid parallelism",
+			method:     http.MethodPost,
+			target:     "/articles:bulk?format=csv&parallelism=0",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "method not allowed",
+			method:     http.MethodGet,
+			target:     "/articles:bulk",
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := inmem.NewArticle()
+			a, err := New(logger.NewTest(false), WithDB(db))
+			if err != nil {
+				t.Fatalf("could not create api, %s", err.Error())
+			}
+
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			rec := httptest.NewRecorder()
+			a.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %v, want %v, body %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var results []bulkResultDTO
+			var done bulkDoneDTO
+			sc := bufio.NewScanner(rec.Body)
+			for sc.Scan() {
+				if strings.HasPrefix(sc.Text(), `{"summary"`) {
+					if err := json.Unmarshal(sc.Bytes(), &done); err != nil {
+						t.Fatalf("could not decode summary, %s", err.Error())
+					}
+					continue
+				}
+				var res bulkResultDTO
+				if err := json.Unmarshal(sc.Bytes(), &res); err != nil {
+					t.Fatalf("could not decode result, %s", err.Error())
+				}
+				results = append(results, res)
+			}
+
+			if done.Summary.Added != tt.wantAdded || done.Summary.Failed != tt.wantFailed || done.Summary.Error != "" {
+				t.Errorf("summary = %+v, want added %v, failed %v", done.Summary, tt.wantAdded, tt.wantFailed)
+			}
+			if len(results) != tt.wantAdded+tt.wantFailed {
+				t.Errorf("got %v results, want %v", len(results), tt.wantAdded+tt.wantFailed)
+			}
+
+			stored, err := db.List(context.TODO())
+			if err != nil {
+				t.Fatalf("could not list articles, %s", err.Error())
+			}
+			if len(stored) != tt.wantAdded {
+				t.Errorf("stored %v articles, want %v", len(stored), tt.wantAdded)
+			}
+		})
+	}
+}
+
+func TestApi_bulkImport_repeated(t *testing.T) {
+	t.Parallel()
+
+	csv, err := os.ReadFile("../bulk/testdata/articles.csv")
+	if err != nil {
+		t.Fatalf("could not read fixture, %s", err.Error())
+	}
+	a, err := New(logger.NewTest(false), WithDB(inmem.NewArticle()))
+	if err != nil {
+		t.Fatalf("could not create api, %s", err.Error())
+	}
+
+	var done bulkDoneDTO
+	for i := 0; i < 2; i++ {
+		req := httptest.NewRequest(http.MethodPost, "/articles:bulk?format=csv", strings.NewReader(string(csv)))
+		rec := httptest.NewRecorder()
+		a.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %v, want %v, body %s", rec.Code, http.StatusOK, rec.Body.String())
+		}
+
+		lines := strings.Split(strings.TrimSpace(rec.Body.String()), "\n")
+		if err := json.Unmarshal([]byte(lines[len(lines)-1]), &done); err != nil {
+			t.Fatalf("could not decode summary, %s", err.Error())
+		}
+	}
+
+	// The stored articles are skipped, only the invalid rows fail again.
+	want := bulkSummaryDTO{Read: 5, Skipped: 3, Failed: 2}
+	if done.Summary != want {
+		t.Errorf("summary = %+v, want %+v", done.Summary, want)
+	}
+}
@@ -0,0 +1,98 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Br0ce/articleDB/pkg/dedup"
+)
+
+// defaultDupThreshold is the similarity of near-duplicates, if the request sets no
+// threshold.
+const defaultDupThreshold = 0.9
+
+type duplicateDTO struct {
+	ID         string  `json:"id"`
+	Title      string  `json:"title"`
+	Similarity float64 `json:"similarity"`
+}
+
+type clusterDTO struct {
+	Canonical string   `json:"canonical"`
+	IDs       []string `json:"ids"`
+}
+
+// getDuplicates handles GET /articles/{id}/duplicates. It returns the
+// near-duplicates of the article with a similarity of at least the query parameter
+// threshold, the most similar first.
+func (a *Api) getDuplicates(w http.ResponseWriter, r *http.Request, id string) {
+	a.log.Info("get near-duplicates", "method", "getDuplicates", "articleID", id)
+
+	threshold, ok := a.parseThreshold(w, r)
+	if !ok {
+		return
+	}
+
+	ar, err := a.db.Get(r.Context(), id)
+	if err != nil {
+		a.writeError(w, err)
+		return
+	}
+
+	dtos := make([]duplicateDTO, 0)
+	if ar.Fingerprint != 0 {
+		matches, err := dedup.New(a.db).NearDuplicates(r.Context(), ar.Fingerprint, threshold)
+		if err != nil {
+			a.writeError(w, err)
+			return
+		}
+		for _, m := range matches {
+			if m.Article.ID == ar.ID {
+				continue
+			}
+			dtos = append(dtos, duplicateDTO{ID: m.Article.ID, Title: m.Article.Title, Similarity: m.Similarity})
+		}
+	}
+
+	a.writeJSON(w, http.StatusOK, dtos)
+}
+
+// getClusters handles GET /articles:clusters. It groups the near-duplicates with a
+// similarity of at least the query parameter threshold.
+func (a *Api) getClusters(w http.ResponseWriter, r *http.Request) {
+	a.allow(w, r, http.MethodGet, func() {
+		a.log.Info("get near-duplicate clusters", "method", "getClusters")
+
+		threshold, ok := a.parseThreshold(w, r)
+		if !ok {
+			return
+		}
+
+		clusters, err := dedup.New(a.db).Clusters(r.Context(), threshold)
+		if err != nil {
+			a.writeError(w, err)
+			return
+		}
+
+		dtos := make([]clusterDTO, 0, len(clusters))
+		for _, c := range clusters {
+			dtos = append(dtos, clusterDTO{Canonical: c.Canonical, IDs: c.IDs})
+		}
+		a.writeJSON(w, http.StatusOK, dtos)
+	})
+}
+
+// parseThreshold returns the query parameter threshold or defaultDupThreshold. An
+// invalid threshold is answered with a bad request.
+func (a *Api) parseThreshold(w http.ResponseWriter, r *http.Request) (float64, bool) {
+	param := r.URL.Query().Get("threshold")
+	if param == "" {
+		return defaultDupThreshold, true
+	}
+	threshold, err := strconv.ParseFloat(param, 64)
+	if err != nil || threshold <= 0 || threshold > 1 {
+		a.writeBadRequest(w, "threshold must be a number in (0, 1]")
+		return 0, false
+	}
+	return threshold, true
+}
@@ -0,0 +1,137 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Br0ce/articleDB/pkg/article"
+	"github.com/Br0ce/articleDB/pkg/db/inmem"
+	"github.com/Br0ce/articleDB/pkg/dedup"
+	"github.com/Br0ce/articleDB/pkg/encoding"
+	"github.com/Br0ce/articleDB/pkg/logger"
+	"github.com/Br0ce/articleDB/pkg/simhash"
+)
+
+func TestApi_getDuplicates(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.TODO()
+	db := inmem.NewArticle()
+	body := "The central bank raised interest rates by a quarter point on Thursday, citing persistent inflation in services and a tight labour market."
+
+	day := time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)
+	add := func(path, body string) string {
+		day = day.AddDate(0, 0, 1)
+		id, err := db.Add(ctx, article.Article{
+			Title:       path,
+			Addr:        url.URL{Scheme: "https", Host: "news.example.com", Path: "/" + path},
+			Body:        body,
+			Published:   day,
+			Fingerprint: simhash.Fingerprint(body),
+		})
+		if err != nil {
+			t.Fatalf("could not add article, %s", err.Error())
+		}
+		return id
+	}
+	wire := add("wire", body)
+	copied := add("copy", body+" Reporting by Jane Doe.")
+	add("other", "A storm hit the northern coast and left thousands of households without power for two days.")
+
+	a, err := New(logger.NewTest(false), WithDB(db))
+	if err != nil {
+		t.Fatalf("could not create api, %s", err.Error())
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		target     string
+		wantStatus int
+		wantIDs    []string
+	}{
+		{
+			name:       "near-duplicates",
+			method:     http.MethodGet,
+			target:     "/articles/" + wire + "/duplicates?threshold=0.75",
+			wantStatus: http.StatusOK,
+			wantIDs:    []string{copied},
+		},
+		{
+			name:       "identical only",
+			method:     http.MethodGet,
+			target:     "/articles/" + wire + "/duplicates?threshold=1",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "invalid threshold",
+			method:     http.MethodGet,
+			target:     "/articles/" + wire + "/duplicates?threshold=2",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "wrong method",
+			method:     http.MethodPost,
+			target:     "/articles/" + wire + "/duplicates",
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			a.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.target, nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %v, want %v, %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var dtos []duplicateDTO
+			if err := encoding.DecodeJSON(rec.Body, &dtos); err != nil {
+				t.Fatalf("could not decode body, %s", err.Error())
+			}
+			var got []string
+			for _, dto := range dtos {
+				got = append(got, dto.ID)
+			}
+			if !reflect.DeepEqual(got, tt.wantIDs) {
+				t.Errorf("ids = %v, want %v", got, tt.wantIDs)
+			}
+		})
+	}
+
+	t.Run("clusters", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		a.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/articles:clusters?threshold=0.75", nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %v, want %v, %s", rec.Code, http.StatusOK, rec.Body.String())
+		}
+
+		var got []clusterDTO
+		if err := encoding.DecodeJSON(rec.Body, &got); err != nil {
+			t.Fatalf("could not decode body, %s", err.Error())
+		}
+		want := []clusterDTO{{Canonical: wire, IDs: []string{wire, copied}}}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("clusters = %+v, want %+v", got, want)
+		}
+	})
+}
+
+func TestNew_nearDuplicateReuse(t *testing.T) {
+	t.Parallel()
+
+	_, err := New(logger.NewTest(false), WithNearDuplicateReuse(1.5))
+	if !errors.Is(err, dedup.ErrInvalidThreshold) {
+		t.Errorf("New() error = %v, want %v", err, dedup.ErrInvalidThreshold)
+	}
+}
@@ -0,0 +1,53 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Br0ce/articleDB/pkg/db"
+	"github.com/Br0ce/articleDB/pkg/encoding"
+)
+
+type fetchDTO struct {
+	URL string `json:"url"`
+}
+
+type idDTO struct {
+	ID string `json:"id"`
+}
+
+// fetchArticle handles POST /articles/fetch. The page at the posted url is downloaded,
+// its main article is extracted and added. Urls of private addresses are rejected by
+// the default fetcher. If the article is already stored unchanged, its ID is answered
+// with status ok.
+func (a *Api) fetchArticle(w http.ResponseWriter, r *http.Request) {
+	a.allow(w, r, http.MethodPost, func() {
+		var dto fetchDTO
+		err := encoding.DecodeJSON(r.Body, &dto)
+		if err != nil {
+			a.writeBadRequest(w, "invalid json body")
+			return
+		}
+
+		a.log.Info("fetch article", "method", "fetchArticle", "url", dto.URL)
+
+		ar, err := a.fetcher.Fetch(r.Context(), dto.URL)
+		if err != nil {
+			a.writeError(w, err)
+			return
+		}
+
+		id, err := a.adder.Add(r.Context(), ar)
+		if errors.Is(err, db.ErrAlreadyExists) && id != "" {
+			// Fetching the same article again is idempotent.
+			a.writeJSON(w, http.StatusOK, idDTO{ID: id})
+			return
+		}
+		if err != nil {
+			a.writeError(w, err)
+			return
+		}
+
+		a.writeJSON(w, http.StatusCreated, idDTO{ID: id})
+	})
+}
@@ -0,0 +1,128 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/Br0ce/articleDB/pkg/crawl"
+	"github.com/Br0ce/articleDB/pkg/db/inmem"
+	"github.com/Br0ce/articleDB/pkg/encoding"
+	"github.com/Br0ce/articleDB/pkg/fetch"
+	"github.com/Br0ce/articleDB/pkg/logger"
+)
+
+func TestApi_fetchArticle(t *testing.T) {
+	t.Parallel()
+
+	page, err := os.ReadFile("../readability/testdata/jsonld.html")
+	if err != nil {
+		t.Fatalf("could not read fixture, %s", err.Error())
+	}
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, err := w.Write(page)
+		if err != nil {
+			t.Errorf("could not write page, %s", err.Error())
+		}
+	}))
+	defer svr.Close()
+
+	db := inmem.NewArticle()
+	client := crawl.NewClient(crawl.NewTransport(crawl.WithPrivateNetworks()))
+	a, err := New(logger.NewTest(false), WithDB(db),
+		WithFetcher(fetch.New(logger.NewTest(false), fetch.WithHTTPClient(client))))
+	if err != nil {
+		t.Fatalf("could not create api, %s", err.Error())
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		body       string
+		wantStatus int
+	}{
+		{
+			name:       "pass",
+			method:     http.MethodPost,
+			body:       `{"url": "` + svr.URL + `/energy"}`,
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name:       "duplicate",
+			method:     http.MethodPost,
+			body:       `{"url": "` + svr.URL + `/energy-again"}`,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "invalid url",
+			method:     http.MethodPost,
+			body:       `{"url": "energy"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "invalid json",
+			method:     http.MethodPost,
+			body:       `{"url": `,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "method not allowed",
+			method:     http.MethodGet,
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	// The cases depend on each other, so they are not run in parallel.
+	var id string
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		a.ServeHTTP(rec, httptest.NewRequest(tt.method, "/articles/fetch", strings.NewReader(tt.body)))
+
+		if rec.Code != tt.wantStatus {
+			t.Fatalf("%s: status = %v, want %v, body %s", tt.name, rec.Code, tt.wantStatus, rec.Body.String())
+		}
+		if tt.wantStatus != http.StatusCreated && tt.wantStatus != http.StatusOK {
+			continue
+		}
+
+		var dto idDTO
+		if err := encoding.DecodeJSON(rec.Body, &dto); err != nil {
+			t.Fatalf("could not decode body, %s", err.Error())
+		}
+		// The duplicate is answered with the ID of the stored article.
+		if id != "" && dto.ID != id {
+			t.Errorf("%s: id = %v, want %v", tt.name, dto.ID, id)
+		}
+		id = dto.ID
+		got, err := db.Get(context.TODO(), dto.ID)
+		if err != nil {
+			t.Fatalf("could not get fetched article, %s", err.Error())
+		}
+		if got.Title != "Energy prices keep rising" || got.Addr.String() != "https://news.example.com/energy-prices" {
+			t.Errorf("fetched article = %+v", got)
+		}
+	}
+}
+
+func TestApi_fetchArticle_private(t *testing.T) {
+	t.Parallel()
+
+	a, err := New(logger.NewTest(false), WithDB(inmem.NewArticle()))
+	if err != nil {
+		t.Fatalf("could not create api, %s", err.Error())
+	}
+
+	for _, addr := range []string{"http://127.0.0.1:8080/admin", "http://169.254.169.254/latest/meta-data/"} {
+		rec := httptest.NewRecorder()
+		body := strings.NewReader(`{"url": "` + addr + `"}`)
+		a.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/articles/fetch", body))
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %v, want %v, body %s", addr, rec.Code, http.StatusBadRequest, rec.Body.String())
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Br0ce/articleDB/pkg/article"
+	"github.com/Br0ce/articleDB/pkg/encoding"
+)
+
+var errRangeUnsupported = errors.New("range queries are not supported by the db")
+
+const (
+	defaultListLimit = 100
+	maxListLimit     = 1000
+)
+
+// listArticles handles GET /articles. The articles are selected by a time range and
+// filtered with the query parameters:
+//
+//   - from and to: the range [from, to) as RFC3339 time or date, e.g. 2023-06-01
+//   - by: the time of the range, published (default) or created
+//   - order: asc (default) or desc
+//   - limit: the maximal number of articles, 100 by default
+//   - q: a text contained in the title or the body, checked on the articles in the
+//     range, see GET /articles/search for term queries
+//   - entity: a named entity mentioned in the articles, it can be repeated
+func (a *Api) listArticles(w http.ResponseWriter, r *http.Request) {
+	a.log.Info("list articles", "method", "listArticles", "query", r.URL.RawQuery)
+
+	ranger, ok := a.db.(article.Ranger)
+	if !ok {
+		a.writeError(w, errRangeUnsupported)
+		return
+	}
+
+	q, msg := parseRangeQuery(r)
+	if msg != "" {
+		a.writeBadRequest(w, msg)
+		return
+	}
+
+	items, err := ranger.Range(r.Context(), q)
+	if err != nil {
+		a.writeError(w, err)
+		return
+	}
+
+	dtos := make([]encoding.Article, 0, len(items))
+	for _, item := range items {
+		dtos = append(dtos, encoding.FromArticle(item))
+	}
+
+	a.writeJSON(w, http.StatusOK, dtos)
+}
+
+// parseRangeQuery returns the range query of the request. If a parameter is
+// invalid, the message for the client is returned.
+func parseRangeQuery(r *http.Request) (article.RangeQuery, string) {
+	params := r.URL.Query()
+	q := article.RangeQuery{
+		Limit:    defaultListLimit,
+		Text:     params.Get("q"),
+		Entities: params["entity"],
+	}
+
+	var ok bool
+	if q.From, ok = parseTimeParam(params.Get("from")); !ok {
+		return q, "from must be a RFC3339 time or a date"
+	}
+	if q.To, ok = parseTimeParam(params.Get("to")); !ok {
+		return q, "to must be a RFC3339 time or a date"
+	}
+
+	switch params.Get("by") {
+	case "", "published":
+		q.Field = article.TimePublished
+	case "created":
+		q.Field = article.TimeCreated
+	default:
+		return q, "by must be published or created"
+	}
+
+	switch params.Get("order") {
+	case "", "asc":
+		q.Order = article.Ascending
+	case "desc":
+		q.Order = article.Descending
+	default:
+		return q, "order must be asc or desc"
+	}
+
+	if param := params.Get("limit"); param != "" {
+		n, err := strconv.Atoi(param)
+		if err != nil || n < 1 || n > maxListLimit {
+			return q, "limit must be a number between 1 and " + strconv.Itoa(maxListLimit)
+		}
+		q.Limit = n
+	}
+
+	return q, ""
+}
+
+// parseTimeParam parses a RFC3339 time or a date. An empty value gives the zero
+// time.
+func parseTimeParam(value string) (time.Time, bool) {
+	if value == "" {
+		return time.Time{}, true
+	}
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return t.UTC(), true
+	}
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		return t, true
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,166 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Br0ce/articleDB/pkg/article"
+	"github.com/Br0ce/articleDB/pkg/db/inmem"
+	"github.com/Br0ce/articleDB/pkg/encoding"
+	"github.com/Br0ce/articleDB/pkg/logger"
+	"github.com/Br0ce/articleDB/pkg/mock"
+)
+
+func TestApi_listArticles(t *testing.T) {
+	t.Parallel()
+
+	db := inmem.NewArticle()
+	a, err := New(logger.NewTest(false), WithDB(db))
+	if err != nil {
+		t.Fatalf("could not create api, %s", err.Error())
+	}
+
+	day := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	stories := []struct {
+		title     string
+		body      string
+		published time.Time
+		locations []string
+	}{
+		{title: "Flood", body: "Heavy rain in Berlin.", published: day, locations: []string{"Berlin"}},
+		{title: "Storm", body: "Heavy wind in Hamburg.", published: day.Add(36 * time.Hour), locations: []string{"Hamburg"}},
+		{title: "Heat", body: "Sunny days in Berlin.", published: day.AddDate(0, 0, 3), locations: []string{"Berlin"}},
+	}
+	for _, s := range stories {
+		_, err := db.Add(context.TODO(), article.Article{
+			Title:     s.title,
+			Addr:      url.URL{Scheme: "https", Host: "weather.example.com", Path: "/" + s.title},
+			Body:      s.body,
+			Published: s.published,
+			NER:       article.NER{Entities: article.EntitiesOf(article.Location, s.locations...)},
+		})
+		if err != nil {
+			t.Fatalf("could not add article, %s", err.Error())
+		}
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		target     string
+		wantStatus int
+		wantTitles []string
+	}{
+		{
+			name:       "all",
+			method:     http.MethodGet,
+			target:     "/articles",
+			wantStatus: http.StatusOK,
+			wantTitles: []string{"Flood", "Storm", "Heat"},
+		},
+		{
+			name:       "date range",
+			method:     http.MethodGet,
+			target:     "/articles?from=2023-06-02&to=2023-06-04",
+			wantStatus: http.StatusOK,
+			wantTitles: []string{"Storm"},
+		},
+		{
+			name:       "time range descending",
+			method:     http.MethodGet,
+			target:     "/articles/?from=2023-06-01T00:00:00Z&order=desc&limit=2",
+			wantStatus: http.StatusOK,
+			wantTitles: []string{"Heat", "Storm"},
+		},
+		{
+			name:       "text and entity",
+			method:     http.MethodGet,
+			target:     "/articles?q=heavy&entity=berlin",
+			wantStatus: http.StatusOK,
+			wantTitles: []string{"Flood"},
+		},
+		{
+			name:       "nothing found",
+			method:     http.MethodGet,
+			target:     "/articles?to=2023-05-01",
+			wantStatus: http.StatusOK,
+			wantTitles: nil,
+		},
+		{
+			name:       "invalid time",
+			method:     http.MethodGet,
+			target:     "/articles?from=yesterday",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "invalid order",
+			method:     http.MethodGet,
+			target:     "/articles?order=up",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "invalid limit",
+			method:     http.MethodGet,
+			target:     "/articles?limit=0",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "empty range",
+			method:     http.MethodGet,
+			target:     "/articles?from=2023-06-02&to=2023-06-01",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "wrong method",
+			method:     http.MethodPost,
+			target:     "/articles",
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			a.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.target, nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %v, want %v, %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var dtos []encoding.Article
+			if err := encoding.DecodeJSON(rec.Body, &dtos); err != nil {
+				t.Fatalf("could not decode body, %s", err.Error())
+			}
+			var got []string
+			for _, dto := range dtos {
+				got = append(got, dto.Title)
+			}
+			if !reflect.DeepEqual(got, tt.wantTitles) {
+				t.Errorf("titles = %v, want %v", got, tt.wantTitles)
+			}
+		})
+	}
+}
+
+func TestApi_listArticles_unsupported(t *testing.T) {
+	t.Parallel()
+
+	a, err := New(logger.NewTest(false), WithDB(&mock.DB{}))
+	if err != nil {
+		t.Fatalf("could not create api, %s", err.Error())
+	}
+
+	rec := httptest.NewRecorder()
+	a.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/articles", nil))
+	if rec.Code != http.StatusNotImplemented {
+		t.Errorf("status = %v, want %v", rec.Code, http.StatusNotImplemented)
+	}
+}
@@ -0,0 +1,113 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Br0ce/articleDB/pkg/adder"
+	"github.com/Br0ce/articleDB/pkg/archive"
+	"github.com/Br0ce/articleDB/pkg/article"
+	"github.com/Br0ce/articleDB/pkg/crawl"
+	"github.com/Br0ce/articleDB/pkg/db"
+	"github.com/Br0ce/articleDB/pkg/encoding"
+	"github.com/Br0ce/articleDB/pkg/fetch"
+	"github.com/Br0ce/articleDB/pkg/ids"
+	"github.com/Br0ce/articleDB/pkg/query"
+	"github.com/Br0ce/articleDB/pkg/readability"
+	"github.com/Br0ce/articleDB/pkg/validate"
+	"github.com/Br0ce/articleDB/pkg/warc"
+)
+
+type errorDTO struct {
+	Error   string          `json:"error"`
+	Details []fieldErrorDTO `json:"details,omitempty"`
+	// Position is the byte offset of a syntax error in a query.
+	Position *int `json:"position,omitempty"`
+}
+
+type fieldErrorDTO struct {
+	Field  string `json:"field"`
+	Reason string `json:"reason"`
+}
+
+// writeJSON writes data json encoded with the given status code.
+func (a *Api) writeJSON(w http.ResponseWriter, status int, data interface{}) {
+	bb, err := encoding.EncodeJSON(data)
+	if err != nil {
+		a.log.Error("could not encode response", "method", "writeJSON", "err", err.Error())
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, err = w.Write(bb)
+	if err != nil {
+		a.log.Error("could not write response", "method", "writeJSON", "err", err.Error())
+	}
+}
+
+// writeError writes the err with a status code matching the err.
+func (a *Api) writeError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, ids.ErrInvalidID), errors.Is(err, fetch.ErrInvalidURL),
+		errors.Is(err, crawl.ErrPrivate):
+		status = http.StatusBadRequest
+	case errors.Is(err, archive.ErrInvalidArchive), errors.Is(err, archive.ErrUnsupportedVersion):
+		status = http.StatusBadRequest
+	case errors.Is(err, warc.ErrInvalidRecord), errors.Is(err, article.ErrInvalidRange),
+		errors.Is(err, query.ErrSyntax):
+		status = http.StatusBadRequest
+	case errors.Is(err, encoding.ErrInvalidArticle), errors.Is(err, encoding.ErrUnsupportedVersion):
+		status = http.StatusBadRequest
+	case errors.Is(err, errRestoreUnsupported), errors.Is(err, adder.ErrUpdateUnsupported),
+		errors.Is(err, errRangeUnsupported), errors.Is(err, errSearchUnsupported),
+		errors.Is(err, errSuggestUnsupported), errors.Is(err, errSemanticUnsupported):
+		status = http.StatusNotImplemented
+	case errors.Is(err, readability.ErrNoContent), errors.Is(err, validate.ErrInvalidArticle):
+		status = http.StatusUnprocessableEntity
+	case errors.Is(err, fetch.ErrNotHTML), errors.Is(err, fetch.ErrUnexpectedStatus):
+		status = http.StatusBadGateway
+	case errors.Is(err, db.ErrNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, db.ErrAlreadyExists):
+		status = http.StatusConflict
+	}
+
+	if status == http.StatusInternalServerError {
+		a.log.Error("could not handle request", "method", "writeError", "err", err.Error())
+		a.writeJSON(w, status, errorDTO{Error: http.StatusText(status)})
+		return
+	}
+
+	a.writeJSON(w, status, errorDTO{Error: err.Error(), Details: details(err), Position: position(err)})
+}
+
+// position returns the position of a syntax error.
+func position(err error) *int {
+	var serr *query.SyntaxError
+	if !errors.As(err, &serr) {
+		return nil
+	}
+	return &serr.Pos
+}
+
+// details returns the invalid fields of a validation error.
+func details(err error) []fieldErrorDTO {
+	var verr *validate.Error
+	if !errors.As(err, &verr) {
+		return nil
+	}
+
+	dtos := make([]fieldErrorDTO, 0, len(verr.Fields))
+	for _, f := range verr.Fields {
+		dtos = append(dtos, fieldErrorDTO{Field: f.Field, Reason: f.Reason})
+	}
+	return dtos
+}
+
+// writeBadRequest writes a bad request with the given message.
+func (a *Api) writeBadRequest(w http.ResponseWriter, msg string) {
+	a.writeJSON(w, http.StatusBadRequest, errorDTO{Error: msg})
+}
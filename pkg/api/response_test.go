@@ -0,0 +1,74 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/Br0ce/articleDB/pkg/db"
+	"github.com/Br0ce/articleDB/pkg/encoding"
+	"github.com/Br0ce/articleDB/pkg/logger"
+	"github.com/Br0ce/articleDB/pkg/validate"
+)
+
+func TestApi_writeError(t *testing.T) {
+	t.Parallel()
+
+	a, err := New(logger.NewTest(false))
+	if err != nil {
+		t.Fatalf("could not create api, %s", err.Error())
+	}
+
+	invalid := &validate.Error{Fields: []validate.FieldError{
+		{Field: validate.FieldTitle, Reason: "is required"},
+		{Field: validate.FieldURL, Reason: "is not an absolute url"},
+	}}
+
+	tests := []struct {
+		name        string
+		err         error
+		wantStatus  int
+		wantDetails []fieldErrorDTO
+	}{
+		{
+			name:       "validation error",
+			err:        fmt.Errorf("add, %w", invalid),
+			wantStatus: http.StatusUnprocessableEntity,
+			wantDetails: []fieldErrorDTO{
+				{Field: "title", Reason: "is required"},
+				{Field: "url", Reason: "is not an absolute url"},
+			},
+		},
+		{
+			name:       "not found",
+			err:        db.ErrNotFound,
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "internal",
+			err:        errors.New("boom"),
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			a.writeError(rec, tt.err)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %v, want %v", rec.Code, tt.wantStatus)
+			}
+			var dto errorDTO
+			if err := encoding.DecodeJSON(rec.Body, &dto); err != nil {
+				t.Fatalf("could not decode body, %s", err.Error())
+			}
+			if !reflect.DeepEqual(dto.Details, tt.wantDetails) {
+				t.Errorf("details = %+v, want %+v", dto.Details, tt.wantDetails)
+			}
+		})
+	}
+}
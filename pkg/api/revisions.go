@@ -0,0 +1,77 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Br0ce/articleDB/pkg/article"
+	"github.com/Br0ce/articleDB/pkg/encoding"
+)
+
+type revisionDTO struct {
+	Number  int         `json:"number"`
+	Created string      `json:"created"`
+	Changes []changeDTO `json:"changes"`
+}
+
+type revisionArticleDTO struct {
+	revisionDTO
+	Article encoding.Article `json:"article"`
+}
+
+// listRevisions handles GET /articles/{id}/revisions. The kept revisions are listed
+// with their changes ordered by number.
+func (a *Api) listRevisions(w http.ResponseWriter, r *http.Request, id string) {
+	a.log.Info("list revisions", "method", "listRevisions", "articleID", id)
+
+	_, err := a.db.Get(r.Context(), id)
+	if err != nil {
+		a.writeError(w, err)
+		return
+	}
+
+	revs, err := a.revs.List(r.Context(), id)
+	if err != nil {
+		a.writeError(w, err)
+		return
+	}
+
+	dtos := make([]revisionDTO, 0, len(revs))
+	for _, rev := range revs {
+		dtos = append(dtos, toRevisionDTO(rev))
+	}
+
+	a.writeJSON(w, http.StatusOK, dtos)
+}
+
+// getRevision handles GET /articles/{id}/revisions/{number}. The revision is
+// returned with the article as of the revision.
+func (a *Api) getRevision(w http.ResponseWriter, r *http.Request, id, number string) {
+	a.log.Info("get revision", "method", "getRevision", "articleID", id, "revision", number)
+
+	n, err := strconv.Atoi(number)
+	if err != nil || n < 1 {
+		a.writeBadRequest(w, "revision must be a positive number")
+		return
+	}
+
+	rev, err := a.revs.Get(r.Context(), id, n)
+	if err != nil {
+		a.writeError(w, err)
+		return
+	}
+
+	a.writeJSON(w, http.StatusOK, revisionArticleDTO{
+		revisionDTO: toRevisionDTO(rev),
+		Article:     encoding.FromArticle(rev.Article),
+	})
+}
+
+func toRevisionDTO(rev article.Revision) revisionDTO {
+	return revisionDTO{
+		Number:  rev.Number,
+		Created: rev.Created.UTC().Format(time.RFC3339Nano),
+		Changes: toDiffDTO(rev.ArticleID, rev.Diff).Changes,
+	}
+}
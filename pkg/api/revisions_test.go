@@ -0,0 +1,119 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Br0ce/articleDB/pkg/article"
+	"github.com/Br0ce/articleDB/pkg/db/inmem"
+	"github.com/Br0ce/articleDB/pkg/encoding"
+	"github.com/Br0ce/articleDB/pkg/ids"
+	"github.com/Br0ce/articleDB/pkg/logger"
+)
+
+func TestApi_revisions(t *testing.T) {
+	t.Parallel()
+
+	db := inmem.NewArticle()
+	a, err := New(logger.NewTest(false), WithDB(db))
+	if err != nil {
+		t.Fatalf("could not create api, %s", err.Error())
+	}
+
+	id, err := a.adder.Add(context.TODO(), article.Article{
+		Title: "Storm",
+		Addr:  url.URL{Scheme: "https", Host: "weather.example.com", Path: "/storm"},
+		Body:  "Heavy rain.",
+	})
+	if err != nil {
+		t.Fatalf("could not add article, %s", err.Error())
+	}
+
+	rec := httptest.NewRecorder()
+	body := `{"title": "Storm warning", "url": "https://weather.example.com/storm", "body": "Heavy rain."}`
+	a.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/articles/"+id, strings.NewReader(body)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("could not update article, status %v, %s", rec.Code, rec.Body.String())
+	}
+
+	t.Run("list", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		a.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/articles/"+id+"/revisions", nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %v, want %v", rec.Code, http.StatusOK)
+		}
+
+		var dtos []revisionDTO
+		if err := encoding.DecodeJSON(rec.Body, &dtos); err != nil {
+			t.Fatalf("could not decode body, %s", err.Error())
+		}
+		if len(dtos) != 2 || dtos[0].Number != 1 || dtos[1].Number != 2 {
+			t.Fatalf("revisions = %+v", dtos)
+		}
+		want := []changeDTO{{Field: "title", Old: "Storm", New: "Storm warning"}}
+		if len(dtos[0].Changes) != 0 || !reflect.DeepEqual(dtos[1].Changes, want) {
+			t.Errorf("changes = %+v, %+v, want [], %+v", dtos[0].Changes, dtos[1].Changes, want)
+		}
+	})
+
+	tests := []struct {
+		name       string
+		path       string
+		wantStatus int
+		wantTitle  string
+	}{
+		{
+			name:       "first revision",
+			path:       "/articles/" + id + "/revisions/1",
+			wantStatus: http.StatusOK,
+			wantTitle:  "Storm",
+		},
+		{
+			name:       "latest revision",
+			path:       "/articles/" + id + "/revisions/2",
+			wantStatus: http.StatusOK,
+			wantTitle:  "Storm warning",
+		},
+		{
+			name:       "unknown revision",
+			path:       "/articles/" + id + "/revisions/3",
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "invalid revision",
+			path:       "/articles/" + id + "/revisions/first",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "unknown article",
+			path:       "/articles/" + ids.UniqueID() + "/revisions",
+			wantStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			a.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %v, want %v, body %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var dto revisionArticleDTO
+			if err := encoding.DecodeJSON(rec.Body, &dto); err != nil {
+				t.Fatalf("could not decode body, %s", err.Error())
+			}
+			if dto.Article.Title != tt.wantTitle || dto.Article.ID != id {
+				t.Errorf("revision article = %+v", dto.Article)
+			}
+		})
+	}
+}
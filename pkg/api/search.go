@@ -0,0 +1,167 @@
+package api
+
+import (
+	"errors"
+	"html"
+	"net/http"
+	"strconv"
+
+	"github.com/Br0ce/articleDB/pkg/encoding"
+	"github.com/Br0ce/articleDB/pkg/highlight"
+	"github.com/Br0ce/articleDB/pkg/query"
+)
+
+var errSearchUnsupported = errors.New("search is not supported by the db")
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+	maxFragmentSize    = 1000
+	maxFragments       = 10
+	maxTagLen          = 32
+)
+
+type hitDTO struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	URL         string `json:"url"`
+	Author      string `json:"author,omitempty"`
+	PublishedAt string `json:"published_at,omitempty"`
+	// Highlights maps the fields title, body and summary to the fragments showing
+	// the matched terms. The title is highlighted as a whole.
+	Highlights map[string][]string `json:"highlights,omitempty"`
+}
+
+// searchArticles handles GET /articles/search. The query q is written in the query
+// language of the package query, the number of hits can be set with limit. The hits
+// are ordered by the date of publication, the latest first. An invalid query is
+// answered with the position of the syntax error.
+//
+// The matched terms are highlighted in fragments of the title, the body and the
+// summary, a term only in the field it is searched in. The fragments are configured
+// with the query parameters fragment_size, fragments and the tags pre_tag and
+// post_tag. The text of the fragments is HTML escaped.
+func (a *Api) searchArticles(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query().Get("q")
+	a.log.Info("search articles", "method", "searchArticles", "query", q)
+
+	searcher, ok := a.db.(query.Searcher)
+	if !ok {
+		a.writeError(w, errSearchUnsupported)
+		return
+	}
+
+	if q == "" {
+		a.writeBadRequest(w, "q is required")
+		return
+	}
+
+	limit := defaultSearchLimit
+	if param := r.URL.Query().Get("limit"); param != "" {
+		var err error
+		limit, err = strconv.Atoi(param)
+		if err != nil || limit < 1 || limit > maxSearchLimit {
+			a.writeBadRequest(w, "limit must be a number between 1 and "+strconv.Itoa(maxSearchLimit))
+			return
+		}
+	}
+
+	hl, msg := parseHighlighter(r)
+	if msg != "" {
+		a.writeBadRequest(w, msg)
+		return
+	}
+
+	plan, err := query.Compile(q, query.WithSynonyms(a.synonyms))
+	if err != nil {
+		a.writeError(w, err)
+		return
+	}
+	a.log.Debug("search plan", "method", "searchArticles", "plan", plan.String())
+
+	hits, err := searcher.Search(r.Context(), plan, limit)
+	if err != nil {
+		a.writeError(w, err)
+		return
+	}
+
+	dtos := make([]hitDTO, 0, len(hits))
+	for _, hit := range hits {
+		ar := encoding.FromArticle(hit.Article)
+		dtos = append(dtos, hitDTO{
+			ID:          ar.ID,
+			Title:       ar.Title,
+			URL:         ar.URL,
+			Author:      ar.Author,
+			PublishedAt: ar.PublishedAt,
+			Highlights:  highlights(hl, hit),
+		})
+	}
+
+	a.writeJSON(w, http.StatusOK, dtos)
+}
+
+// parseHighlighter returns the highlighter configured by the request. If a
+// parameter is invalid, the message for the client is returned.
+func parseHighlighter(r *http.Request) (*highlight.Highlighter, string) {
+	params := r.URL.Query()
+	opts := []highlight.Option{highlight.WithEscaper(html.EscapeString)}
+
+	if param := params.Get("fragment_size"); param != "" {
+		n, err := strconv.Atoi(param)
+		if err != nil || n < 1 || n > maxFragmentSize {
+			return nil, "fragment_size must be a number between 1 and " + strconv.Itoa(maxFragmentSize)
+		}
+		opts = append(opts, highlight.WithFragmentSize(n))
+	}
+	if param := params.Get("fragments"); param != "" {
+		n, err := strconv.Atoi(param)
+		if err != nil || n < 0 || n > maxFragments {
+			return nil, "fragments must be a number between 0 and " + strconv.Itoa(maxFragments)
+		}
+		opts = append(opts, highlight.WithFragments(n))
+	}
+	if params.Has("pre_tag") || params.Has("post_tag") {
+		pre, post := params.Get("pre_tag"), params.Get("post_tag")
+		if len(pre) > maxTagLen || len(post) > maxTagLen {
+			return nil, "tags must not exceed " + strconv.Itoa(maxTagLen) + " bytes"
+		}
+		opts = append(opts, highlight.WithTags(pre, post))
+	}
+
+	return highlight.New(opts...), ""
+}
+
+// highlights returns the highlighted fragments of the fields of the hit.
+func highlights(hl *highlight.Highlighter, hit query.Hit) map[string][]string {
+	result := make(map[string][]string)
+
+	if title := hl.Highlight(hit.Article.Title, spans(hit.Matches[query.TextTitle])); title != "" {
+		result[string(query.TextTitle)] = []string{title}
+	}
+	texts := []struct {
+		field query.TextField
+		text  string
+	}{
+		{field: query.TextBody, text: hit.Article.Body},
+		{field: query.TextSummary, text: hit.Article.Summary},
+	}
+	for _, t := range texts {
+		if fragments := hl.Fragments(t.text, spans(hit.Matches[t.field])); len(fragments) > 0 {
+			result[string(t.field)] = fragments
+		}
+	}
+
+	if len(result) == 0 {
+		return nil
+	}
+	return result
+}
+
+func spans(matches []query.Match) []highlight.Span {
+	spans := make([]highlight.Span, 0, len(matches))
+	for _, m := range matches {
+		spans = append(spans, highlight.Span{Start: m.Start, End: m.End})
+	}
+	return spans
+}
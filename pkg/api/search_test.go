@@ -0,0 +1,334 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Br0ce/articleDB/pkg/article"
+	"github.com/Br0ce/articleDB/pkg/db/inmem"
+	"github.com/Br0ce/articleDB/pkg/encoding"
+	"github.com/Br0ce/articleDB/pkg/logger"
+	"github.com/Br0ce/articleDB/pkg/mock"
+	"github.com/Br0ce/articleDB/pkg/query"
+)
+
+func TestApi_searchArticles(t *testing.T) {
+	t.Parallel()
+
+	db := inmem.NewArticle()
+	a, err := New(logger.NewTest(false), WithDB(db))
+	if err != nil {
+		t.Fatalf("could not create api, %s", err.Error())
+	}
+
+	day := time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)
+	for _, ar := range []article.Article{
+		{
+			Title:     "Talks on the energy crisis",
+			Addr:      url.URL{Scheme: "https", Host: "news.example.com", Path: "/talks"},
+			Body:      "Olaf Scholz met the EU.",
+			Published: day,
+			NER: article.NER{Entities: append(article.EntitiesOf(article.Person, "Olaf Scholz"),
+				article.EntitiesOf(article.Organisation, "EU")...)},
+		},
+		{
+			Title:     "Energy crisis again",
+			Addr:      url.URL{Scheme: "https", Host: "news.example.com", Path: "/again"},
+			Body:      "The EU is worried.",
+			Published: day.AddDate(1, 0, 0),
+			NER:       article.NER{Entities: article.EntitiesOf(article.Organisation, "EU")},
+		},
+	} {
+		if _, err := db.Add(context.TODO(), ar); err != nil {
+			t.Fatalf("could not add article, %s", err.Error())
+		}
+	}
+
+	tests := []struct {
+		name         string
+		method       string
+		q            string
+		limit        string
+		wantStatus   int
+		wantTitles   []string
+		wantPosition int
+	}{
+		{
+			name:       "compound query",
+			method:     http.MethodGet,
+			q:          `person:"Olaf Scholz" AND org:EU AND published:[2023-01-01 TO 2023-06-30] AND "energy crisis"`,
+			wantStatus: http.StatusOK,
+			wantTitles: []string{"Talks on the energy crisis"},
+		},
+		{
+			name:       "latest first",
+			method:     http.MethodGet,
+			q:          `org:eu`,
+			wantStatus: http.StatusOK,
+			wantTitles: []string{"Energy crisis again", "Talks on the energy crisis"},
+		},
+		{
+			name:       "limit",
+			method:     http.MethodGet,
+			q:          `org:eu`,
+			limit:      "1",
+			wantStatus: http.StatusOK,
+			wantTitles: []string{"Energy crisis again"},
+		},
+		{
+			name:       "nothing found",
+			method:     http.MethodGet,
+			q:          `storm`,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:         "syntax error",
+			method:       http.MethodGet,
+			q:            `energy AND (org:EU`,
+			wantStatus:   http.StatusBadRequest,
+			wantPosition: 11,
+		},
+		{
+			name:       "missing query",
+			method:     http.MethodGet,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "invalid limit",
+			method:     http.MethodGet,
+			q:          `energy`,
+			limit:      "1000",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "wrong method",
+			method:     http.MethodPost,
+			q:          `energy`,
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := url.Values{}
+			if tt.q != "" {
+				params.Set("q", tt.q)
+			}
+			if tt.limit != "" {
+				params.Set("limit", tt.limit)
+			}
+
+			rec := httptest.NewRecorder()
+			a.ServeHTTP(rec, httptest.NewRequest(tt.method, "/articles/search?"+params.Encode(), nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %v, want %v, %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+
+			if tt.wantPosition > 0 {
+				var dto errorDTO
+				if err := encoding.DecodeJSON(rec.Body, &dto); err != nil {
+					t.Fatalf("could not decode body, %s", err.Error())
+				}
+				if dto.Position == nil || *dto.Position != tt.wantPosition {
+					t.Errorf("position = %v, want %v", dto.Position, tt.wantPosition)
+				}
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var dtos []hitDTO
+			if err := encoding.DecodeJSON(rec.Body, &dtos); err != nil {
+				t.Fatalf("could not decode body, %s", err.Error())
+			}
+			var got []string
+			for _, dto := range dtos {
+				got = append(got, dto.Title)
+			}
+			if !reflect.DeepEqual(got, tt.wantTitles) {
+				t.Errorf("titles = %v, want %v", got, tt.wantTitles)
+			}
+		})
+	}
+}
+
+func TestApi_searchArticles_unsupported(t *testing.T) {
+	t.Parallel()
+
+	a, err := New(logger.NewTest(false), WithDB(&mock.DB{}))
+	if err != nil {
+		t.Fatalf("could not create api, %s", err.Error())
+	}
+
+	rec := httptest.NewRecorder()
+	a.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/articles/search?q=energy", nil))
+	if rec.Code != http.StatusNotImplemented {
+		t.Errorf("status = %v, want %v", rec.Code, http.StatusNotImplemented)
+	}
+}
+
+func TestApi_searchArticles_highlights(t *testing.T) {
+	t.Parallel()
+
+	db := inmem.NewArticle()
+	a, err := New(logger.NewTest(false), WithDB(db))
+	if err != nil {
+		t.Fatalf("could not create api, %s", err.Error())
+	}
+
+	_, err = db.Add(context.TODO(), article.Article{
+		Title:   "Energy crisis & prices",
+		Addr:    url.URL{Scheme: "https", Host: "news.example.com", Path: "/prices"},
+		Body:    "Households pay more for energy. Experts expect a long winter. The crisis will end in spring.",
+		Summary: "Energy is expensive.",
+	})
+	if err != nil {
+		t.Fatalf("could not add article, %s", err.Error())
+	}
+
+	tests := []struct {
+		name       string
+		params     url.Values
+		wantStatus int
+		want       map[string][]string
+	}{
+		{
+			name:       "default tags",
+			params:     url.Values{"q": {"energy crisis"}},
+			wantStatus: http.StatusOK,
+			want: map[string][]string{
+				"title": {"<em>Energy</em> <em>crisis</em> &amp; prices"},
+				"body":  {"Households pay more for <em>energy</em>. Experts expect a long winter. The <em>crisis</em> will end in spring."},
+			},
+		},
+		{
+			name: "fragments and tags",
+			params: url.Values{
+				"q":             {"energy OR crisis"},
+				"fragment_size": {"30"},
+				"fragments":     {"2"},
+				"pre_tag":       {"["},
+				"post_tag":      {"]"},
+			},
+			wantStatus: http.StatusOK,
+			want: map[string][]string{
+				"title": {"[Energy] [crisis] &amp; prices"},
+				"body":  {"for [energy]. Experts expect a", "The [crisis] will end in"},
+			},
+		},
+		{
+			name:       "no fragments",
+			params:     url.Values{"q": {"winter"}, "fragments": {"0"}},
+			wantStatus: http.StatusOK,
+			want:       nil,
+		},
+		{
+			name:       "only positive terms",
+			params:     url.Values{"q": {"title:energy NOT winter OR spring"}},
+			wantStatus: http.StatusOK,
+			want: map[string][]string{
+				"title": {"<em>Energy</em> crisis &amp; prices"},
+				"body":  {"Households pay more for energy. Experts expect a long winter. The crisis will end in <em>spring</em>."},
+			},
+		},
+		{
+			name:       "terms in their field",
+			params:     url.Values{"q": {"summary:energy AND crisis"}},
+			wantStatus: http.StatusOK,
+			want: map[string][]string{
+				"title":   {"Energy <em>crisis</em> &amp; prices"},
+				"body":    {"Households pay more for energy. Experts expect a long winter. The <em>crisis</em> will end in spring."},
+				"summary": {"<em>Energy</em> is expensive."},
+			},
+		},
+		{
+			name:       "invalid fragment size",
+			params:     url.Values{"q": {"energy"}, "fragment_size": {"0"}},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "tag too long",
+			params:     url.Values{"q": {"energy"}, "pre_tag": {strings.Repeat("x", 33)}},
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			a.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/articles/search?"+tt.params.Encode(), nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %v, want %v, %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var dtos []hitDTO
+			if err := encoding.DecodeJSON(rec.Body, &dtos); err != nil {
+				t.Fatalf("could not decode body, %s", err.Error())
+			}
+			if len(dtos) != 1 {
+				t.Fatalf("hits = %v, want 1", len(dtos))
+			}
+			if !reflect.DeepEqual(dtos[0].Highlights, tt.want) {
+				t.Errorf("highlights = %q, want %q", dtos[0].Highlights, tt.want)
+			}
+		})
+	}
+}
+
+func TestApi_searchArticles_synonyms(t *testing.T) {
+	t.Parallel()
+
+	db := inmem.NewArticle()
+	synonyms := query.NewSynonyms([]string{"EU", "European Union"})
+	a, err := New(logger.NewTest(false), WithDB(db), WithSynonyms(synonyms))
+	if err != nil {
+		t.Fatalf("could not create api, %s", err.Error())
+	}
+
+	for _, ar := range []article.Article{
+		{
+			Title: "The EU meets",
+			Addr:  url.URL{Scheme: "https", Host: "news.example.com", Path: "/eu"},
+		},
+		{
+			Title: "The European Union meets",
+			Addr:  url.URL{Scheme: "https", Host: "news.example.com", Path: "/union"},
+		},
+	} {
+		if _, err := db.Add(context.TODO(), ar); err != nil {
+			t.Fatalf("could not add article, %s", err.Error())
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	a.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/articles/search?q=eu", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %v, want %v, %s", rec.Code, http.StatusOK, rec.Body.String())
+	}
+
+	var dtos []hitDTO
+	if err := encoding.DecodeJSON(rec.Body, &dtos); err != nil {
+		t.Fatalf("could not decode body, %s", err.Error())
+	}
+	var got []string
+	for _, dto := range dtos {
+		got = append(got, dto.Title)
+	}
+	sort.Strings(got)
+	want := []string{"The EU meets", "The European Union meets"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("titles = %v, want %v", got, want)
+	}
+}
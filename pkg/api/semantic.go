@@ -0,0 +1,60 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+)
+
+var errSemanticUnsupported = errors.New("semantic search is not configured")
+
+const (
+	defaultSemanticResults = 10
+	maxSemanticResults     = 100
+)
+
+type semanticDTO struct {
+	ID    string  `json:"id"`
+	Title string  `json:"title"`
+	Score float64 `json:"score"`
+}
+
+// semanticSearch handles GET /articles/semantic. It returns the k articles most
+// similar in meaning to the query q, the most similar first. It requires an
+// embedder, see WithEmbedder.
+func (a *Api) semanticSearch(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query().Get("q")
+	a.log.Info("semantic search", "method", "semanticSearch", "query", q)
+
+	if a.semantic == nil {
+		a.writeError(w, errSemanticUnsupported)
+		return
+	}
+
+	if q == "" {
+		a.writeBadRequest(w, "q is required")
+		return
+	}
+
+	k := defaultSemanticResults
+	if param := r.URL.Query().Get("k"); param != "" {
+		var err error
+		k, err = strconv.Atoi(param)
+		if err != nil || k < 1 || k > maxSemanticResults {
+			a.writeBadRequest(w, "k must be a number between 1 and "+strconv.Itoa(maxSemanticResults))
+			return
+		}
+	}
+
+	results, err := a.semantic.Search(r.Context(), q, k)
+	if err != nil {
+		a.writeError(w, err)
+		return
+	}
+
+	dtos := make([]semanticDTO, 0, len(results))
+	for _, res := range results {
+		dtos = append(dtos, semanticDTO{ID: res.Article.ID, Title: res.Article.Title, Score: res.Score})
+	}
+	a.writeJSON(w, http.StatusOK, dtos)
+}
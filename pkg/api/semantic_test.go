@@ -0,0 +1,203 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/Br0ce/articleDB/pkg/article"
+	"github.com/Br0ce/articleDB/pkg/db/inmem"
+	"github.com/Br0ce/articleDB/pkg/encoding"
+	"github.com/Br0ce/articleDB/pkg/extract/ngram"
+	"github.com/Br0ce/articleDB/pkg/logger"
+	"github.com/Br0ce/articleDB/pkg/vector/hnsw"
+)
+
+func TestApi_semanticSearch(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.TODO()
+	db := inmem.NewArticle()
+	emb := ngram.NewClient(0)
+
+	// An article stored before the api is created is reindexed by New.
+	vec, err := emb.Embed(ctx, "The storm flooded the coast.")
+	if err != nil {
+		t.Fatalf("could not embed, %s", err.Error())
+	}
+	_, err = db.Add(ctx, article.Article{
+		Title:     "Storm",
+		Addr:      url.URL{Scheme: "https", Host: "news.example.com", Path: "/storm"},
+		Body:      "The storm flooded the coast.",
+		Embedding: vec,
+	})
+	if err != nil {
+		t.Fatalf("could not add article, %s", err.Error())
+	}
+
+	a, err := New(logger.NewTest(false), WithDB(db), WithEmbedder(emb))
+	if err != nil {
+		t.Fatalf("could not create api, %s", err.Error())
+	}
+	_, err = a.adder.Add(ctx, article.Article{
+		Title: "Rates",
+		Addr:  url.URL{Scheme: "https", Host: "news.example.com", Path: "/rates"},
+		Body:  "The central bank raised interest rates again.",
+	})
+	if err != nil {
+		t.Fatalf("could not add article, %s", err.Error())
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		target     string
+		wantStatus int
+		wantTitles []string
+	}{
+		{
+			name:       "added article first",
+			method:     http.MethodGet,
+			target:     "/articles/semantic?q=" + url.QueryEscape("interest rates of the bank"),
+			wantStatus: http.StatusOK,
+			wantTitles: []string{"Rates", "Storm"},
+		},
+		{
+			name:       "reindexed article first",
+			method:     http.MethodGet,
+			target:     "/articles/semantic?k=1&q=" + url.QueryEscape("flooded coast"),
+			wantStatus: http.StatusOK,
+			wantTitles: []string{"Storm"},
+		},
+		{
+			name:       "missing query",
+			method:     http.MethodGet,
+			target:     "/articles/semantic",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "invalid k",
+			method:     http.MethodGet,
+			target:     "/articles/semantic?q=storm&k=0",
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			a.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.target, nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %v, want %v, %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var dtos []semanticDTO
+			if err := encoding.DecodeJSON(rec.Body, &dtos); err != nil {
+				t.Fatalf("could not decode body, %s", err.Error())
+			}
+			var got []string
+			for _, dto := range dtos {
+				got = append(got, dto.Title)
+			}
+			if len(got) != len(tt.wantTitles) || got[0] != tt.wantTitles[0] {
+				t.Errorf("titles = %v, want %v", got, tt.wantTitles)
+			}
+		})
+	}
+}
+
+func TestApi_semanticSearch_restored(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.TODO()
+	emb := ngram.NewClient(0)
+	src, err := New(logger.NewTest(false), WithEmbedder(emb))
+	if err != nil {
+		t.Fatalf("could not create api, %s", err.Error())
+	}
+	_, err = src.adder.Add(ctx, article.Article{
+		Title: "Storm",
+		Addr:  url.URL{Scheme: "https", Host: "news.example.com", Path: "/storm"},
+		Body:  "The storm flooded the coast.",
+	})
+	if err != nil {
+		t.Fatalf("could not add article, %s", err.Error())
+	}
+	rec := httptest.NewRecorder()
+	src.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/articles:export", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("export status = %v", rec.Code)
+	}
+
+	a, err := New(logger.NewTest(false), WithEmbedder(emb))
+	if err != nil {
+		t.Fatalf("could not create api, %s", err.Error())
+	}
+	restored := httptest.NewRecorder()
+	a.ServeHTTP(restored, httptest.NewRequest(http.MethodPost, "/articles:restore", rec.Body))
+	if restored.Code != http.StatusOK {
+		t.Fatalf("restore status = %v, %s", restored.Code, restored.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	a.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/articles/semantic?q="+url.QueryEscape("flooded coast"), nil))
+	var dtos []semanticDTO
+	if err := encoding.DecodeJSON(rec.Body, &dtos); err != nil {
+		t.Fatalf("could not decode body, %s", err.Error())
+	}
+	if len(dtos) != 1 || dtos[0].Title != "Storm" {
+		t.Errorf("results = %+v, want Storm", dtos)
+	}
+}
+
+func TestApi_semanticSearch_unsupported(t *testing.T) {
+	t.Parallel()
+
+	a, err := New(logger.NewTest(false))
+	if err != nil {
+		t.Fatalf("could not create api, %s", err.Error())
+	}
+
+	rec := httptest.NewRecorder()
+	a.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/articles/semantic?q=storm", nil))
+	if rec.Code != http.StatusNotImplemented {
+		t.Errorf("status = %v, want %v", rec.Code, http.StatusNotImplemented)
+	}
+}
+
+func TestApi_semanticSearch_vectorIndex(t *testing.T) {
+	t.Parallel()
+
+	idx := hnsw.New()
+	a, err := New(logger.NewTest(false), WithEmbedder(ngram.NewClient(0)), WithVectorIndex(idx))
+	if err != nil {
+		t.Fatalf("could not create api, %s", err.Error())
+	}
+	_, err = a.adder.Add(context.TODO(), article.Article{
+		Title: "Rates",
+		Addr:  url.URL{Scheme: "https", Host: "news.example.com", Path: "/rates"},
+		Body:  "The central bank raised interest rates again.",
+	})
+	if err != nil {
+		t.Fatalf("could not add article, %s", err.Error())
+	}
+	if idx.Len() != 1 {
+		t.Fatalf("Index.Len() = %v, want 1", idx.Len())
+	}
+
+	rec := httptest.NewRecorder()
+	a.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/articles/semantic?q=rates", nil))
+	var dtos []semanticDTO
+	if err := encoding.DecodeJSON(rec.Body, &dtos); err != nil {
+		t.Fatalf("could not decode body, %s", err.Error())
+	}
+	if len(dtos) != 1 || dtos[0].Title != "Rates" {
+		t.Errorf("results = %+v, want Rates", dtos)
+	}
+}
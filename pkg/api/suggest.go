@@ -0,0 +1,77 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Br0ce/articleDB/pkg/suggest"
+)
+
+var errSuggestUnsupported = errors.New("suggestions are not supported by the db")
+
+const (
+	defaultSuggestLimit = 10
+	maxSuggestLimit     = 50
+)
+
+type suggestionDTO struct {
+	Text string `json:"text"`
+	// Kind is title or entity.
+	Kind string `json:"kind"`
+	// Type is the type of an entity.
+	Type  string `json:"type,omitempty"`
+	Count int    `json:"count"`
+	// Distance is the edit distance of a misspelled prefix.
+	Distance int `json:"distance,omitempty"`
+}
+
+// suggest handles GET /suggest. It completes the prefix to titles and entity
+// names, the number of suggestions can be set with limit. Exact completions come
+// first, misspelled prefixes are completed within a small edit distance.
+func (a *Api) suggest(w http.ResponseWriter, r *http.Request) {
+	a.allow(w, r, http.MethodGet, func() {
+		prefix := r.URL.Query().Get("prefix")
+		a.log.Info("suggest", "method", "suggest", "prefix", prefix)
+
+		suggester, ok := a.db.(suggest.Suggester)
+		if !ok {
+			a.writeError(w, errSuggestUnsupported)
+			return
+		}
+
+		if prefix == "" {
+			a.writeBadRequest(w, "prefix is required")
+			return
+		}
+
+		limit := defaultSuggestLimit
+		if param := r.URL.Query().Get("limit"); param != "" {
+			var err error
+			limit, err = strconv.Atoi(param)
+			if err != nil || limit < 1 || limit > maxSuggestLimit {
+				a.writeBadRequest(w, "limit must be a number between 1 and "+strconv.Itoa(maxSuggestLimit))
+				return
+			}
+		}
+
+		suggestions, err := suggester.Suggest(r.Context(), prefix, limit)
+		if err != nil {
+			a.writeError(w, err)
+			return
+		}
+
+		dtos := make([]suggestionDTO, 0, len(suggestions))
+		for _, s := range suggestions {
+			dtos = append(dtos, suggestionDTO{
+				Text:     s.Text,
+				Kind:     string(s.Kind),
+				Type:     string(s.Type),
+				Count:    s.Count,
+				Distance: s.Distance,
+			})
+		}
+
+		a.writeJSON(w, http.StatusOK, dtos)
+	})
+}
@@ -0,0 +1,148 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/Br0ce/articleDB/pkg/article"
+	"github.com/Br0ce/articleDB/pkg/db/inmem"
+	"github.com/Br0ce/articleDB/pkg/encoding"
+	"github.com/Br0ce/articleDB/pkg/logger"
+	"github.com/Br0ce/articleDB/pkg/mock"
+)
+
+func TestApi_suggest(t *testing.T) {
+	t.Parallel()
+
+	db := inmem.NewArticle()
+	a, err := New(logger.NewTest(false), WithDB(db))
+	if err != nil {
+		t.Fatalf("could not create api, %s", err.Error())
+	}
+
+	for _, ar := range []article.Article{
+		{
+			Title: "Scholz visits Paris",
+			Addr:  url.URL{Scheme: "https", Host: "news.example.com", Path: "/paris"},
+			NER:   article.NER{Entities: article.EntitiesOf(article.Person, "Olaf Scholz")},
+		},
+		{
+			Title: "Energy prices",
+			Addr:  url.URL{Scheme: "https", Host: "news.example.com", Path: "/prices"},
+			NER:   article.NER{Entities: article.EntitiesOf(article.Person, "Olaf Scholz")},
+		},
+	} {
+		if _, err := db.Add(context.TODO(), ar); err != nil {
+			t.Fatalf("could not add article, %s", err.Error())
+		}
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		prefix     string
+		limit      string
+		wantStatus int
+		want       []suggestionDTO
+	}{
+		{
+			name:       "prefix",
+			method:     http.MethodGet,
+			prefix:     "scho",
+			wantStatus: http.StatusOK,
+			want: []suggestionDTO{
+				{Text: "Olaf Scholz", Kind: "entity", Type: "person", Count: 2},
+				{Text: "Scholz visits Paris", Kind: "title", Count: 1},
+			},
+		},
+		{
+			name:       "misspelled",
+			method:     http.MethodGet,
+			prefix:     "Olaf Sholz",
+			wantStatus: http.StatusOK,
+			want:       []suggestionDTO{{Text: "Olaf Scholz", Kind: "entity", Type: "person", Count: 2, Distance: 1}},
+		},
+		{
+			name:       "limit",
+			method:     http.MethodGet,
+			prefix:     "scho",
+			limit:      "1",
+			wantStatus: http.StatusOK,
+			want:       []suggestionDTO{{Text: "Olaf Scholz", Kind: "entity", Type: "person", Count: 2}},
+		},
+		{
+			name:       "nothing found",
+			method:     http.MethodGet,
+			prefix:     "storm",
+			wantStatus: http.StatusOK,
+			want:       []suggestionDTO{},
+		},
+		{
+			name:       "missing prefix",
+			method:     http.MethodGet,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "invalid limit",
+			method:     http.MethodGet,
+			prefix:     "scho",
+			limit:      "51",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "wrong method",
+			method:     http.MethodPost,
+			prefix:     "scho",
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := url.Values{}
+			if tt.prefix != "" {
+				params.Set("prefix", tt.prefix)
+			}
+			if tt.limit != "" {
+				params.Set("limit", tt.limit)
+			}
+
+			rec := httptest.NewRecorder()
+			a.ServeHTTP(rec, httptest.NewRequest(tt.method, "/suggest?"+params.Encode(), nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %v, want %v, %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var got []suggestionDTO
+			if err := encoding.DecodeJSON(rec.Body, &got); err != nil {
+				t.Fatalf("could not decode body, %s", err.Error())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("suggestions = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApi_suggest_unsupported(t *testing.T) {
+	t.Parallel()
+
+	a, err := New(logger.NewTest(false), WithDB(&mock.DB{}))
+	if err != nil {
+		t.Fatalf("could not create api, %s", err.Error())
+	}
+
+	rec := httptest.NewRecorder()
+	a.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/suggest?prefix=scho", nil))
+	if rec.Code != http.StatusNotImplemented {
+		t.Errorf("status = %v, want %v", rec.Code, http.StatusNotImplemented)
+	}
+}
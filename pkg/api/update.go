@@ -0,0 +1,71 @@
+package api
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/Br0ce/articleDB/pkg/article"
+	"github.com/Br0ce/articleDB/pkg/encoding"
+)
+
+// maxArticleSize limits the size of a posted article.
+const maxArticleSize = 4 << 20
+
+type editDTO struct {
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
+
+type changeDTO struct {
+	Field string    `json:"field"`
+	Old   string    `json:"old,omitempty"`
+	New   string    `json:"new,omitempty"`
+	Edits []editDTO `json:"edits,omitempty"`
+}
+
+type diffDTO struct {
+	ID      string      `json:"id"`
+	Changes []changeDTO `json:"changes"`
+}
+
+// updateArticle handles PUT /articles/{id}. The body is an article in the wire
+// schema, its original fields replace the fields of the stored article. The
+// response lists the changed fields, the body is given as word diff.
+func (a *Api) updateArticle(w http.ResponseWriter, r *http.Request, id string) {
+	a.log.Info("update article", "method", "updateArticle", "articleID", id)
+
+	data, err := io.ReadAll(io.LimitReader(r.Body, maxArticleSize))
+	if err != nil {
+		a.writeBadRequest(w, "could not read body")
+		return
+	}
+	ar, err := encoding.UnmarshalArticle(data)
+	if err != nil {
+		a.writeError(w, err)
+		return
+	}
+	ar.ID = id
+
+	diff, err := a.adder.Update(r.Context(), ar)
+	if err != nil {
+		a.writeError(w, err)
+		return
+	}
+
+	a.writeJSON(w, http.StatusOK, toDiffDTO(id, diff))
+}
+
+func toDiffDTO(id string, diff article.Diff) diffDTO {
+	dto := diffDTO{ID: id, Changes: make([]changeDTO, 0, len(diff.Changes))}
+	for _, c := range diff.Changes {
+		change := changeDTO{Field: string(c.Field)}
+		if len(c.Edits) == 0 {
+			change.Old, change.New = c.Old, c.New
+		}
+		for _, e := range c.Edits {
+			change.Edits = append(change.Edits, editDTO{Op: e.Op.String(), Text: e.Text})
+		}
+		dto.Changes = append(dto.Changes, change)
+	}
+	return dto
+}
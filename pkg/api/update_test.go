@@ -0,0 +1,115 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Br0ce/articleDB/pkg/article"
+	"github.com/Br0ce/articleDB/pkg/db/inmem"
+	"github.com/Br0ce/articleDB/pkg/encoding"
+	"github.com/Br0ce/articleDB/pkg/ids"
+	"github.com/Br0ce/articleDB/pkg/logger"
+)
+
+func TestApi_updateArticle(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.TODO()
+	db := inmem.NewArticle()
+	id, err := db.Add(ctx, article.Article{
+		Title: "Storm warning",
+		Addr:  url.URL{Scheme: "https", Host: "weather.example.com", Path: "/storm"},
+		Body:  "Heavy rain is expected.",
+	})
+	if err != nil {
+		t.Fatalf("could not add article, %s", err.Error())
+	}
+
+	a, err := New(logger.NewTest(false), WithDB(db))
+	if err != nil {
+		t.Fatalf("could not create api, %s", err.Error())
+	}
+
+	tests := []struct {
+		name        string
+		id          string
+		body        string
+		wantStatus  int
+		wantChanges []changeDTO
+	}{
+		{
+			name:       "changed",
+			id:         id,
+			body:       `{"title": "Storm warning", "url": "https://weather.example.com/storm", "author": "Jane Doe", "body": "Heavy snow is expected."}`,
+			wantStatus: http.StatusOK,
+			wantChanges: []changeDTO{
+				{Field: "author", New: "Jane Doe"},
+				{Field: "body", Edits: []editDTO{
+					{Op: "equal", Text: "Heavy "},
+					{Op: "delete", Text: "rain"},
+					{Op: "insert", Text: "snow"},
+					{Op: "equal", Text: " is expected."},
+				}},
+			},
+		},
+		{
+			name:        "unchanged",
+			id:          id,
+			body:        `{"title": "Storm warning", "url": "https://weather.example.com/storm", "author": "Jane Doe", "body": "Heavy snow is expected."}`,
+			wantStatus:  http.StatusOK,
+			wantChanges: []changeDTO{},
+		},
+		{
+			name:       "invalid article",
+			id:         id,
+			body:       `{"title": "Storm warning", "url": "https://weather.example.com/storm"}`,
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "invalid json",
+			id:         id,
+			body:       `{"title": `,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "not found",
+			id:         ids.UniqueID(),
+			body:       `{"title": "Storm", "url": "https://weather.example.com/other", "body": "Rain."}`,
+			wantStatus: http.StatusNotFound,
+		},
+	}
+
+	// The cases depend on each other, so they are not run in parallel.
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		a.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/articles/"+tt.id, strings.NewReader(tt.body)))
+
+		if rec.Code != tt.wantStatus {
+			t.Fatalf("%s: status = %v, want %v, body %s", tt.name, rec.Code, tt.wantStatus, rec.Body.String())
+		}
+		if tt.wantStatus != http.StatusOK {
+			continue
+		}
+
+		var dto diffDTO
+		if err := encoding.DecodeJSON(rec.Body, &dto); err != nil {
+			t.Fatalf("could not decode body, %s", err.Error())
+		}
+		if !reflect.DeepEqual(dto.Changes, tt.wantChanges) {
+			t.Errorf("%s: changes = %+v, want %+v", tt.name, dto.Changes, tt.wantChanges)
+		}
+	}
+
+	got, err := db.Get(ctx, id)
+	if err != nil {
+		t.Fatalf("could not get article, %s", err.Error())
+	}
+	if got.Author != "Jane Doe" || got.Body != "Heavy snow is expected." {
+		t.Errorf("updated article = %+v", got)
+	}
+}
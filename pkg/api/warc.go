@@ -0,0 +1,38 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/Br0ce/articleDB/pkg/warc"
+)
+
+type warcSummaryDTO struct {
+	Records int `json:"records"`
+	Pages   int `json:"pages"`
+	Added   int `json:"added"`
+	Skipped int `json:"skipped"`
+	Failed  int `json:"failed"`
+}
+
+// importWARC handles POST /articles:warc. The body is a WARC file, the main article
+// of every captured html page is added.
+func (a *Api) importWARC(w http.ResponseWriter, r *http.Request) {
+	a.allow(w, r, http.MethodPost, func() {
+		a.log.Info("import warc", "method", "importWARC")
+
+		importer := warc.NewImporter(a.adder, a.log.With("name", "warc"))
+		sum, err := importer.Import(r.Context(), r.Body)
+		if err != nil {
+			a.writeError(w, err)
+			return
+		}
+
+		a.writeJSON(w, http.StatusOK, warcSummaryDTO{
+			Records: sum.Records,
+			Pages:   sum.Pages,
+			Added:   sum.Added,
+			Skipped: sum.Skipped,
+			Failed:  sum.Failed,
+		})
+	})
+}
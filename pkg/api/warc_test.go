@@ -0,0 +1,95 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/Br0ce/articleDB/pkg/db/inmem"
+	"github.com/Br0ce/articleDB/pkg/encoding"
+	"github.com/Br0ce/articleDB/pkg/logger"
+	"github.com/Br0ce/articleDB/pkg/warc"
+)
+
+func TestApi_importWARC(t *testing.T) {
+	t.Parallel()
+
+	data, err := os.ReadFile("../warc/testdata/example.warc")
+	if err != nil {
+		t.Fatalf("could not read fixture, %s", err.Error())
+	}
+
+	db := inmem.NewArticle()
+	a, err := New(logger.NewTest(false), WithDB(db))
+	if err != nil {
+		t.Fatalf("could not create api, %s", err.Error())
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		body       []byte
+		wantStatus int
+		want       warcSummaryDTO
+	}{
+		{
+			name:       "pass",
+			method:     http.MethodPost,
+			body:       data,
+			wantStatus: http.StatusOK,
+			want:       warcSummaryDTO{Records: 8, Pages: 3, Added: 2, Skipped: 1},
+		},
+		{
+			name:       "again",
+			method:     http.MethodPost,
+			body:       data,
+			wantStatus: http.StatusOK,
+			want:       warcSummaryDTO{Records: 8, Pages: 3, Skipped: 3},
+		},
+		{
+			name:       "invalid warc",
+			method:     http.MethodPost,
+			body:       []byte("no warc\r\n"),
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "method not allowed",
+			method:     http.MethodGet,
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	// The cases depend on each other, so they are not run in parallel.
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		a.ServeHTTP(rec, httptest.NewRequest(tt.method, "/articles:warc", bytes.NewReader(tt.body)))
+
+		if rec.Code != tt.wantStatus {
+			t.Fatalf("%s: status = %v, want %v, body %s", tt.name, rec.Code, tt.wantStatus, rec.Body.String())
+		}
+		if tt.wantStatus != http.StatusOK {
+			continue
+		}
+
+		var got warcSummaryDTO
+		if err := encoding.DecodeJSON(rec.Body, &got); err != nil {
+			t.Fatalf("could not decode body, %s", err.Error())
+		}
+		if got != tt.want {
+			t.Errorf("%s: summary = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+
+	items, err := db.List(context.TODO())
+	if err != nil {
+		t.Fatalf("could not list articles, %s", err.Error())
+	}
+	for _, ar := range items {
+		if ar.Source.Kind != warc.SourceKind || ar.Source.ID == "" || ar.Source.Captured.IsZero() {
+			t.Errorf("Article.Source = %+v", ar.Source)
+		}
+	}
+}
@@ -0,0 +1,193 @@
+// Package archive writes and reads backups of the article store. An archive is a
+// gzip compressed NDJSON file. The first line is a header with the format version,
+// every further line holds an article with all its enriched fields in the wire
+// schema of the encoding package, or a revision of the article before it.
+package archive
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/Br0ce/articleDB/pkg/article"
+	"github.com/Br0ce/articleDB/pkg/encoding"
+)
+
+const (
+	// Format identifies an archive.
+	Format = "articledb-archive"
+	// Version is the version of the archive format written by a Writer.
+	Version = 1
+)
+
+var (
+	ErrInvalidArchive     = errors.New("invalid archive")
+	ErrUnsupportedVersion = errors.New("unsupported archive version")
+)
+
+// Header is the first line of an archive.
+type Header struct {
+	Format  string    `json:"format"`
+	Version int       `json:"version"`
+	Created time.Time `json:"created"`
+}
+
+// Writer writes an archive.
+type Writer struct {
+	zw  *gzip.Writer
+	enc *json.Encoder
+}
+
+// NewWriter writes the header of an archive to w and returns a Writer for its
+// articles. The archive is only complete after Close.
+func NewWriter(w io.Writer) (*Writer, error) {
+	zw := gzip.NewWriter(w)
+	enc := json.NewEncoder(zw)
+
+	err := enc.Encode(Header{Format: Format, Version: Version, Created: time.Now().UTC()})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Writer{zw: zw, enc: enc}, nil
+}
+
+// Write adds the article to the archive.
+func (w *Writer) Write(ar article.Article) error {
+	return w.enc.Encode(encoding.FromArticle(ar))
+}
+
+// WriteRevision adds the revision to the archive. The revisions of an article must
+// be written right after the article.
+func (w *Writer) WriteRevision(rev article.Revision) error {
+	return w.enc.Encode(revisionLine{Revision: fromRevision(rev)})
+}
+
+// Close flushes the archive. It does not close the underlying writer.
+func (w *Writer) Close() error {
+	return w.zw.Close()
+}
+
+// Reader reads an archive.
+type Reader struct {
+	Header Header
+
+	zr    *gzip.Reader
+	lines *bufio.Reader
+	line  int
+	// pending is the line read ahead while looking for revisions.
+	pending   []byte
+	revisions []article.Revision
+}
+
+// NewReader reads the header of the archive in r and returns a Reader for its
+// articles. Archives of another version are rejected with ErrUnsupportedVersion.
+func NewReader(r io.Reader) (*Reader, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("%s, %w", err.Error(), ErrInvalidArchive)
+	}
+
+	rd := &Reader{zr: zr, lines: bufio.NewReader(zr)}
+
+	data, err := rd.next()
+	if err != nil {
+		return nil, fmt.Errorf("could not read header, %w", ErrInvalidArchive)
+	}
+	err = json.Unmarshal(data, &rd.Header)
+	if err != nil || rd.Header.Format != Format {
+		return nil, fmt.Errorf("no archive header, %w", ErrInvalidArchive)
+	}
+	if rd.Header.Version != Version {
+		return nil, fmt.Errorf("version %d, %w", rd.Header.Version, ErrUnsupportedVersion)
+	}
+
+	return rd, nil
+}
+
+// Next returns the next article of the archive. It returns io.EOF, if there are no
+// more articles. The revisions of the article are returned by Revisions until the
+// next call of Next.
+func (r *Reader) Next() (article.Article, error) {
+	r.revisions = nil
+
+	data := r.pending
+	r.pending = nil
+	if data == nil {
+		var err error
+		data, err = r.next()
+		if err != nil {
+			return article.Article{}, err
+		}
+	}
+	if isRevision(data) {
+		return article.Article{}, fmt.Errorf("line %d, revision without article, %w", r.line, ErrInvalidArchive)
+	}
+
+	ar, err := r.article(data)
+	if err != nil {
+		return article.Article{}, err
+	}
+
+	for {
+		data, err := r.next()
+		if errors.Is(err, io.EOF) {
+			return ar, nil
+		}
+		if err != nil {
+			return article.Article{}, err
+		}
+		if !isRevision(data) {
+			r.pending = data
+			return ar, nil
+		}
+
+		rev, err := toRevision(data)
+		if err != nil || rev.ArticleID != ar.ID || rev.Number < 1 {
+			return article.Article{}, fmt.Errorf("line %d, invalid revision, %w", r.line, ErrInvalidArchive)
+		}
+		r.revisions = append(r.revisions, rev)
+	}
+}
+
+// Revisions returns the revisions of the article returned by the last call of Next.
+func (r *Reader) Revisions() []article.Revision {
+	return r.revisions
+}
+
+// article decodes an article line.
+func (r *Reader) article(data []byte) (article.Article, error) {
+	ar, err := encoding.UnmarshalArticle(data)
+	if err != nil {
+		return article.Article{}, fmt.Errorf("line %d, %s, %w", r.line, err.Error(), ErrInvalidArchive)
+	}
+
+	return ar, nil
+}
+
+// next returns the next non-empty line.
+func (r *Reader) next() ([]byte, error) {
+	for {
+		data, err := r.lines.ReadBytes('\n')
+		if len(data) == 0 && err != nil {
+			return nil, err
+		}
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, err
+		}
+		r.line++
+
+		if len(data) > 1 || (len(data) == 1 && data[0] != '\n') {
+			return data, nil
+		}
+	}
+}
+
+// Close closes the decompression. It does not close the underlying reader.
+func (r *Reader) Close() error {
+	return r.zr.Close()
+}
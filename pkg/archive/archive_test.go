@@ -0,0 +1,285 @@
+package archive
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Br0ce/articleDB/pkg/article"
+	"github.com/Br0ce/articleDB/pkg/db"
+	"github.com/Br0ce/articleDB/pkg/db/inmem"
+	"github.com/Br0ce/articleDB/pkg/ids"
+)
+
+func testArticles() []article.Article {
+	date := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+	return []article.Article{
+		{
+			ID:        ids.UniqueID(),
+			Title:     "Energy prices keep rising",
+			Addr:      url.URL{Scheme: "https", Host: "news.example.com", Path: "/energy-prices"},
+			Author:    "Jane Doe",
+			Created:   date.Add(time.Hour),
+			Updated:   date.Add(2 * time.Hour),
+			Published: date,
+			Body:      "Gas and electricity prices rose again.",
+			Summary:   "Prices rose.",
+			Keywords:  []string{"energy", "prices"},
+			NER: article.NER{Entities: []article.Entity{
+				{Name: "Jane Doe", Type: article.Person, Count: 1, Confidence: 0.9},
+				{Name: "Berlin", Type: article.Location, Count: 1},
+				{Name: "EU", Type: article.Organisation, Count: 1},
+			}},
+			Fingerprint: 1 << 63,
+			Embedding:   []float32{0.6, 0.8},
+			Source: article.Source{
+				Kind:     "warc",
+				ID:       "urn:uuid:d9f4a7a2-6f55-4b8e-9f0b-3c1e2a4b5c6d",
+				Captured: date.Add(-time.Hour),
+			},
+		},
+		{
+			ID:    ids.UniqueID(),
+			Title: "Storm warning",
+			Addr:  url.URL{Scheme: "https", Host: "news.example.com", Path: "/storm"},
+			Body:  "Heavy rain is expected.",
+		},
+	}
+}
+
+// testRevisions returns two revisions of ar, the second one changes the title and
+// the body.
+func testRevisions(ar article.Article) []article.Revision {
+	old := ar
+	old.Title = "Energy prices rise"
+	old.Body = "Gas prices rose again."
+	old.Embedding = nil
+	current := ar
+	current.Embedding = nil
+
+	date := time.Date(2023, 6, 1, 13, 0, 0, 0, time.UTC)
+	return []article.Revision{
+		{ArticleID: ar.ID, Number: 1, Created: date, Article: old},
+		{ArticleID: ar.ID, Number: 2, Created: date.Add(time.Hour), Article: current, Diff: old.Diff(current)},
+	}
+}
+
+func TestWriterReader(t *testing.T) {
+	t.Parallel()
+
+	want := testArticles()
+	wantRevs := testRevisions(want[0])
+
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf)
+	if err != nil {
+		t.Fatalf("NewWriter() error = %v", err)
+	}
+	for i, ar := range want {
+		if err := w.Write(ar); err != nil {
+			t.Fatalf("Writer.Write() error = %v", err)
+		}
+		if i > 0 {
+			continue
+		}
+		for _, rev := range wantRevs {
+			if err := w.WriteRevision(rev); err != nil {
+				t.Fatalf("Writer.WriteRevision() error = %v", err)
+			}
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Writer.Close() error = %v", err)
+	}
+
+	r, err := NewReader(&buf)
+	if err != nil {
+		t.Fatalf("NewReader() error = %v", err)
+	}
+	if r.Header.Format != Format || r.Header.Version != Version || r.Header.Created.IsZero() {
+		t.Errorf("Reader.Header = %+v", r.Header)
+	}
+
+	var got []article.Article
+	var gotRevs []article.Revision
+	for {
+		ar, err := r.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Reader.Next() error = %v", err)
+		}
+		got = append(got, ar)
+		gotRevs = append(gotRevs, r.Revisions()...)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Reader.Next() = %+v, want %+v", got, want)
+	}
+	if !reflect.DeepEqual(gotRevs, wantRevs) {
+		t.Errorf("Reader.Revisions() = %+v, want %+v", gotRevs, wantRevs)
+	}
+}
+
+func TestReader_Next_invalid(t *testing.T) {
+	t.Parallel()
+
+	header := `{"format":"articledb-archive","version":1}` + "\n"
+	ar := `{"id":"6f1c2d3e-4b5a-4c6d-8e7f-9a0b1c2d3e4f","title":"Energy","url":"https://news.example.com/energy","body":"Prices rose."}` + "\n"
+	rev := `{"revision":{"article_id":"6f1c2d3e-4b5a-4c6d-8e7f-9a0b1c2d3e4f","number":1,"created":"2023-06-01T12:00:00Z","article":` +
+		strings.TrimSpace(ar) + `}}` + "\n"
+
+	truncated := gzipped(t, header+ar+ar).Bytes()
+	truncated = truncated[:len(truncated)-8]
+
+	tests := []struct {
+		name    string
+		data    io.Reader
+		wantErr error
+	}{
+		{name: "truncated", data: bytes.NewReader(truncated), wantErr: io.ErrUnexpectedEOF},
+		{name: "revision without article", data: gzipped(t, header+rev), wantErr: ErrInvalidArchive},
+		{name: "revision of other article", data: gzipped(t, header+ar+strings.Replace(rev, "6f1c", "7f1c", 1)), wantErr: ErrInvalidArchive},
+		{name: "revision without number", data: gzipped(t, header+ar+strings.Replace(rev, `"number":1`, `"number":0`, 1)), wantErr: ErrInvalidArchive},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := NewReader(tt.data)
+			if err != nil {
+				t.Fatalf("NewReader() error = %v", err)
+			}
+			for err == nil {
+				_, err = r.Next()
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Reader.Next() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func gzipped(t *testing.T, data string) *bytes.Buffer {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write([]byte(data)); err != nil {
+		t.Fatalf("could not compress, %s", err.Error())
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("could not compress, %s", err.Error())
+	}
+	return &buf
+}
+
+func TestNewReader(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		data    io.Reader
+		wantErr error
+	}{
+		{
+			name:    "not compressed",
+			data:    bytes.NewBufferString(`{"format":"articledb-archive","version":1}`),
+			wantErr: ErrInvalidArchive,
+		},
+		{
+			name:    "no header",
+			data:    gzipped(t, `{"id":"1234"}`+"\n"),
+			wantErr: ErrInvalidArchive,
+		},
+		{
+			name:    "empty",
+			data:    gzipped(t, ""),
+			wantErr: ErrInvalidArchive,
+		},
+		{
+			name:    "newer version",
+			data:    gzipped(t, `{"format":"articledb-archive","version":2}`+"\n"),
+			wantErr: ErrUnsupportedVersion,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewReader(tt.data)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("NewReader() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestExportRestore(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.TODO()
+	src := inmem.NewArticle()
+	articles := testArticles()
+	for _, ar := range articles {
+		if err := src.Restore(ctx, ar); err != nil {
+			t.Fatalf("could not restore article, %s", err.Error())
+		}
+	}
+
+	srcRevs := inmem.NewRevision()
+	wantRevs := testRevisions(articles[0])
+	for _, rev := range wantRevs {
+		if err := srcRevs.Restore(ctx, rev); err != nil {
+			t.Fatalf("could not restore revision, %s", err.Error())
+		}
+	}
+
+	var buf bytes.Buffer
+	n, err := Export(ctx, &buf, src, srcRevs)
+	if err != nil || n != 2 {
+		t.Fatalf("Export() = %v, %v", n, err)
+	}
+	backup := buf.Bytes()
+
+	dst := inmem.NewArticle()
+	dstRevs := inmem.NewRevision()
+	n, err = Restore(ctx, bytes.NewReader(backup), dst, dstRevs)
+	if err != nil || n != 2 {
+		t.Fatalf("Restore() = %v, %v", n, err)
+	}
+
+	gotRevs, err := dstRevs.List(ctx, wantRevs[0].ArticleID)
+	if err != nil {
+		t.Fatalf("could not list revisions, %s", err.Error())
+	}
+	if !reflect.DeepEqual(gotRevs, wantRevs) {
+		t.Errorf("restored revisions = %+v, want %+v", gotRevs, wantRevs)
+	}
+
+	want, err := src.List(ctx)
+	if err != nil {
+		t.Fatalf("could not list articles, %s", err.Error())
+	}
+	got, err := dst.List(ctx)
+	if err != nil {
+		t.Fatalf("could not list articles, %s", err.Error())
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("restored = %+v, want %+v", got, want)
+	}
+
+	// Restoring into a store with other articles at the same addrs fails.
+	other := inmem.NewArticle()
+	if _, err := other.Add(ctx, article.Article{Addr: want[0].Addr}); err != nil {
+		t.Fatalf("could not add article, %s", err.Error())
+	}
+	_, err = Restore(ctx, bytes.NewReader(backup), other, nil)
+	if !errors.Is(err, db.ErrAlreadyExists) {
+		t.Errorf("Restore() error = %v, want %v", err, db.ErrAlreadyExists)
+	}
+}
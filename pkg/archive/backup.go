@@ -0,0 +1,114 @@
+package archive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/Br0ce/articleDB/pkg/article"
+)
+
+// Lister lists all stored articles.
+type Lister interface {
+	List(ctx context.Context) ([]article.Article, error)
+}
+
+// Restorer stores an article under its own ID, e.g. the inmem.Article.
+type Restorer interface {
+	Restore(ctx context.Context, ar article.Article) error
+}
+
+// RevisionLister lists the revisions of an article, e.g. the inmem.Revision.
+type RevisionLister interface {
+	List(ctx context.Context, articleID string) ([]article.Revision, error)
+}
+
+// RevisionRestorer stores a revision with its own number, e.g. the inmem.Revision.
+type RevisionRestorer interface {
+	Restore(ctx context.Context, rev article.Revision) error
+}
+
+// Export writes all articles of db as archive to w. It returns the number of
+// exported articles. If revs is not nil, the revisions of every article are written
+// after the article.
+func Export(ctx context.Context, w io.Writer, db Lister, revs RevisionLister) (int, error) {
+	items, err := db.List(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	aw, err := NewWriter(w)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, ar := range items {
+		if err := ctx.Err(); err != nil {
+			return i, err
+		}
+		err = aw.Write(ar)
+		if err != nil {
+			return i, err
+		}
+		if revs == nil {
+			continue
+		}
+
+		kept, err := revs.List(ctx, ar.ID)
+		if err != nil {
+			return i, fmt.Errorf("could not list revisions of %s, %w", ar.ID, err)
+		}
+		for _, rev := range kept {
+			err = aw.WriteRevision(rev)
+			if err != nil {
+				return i, err
+			}
+		}
+	}
+
+	return len(items), aw.Close()
+}
+
+// Restore stores all articles of the archive in r with their original IDs. If revs
+// is not nil, the revisions are restored with their original numbers as well. It
+// stops at the first article, that can not be restored, and returns the number of
+// restored articles.
+func Restore(ctx context.Context, r io.Reader, db Restorer, revs RevisionRestorer) (int, error) {
+	ar, err := NewReader(r)
+	if err != nil {
+		return 0, err
+	}
+	defer ar.Close()
+
+	var n int
+	for {
+		if err := ctx.Err(); err != nil {
+			return n, err
+		}
+
+		item, err := ar.Next()
+		if errors.Is(err, io.EOF) {
+			return n, nil
+		}
+		if err != nil {
+			return n, err
+		}
+
+		err = db.Restore(ctx, item)
+		if err != nil {
+			return n, fmt.Errorf("could not restore article %s, %w", item.ID, err)
+		}
+		n++
+
+		if revs == nil {
+			continue
+		}
+		for _, rev := range ar.Revisions() {
+			err = revs.Restore(ctx, rev)
+			if err != nil {
+				return n, fmt.Errorf("could not restore revision %d of %s, %w", rev.Number, item.ID, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package archive
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/Br0ce/articleDB/pkg/article"
+	"github.com/Br0ce/articleDB/pkg/encoding"
+)
+
+// revisionLine is a line of an archive holding a revision. The key distinguishes it
+// from the article lines.
+type revisionLine struct {
+	Revision *revisionDTO `json:"revision"`
+}
+
+type changeDTO struct {
+	Field string `json:"field"`
+	Old   string `json:"old,omitempty"`
+	New   string `json:"new,omitempty"`
+}
+
+type revisionDTO struct {
+	ArticleID string           `json:"article_id"`
+	Number    int              `json:"number"`
+	Created   time.Time        `json:"created"`
+	Changes   []changeDTO      `json:"changes,omitempty"`
+	Article   encoding.Article `json:"article"`
+}
+
+func fromRevision(rev article.Revision) *revisionDTO {
+	dto := &revisionDTO{
+		ArticleID: rev.ArticleID,
+		Number:    rev.Number,
+		Created:   rev.Created,
+		Article:   encoding.FromArticle(rev.Article),
+	}
+	for _, c := range rev.Diff.Changes {
+		dto.Changes = append(dto.Changes, changeDTO{Field: string(c.Field), Old: c.Old, New: c.New})
+	}
+	return dto
+}
+
+// isRevision reports whether the line holds a revision.
+func isRevision(data []byte) bool {
+	var line struct {
+		Revision json.RawMessage `json:"revision"`
+	}
+	return json.Unmarshal(data, &line) == nil && line.Revision != nil
+}
+
+// toRevision decodes a revision line. The word diff of the body is computed again
+// from the old and the new body.
+func toRevision(data []byte) (article.Revision, error) {
+	var line struct {
+		Revision struct {
+			revisionDTO
+			Article json.RawMessage `json:"article"`
+		} `json:"revision"`
+	}
+	err := json.Unmarshal(data, &line)
+	if err != nil {
+		return article.Revision{}, err
+	}
+	dto := line.Revision
+
+	ar, err := encoding.UnmarshalArticle(dto.Article)
+	if err != nil {
+		return article.Revision{}, err
+	}
+
+	rev := article.Revision{ArticleID: dto.ArticleID, Number: dto.Number, Created: dto.Created, Article: ar}
+	for _, c := range dto.Changes {
+		change := article.Change{Field: article.Field(c.Field), Old: c.Old, New: c.New}
+		if change.Field == article.FieldBody {
+			change.Edits = article.WordDiff(c.Old, c.New)
+		}
+		rev.Diff.Changes = append(rev.Diff.Changes, change)
+	}
+
+	return rev, nil
+}
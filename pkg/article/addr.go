@@ -0,0 +1,67 @@
+package article
+
+import (
+	"net/url"
+	"strings"
+)
+
+// trackingParams holds query parameters that only exist to track the reader and
+// do not change the addressed content. Parameters with one of the trackingPrefixes
+// are treated the same way.
+var (
+	trackingParams   = []string{"fbclid", "gclid"}
+	trackingPrefixes = []string{"utm_"}
+)
+
+// CanonicalAddr returns the canonical form of the given addr. Two addresses
+// pointing to the same article should result in the same canonical addr.
+// Scheme and host are lowercased, default ports are dropped, tracking query
+// parameters are stripped, the remaining parameters are sorted, trailing slashes
+// of the path are removed and the fragment is dropped.
+func CanonicalAddr(addr url.URL) url.URL {
+	addr.Scheme = strings.ToLower(addr.Scheme)
+	addr.Host = strings.ToLower(addr.Host)
+
+	if (addr.Scheme == "http" && addr.Port() == "80") || (addr.Scheme == "https" && addr.Port() == "443") {
+		addr.Host = addr.Hostname()
+	}
+
+	addr.Path = strings.TrimRight(addr.Path, "/")
+	addr.RawPath = ""
+
+	query := addr.Query()
+	for key := range query {
+		if isTrackingParam(key) {
+			query.Del(key)
+		}
+	}
+	addr.RawQuery = query.Encode()
+	addr.ForceQuery = false
+
+	addr.Fragment = ""
+	addr.RawFragment = ""
+
+	return addr
+}
+
+// CanonicalKey returns the string representation of the canonical form of addr.
+// It is empty if addr is empty.
+func CanonicalKey(addr url.URL) string {
+	canonical := CanonicalAddr(addr)
+	return canonical.String()
+}
+
+func isTrackingParam(key string) bool {
+	key = strings.ToLower(key)
+	for _, param := range trackingParams {
+		if key == param {
+			return true
+		}
+	}
+	for _, prefix := range trackingPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
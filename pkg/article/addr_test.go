@@ -0,0 +1,81 @@
+package article
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestCanonicalAddr(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		addr string
+		want string
+	}{
+		{
+			name: "already canonical",
+			addr: "https://news.example.com/politics/story",
+			want: "https://news.example.com/politics/story",
+		},
+		{
+			name: "uppercase host and scheme",
+			addr: "HTTPS://News.Example.COM/politics/Story",
+			want: "https://news.example.com/politics/Story",
+		},
+		{
+			name: "tracking params",
+			addr: "https://news.example.com/story?utm_source=x&UTM_medium=y&fbclid=1&gclid=2",
+			want: "https://news.example.com/story",
+		},
+		{
+			name: "keep and sort other params",
+			addr: "https://news.example.com/story?page=2&utm_campaign=c&id=7",
+			want: "https://news.example.com/story?id=7&page=2",
+		},
+		{
+			name: "trailing slash",
+			addr: "https://news.example.com/story/",
+			want: "https://news.example.com/story",
+		},
+		{
+			name: "root path",
+			addr: "https://news.example.com/",
+			want: "https://news.example.com",
+		},
+		{
+			name: "fragment",
+			addr: "https://news.example.com/story#comments",
+			want: "https://news.example.com/story",
+		},
+		{
+			name: "default port",
+			addr: "https://news.example.com:443/story",
+			want: "https://news.example.com/story",
+		},
+		{
+			name: "non default port",
+			addr: "http://news.example.com:8080/story",
+			want: "http://news.example.com:8080/story",
+		},
+		{
+			name: "empty",
+			addr: "",
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addr, err := url.Parse(tt.addr)
+			if err != nil {
+				t.Fatalf("could not parse addr, %s", err.Error())
+			}
+
+			got := CanonicalAddr(*addr)
+			if got.String() != tt.want {
+				t.Errorf("CanonicalAddr() = %v, want %v", got.String(), tt.want)
+			}
+		})
+	}
+}
@@ -17,19 +17,31 @@ type Article struct {
 	Updated   time.Time
 	Published time.Time
 	Body      string
-	Summary   string
-	Keywords  []string
-	NER       NER
+	// Language is the language of the title and the body, detected on ingestion,
+	// unless it is set.
+	Language Language
+	Summary  string
+	Keywords []string
+	NER      NER
+	// Fingerprint is a locality-sensitive hash of the body, used to detect
+	// near-duplicates.
+	Fingerprint uint64
+	// Embedding is a dense vector representation of the article, used for
+	// semantic search.
+	Embedding []float32
+	// Source describes where the article was ingested from. It is only set for
+	// articles ingested from an archive, e.g. a WARC file.
+	Source Source
 }
 
-// NER holds lists of the different entity types found in an article.
-type NER struct {
-	// Pers is a list of persons found in the article.
-	Pers []string
-	// Locs is a list of locations found in the article.
-	Locs []string
-	// Orgs is a list of Organisations found in the article.
-	Orgs []string
+// Source describes the archive an article was ingested from.
+type Source struct {
+	// Kind is the kind of the archive, e.g. "warc".
+	Kind string
+	// ID identifies the article within the archive, e.g. the WARC record ID.
+	ID string
+	// Captured is the time the archive captured the article.
+	Captured time.Time
 }
 
 // Equal checks if this Article a is equal to the given Article b.
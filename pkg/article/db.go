@@ -5,4 +5,5 @@ import "context"
 type DB interface {
 	Add(ctx context.Context, ar Article) (string, error)
 	Get(ctx context.Context, id string) (Article, error)
+	List(ctx context.Context) ([]Article, error)
 }
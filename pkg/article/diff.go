@@ -0,0 +1,282 @@
+package article
+
+import (
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Field names an original field of an article.
+type Field string
+
+const (
+	FieldTitle     Field = "title"
+	FieldURL       Field = "url"
+	FieldAuthor    Field = "author"
+	FieldPublished Field = "published_at"
+	FieldBody      Field = "body"
+)
+
+// Op is the operation of an Edit.
+type Op int
+
+const (
+	OpEqual Op = iota
+	OpInsert
+	OpDelete
+)
+
+func (o Op) String() string {
+	switch o {
+	case OpEqual:
+		return "equal"
+	case OpInsert:
+		return "insert"
+	case OpDelete:
+		return "delete"
+	default:
+		return "unknown"
+	}
+}
+
+// Edit is a step of a word diff. Applying the equal and deleted texts in order
+// gives the old text, the equal and inserted texts give the new text.
+type Edit struct {
+	Op   Op
+	Text string
+}
+
+// Change is the change of a field. Old and New are the string representations of
+// the field, times are written in RFC3339 and empty, if not set.
+type Change struct {
+	Field Field
+	Old   string
+	New   string
+	// Edits is the word diff of the old and the new value. It is only set for the
+	// body.
+	Edits []Edit
+}
+
+// Diff holds the changes of the original fields between two versions of an article.
+type Diff struct {
+	Changes []Change
+}
+
+// Empty reports whether no field changed.
+func (d Diff) Empty() bool {
+	return len(d.Changes) == 0
+}
+
+// Changed reports whether the field changed.
+func (d Diff) Changed(field Field) bool {
+	_, ok := d.Change(field)
+	return ok
+}
+
+// Change returns the change of the field and whether it changed.
+func (d Diff) Change(field Field) (Change, bool) {
+	for _, c := range d.Changes {
+		if c.Field == field {
+			return c, true
+		}
+	}
+	return Change{}, false
+}
+
+// Diff returns the changes of the original fields from Article a to Article b. Like
+// Equal, only title, addr, author, date of publication and body are considered.
+func (a Article) Diff(b Article) Diff {
+	var d Diff
+	add := func(field Field, old, new string) {
+		if old != new {
+			d.Changes = append(d.Changes, Change{Field: field, Old: old, New: new})
+		}
+	}
+
+	add(FieldTitle, a.Title, b.Title)
+	add(FieldURL, a.Addr.String(), b.Addr.String())
+	add(FieldAuthor, a.Author, b.Author)
+	if !a.Published.Equal(b.Published) {
+		d.Changes = append(d.Changes, Change{
+			Field: FieldPublished,
+			Old:   formatPublished(a.Published),
+			New:   formatPublished(b.Published),
+		})
+	}
+	if a.Body != b.Body {
+		d.Changes = append(d.Changes, Change{
+			Field: FieldBody,
+			Old:   a.Body,
+			New:   b.Body,
+			Edits: WordDiff(a.Body, b.Body),
+		})
+	}
+
+	return d
+}
+
+func formatPublished(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// maxDiffCost bounds the number of inserted and deleted words, a word diff searches
+// for the shortest edit script. Beyond it, the differing middle part of the texts is
+// reported as deleted and inserted as a whole.
+const maxDiffCost = 1024
+
+// WordDiff returns the edits from old to new word by word. Whitespace is kept, so the
+// texts can be restored from the edits.
+func WordDiff(old, new string) []Edit {
+	a, b := words(old), words(new)
+
+	// The common prefix and suffix are cut off, as most edits of a story only touch
+	// a small part of it.
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	var edits []Edit
+	edits = appendEdits(edits, OpEqual, a[:prefix])
+	edits = append(edits, shortestEdits(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	edits = appendEdits(edits, OpEqual, a[len(a)-suffix:])
+
+	return merge(edits)
+}
+
+// shortestEdits returns the shortest edit script from a to b with the algorithm of
+// Myers. If the script costs more than maxDiffCost, a is deleted and b is inserted.
+func shortestEdits(a, b []string) []Edit {
+	n, m := len(a), len(b)
+	if n == 0 || m == 0 {
+		return appendEdits(appendEdits(nil, OpDelete, a), OpInsert, b)
+	}
+
+	maxD := min(n+m, maxDiffCost)
+	offset := maxD + 1
+	v := make([]int, 2*offset+1)
+	// trace[d] holds v[-d..d] before step d, it is used to backtrack the path.
+	var trace [][]int
+
+	for d := 0; d <= maxD; d++ {
+		trace = append(trace, append([]int(nil), v[offset-d:offset+d+1]...))
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+
+			if x >= n && y >= m {
+				return backtrack(a, b, trace)
+			}
+		}
+	}
+
+	return appendEdits(appendEdits(nil, OpDelete, a), OpInsert, b)
+}
+
+// backtrack follows the path found by shortestEdits from the end to the start and
+// returns its edits.
+func backtrack(a, b []string, trace [][]int) []Edit {
+	var edits []Edit
+	x, y := len(a), len(b)
+
+	for d := len(trace) - 1; d > 0; d-- {
+		v := trace[d]
+		at := func(k int) int { return v[k+d] }
+
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && at(k-1) < at(k+1)) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := at(prevK)
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			edits = append(edits, Edit{Op: OpEqual, Text: a[x-1]})
+			x--
+			y--
+		}
+		if x == prevX {
+			edits = append(edits, Edit{Op: OpInsert, Text: b[prevY]})
+		} else {
+			edits = append(edits, Edit{Op: OpDelete, Text: a[prevX]})
+		}
+		x, y = prevX, prevY
+	}
+	for x > 0 && y > 0 {
+		edits = append(edits, Edit{Op: OpEqual, Text: a[x-1]})
+		x--
+		y--
+	}
+
+	for i, j := 0, len(edits)-1; i < j; i, j = i+1, j-1 {
+		edits[i], edits[j] = edits[j], edits[i]
+	}
+	return edits
+}
+
+// appendEdits appends the tokens as a single edit.
+func appendEdits(edits []Edit, op Op, tokens []string) []Edit {
+	if len(tokens) == 0 {
+		return edits
+	}
+	return append(edits, Edit{Op: op, Text: strings.Join(tokens, "")})
+}
+
+// merge joins adjacent edits with the same operation.
+func merge(edits []Edit) []Edit {
+	var merged []Edit
+	for i := 0; i < len(edits); {
+		j := i + 1
+		for j < len(edits) && edits[j].Op == edits[i].Op {
+			j++
+		}
+
+		texts := make([]string, 0, j-i)
+		for _, e := range edits[i:j] {
+			texts = append(texts, e.Text)
+		}
+		merged = append(merged, Edit{Op: edits[i].Op, Text: strings.Join(texts, "")})
+		i = j
+	}
+	return merged
+}
+
+// words splits s into words and runs of whitespace.
+func words(s string) []string {
+	var tokens []string
+	start := 0
+	space := false
+	for i, r := range s {
+		isSpace := unicode.IsSpace(r)
+		if i > start && isSpace != space {
+			tokens = append(tokens, s[start:i])
+			start = i
+		}
+		space = isSpace
+	}
+	if start < len(s) {
+		tokens = append(tokens, s[start:])
+	}
+	return tokens
+}
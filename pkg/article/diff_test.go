@@ -0,0 +1,191 @@
+package article
+
+import (
+	"math/rand"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestArticle_Diff(t *testing.T) {
+	t.Parallel()
+
+	published := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+	old := Article{
+		Title:     "Storm warning",
+		Addr:      url.URL{Scheme: "https", Host: "weather.example.com", Path: "/storm"},
+		Author:    "Jane Doe",
+		Published: published,
+		Body:      "Heavy rain is expected.",
+		Summary:   "Rain.",
+	}
+
+	tests := []struct {
+		name   string
+		modify func(ar *Article)
+		want   Diff
+	}{
+		{
+			name: "equal",
+			modify: func(ar *Article) {
+				ar.Summary = "Other features are ignored."
+				ar.Published = published.In(time.FixedZone("CEST", 2*60*60))
+			},
+			want: Diff{},
+		},
+		{
+			name: "metadata",
+			modify: func(ar *Article) {
+				ar.Title = "Storm warning updated"
+				ar.Addr.Path = "/storm-updated"
+				ar.Published = time.Time{}
+			},
+			want: Diff{Changes: []Change{
+				{Field: FieldTitle, Old: "Storm warning", New: "Storm warning updated"},
+				{Field: FieldURL, Old: "https://weather.example.com/storm", New: "https://weather.example.com/storm-updated"},
+				{Field: FieldPublished, Old: "2023-06-01T12:00:00Z", New: ""},
+			}},
+		},
+		{
+			name: "body",
+			modify: func(ar *Article) {
+				ar.Body = "Heavy snow is expected."
+			},
+			want: Diff{Changes: []Change{
+				{
+					Field: FieldBody,
+					Old:   "Heavy rain is expected.",
+					New:   "Heavy snow is expected.",
+					Edits: []Edit{
+						{Op: OpEqual, Text: "Heavy "},
+						{Op: OpDelete, Text: "rain"},
+						{Op: OpInsert, Text: "snow"},
+						{Op: OpEqual, Text: " is expected."},
+					},
+				},
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ar := old
+			tt.modify(&ar)
+
+			got := old.Diff(ar)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Article.Diff() = %+v, want %+v", got, tt.want)
+			}
+			if got.Empty() != (len(tt.want.Changes) == 0) {
+				t.Errorf("Diff.Empty() = %v", got.Empty())
+			}
+		})
+	}
+}
+
+func TestWordDiff(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		old  string
+		new  string
+		want []Edit
+	}{
+		{
+			name: "equal",
+			old:  "Heavy rain.",
+			new:  "Heavy rain.",
+			want: []Edit{{Op: OpEqual, Text: "Heavy rain."}},
+		},
+		{
+			name: "insert",
+			old:  "Heavy rain.",
+			new:  "Heavy and cold rain.",
+			want: []Edit{
+				{Op: OpEqual, Text: "Heavy "},
+				{Op: OpInsert, Text: "and cold "},
+				{Op: OpEqual, Text: "rain."},
+			},
+		},
+		{
+			name: "delete and insert",
+			old:  "The storm hit the coast on Monday.",
+			new:  "A storm hit the north coast on Tuesday.",
+			want: []Edit{
+				{Op: OpDelete, Text: "The"},
+				{Op: OpInsert, Text: "A"},
+				{Op: OpEqual, Text: " storm hit the "},
+				{Op: OpInsert, Text: "north "},
+				{Op: OpEqual, Text: "coast on "},
+				{Op: OpDelete, Text: "Monday."},
+				{Op: OpInsert, Text: "Tuesday."},
+			},
+		},
+		{
+			name: "from empty",
+			old:  "",
+			new:  "Rain.",
+			want: []Edit{{Op: OpInsert, Text: "Rain."}},
+		},
+		{
+			name: "to empty",
+			old:  "Rain.",
+			new:  "",
+			want: []Edit{{Op: OpDelete, Text: "Rain."}},
+		},
+		{
+			name: "both empty",
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := WordDiff(tt.old, tt.new)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("WordDiff() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWordDiff_restore(t *testing.T) {
+	t.Parallel()
+
+	vocabulary := []string{"rain", "storm", "coast", "the", "a", "wind", "\n", "  "}
+	text := func(r *rand.Rand, n int) string {
+		var b strings.Builder
+		for i := 0; i < n; i++ {
+			b.WriteString(vocabulary[r.Intn(len(vocabulary))])
+			b.WriteString(" ")
+		}
+		return b.String()
+	}
+
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 50; i++ {
+		old, new := text(r, r.Intn(200)), text(r, r.Intn(200))
+		if i%10 == 0 {
+			// Exceeds the maximal cost, so the fallback is used.
+			old, new = text(r, 3000), text(r, 3000)
+		}
+
+		edits := WordDiff(old, new)
+
+		var gotOld, gotNew strings.Builder
+		for _, e := range edits {
+			if e.Op != OpInsert {
+				gotOld.WriteString(e.Text)
+			}
+			if e.Op != OpDelete {
+				gotNew.WriteString(e.Text)
+			}
+		}
+		if gotOld.String() != old || gotNew.String() != new {
+			t.Fatalf("edits do not restore the texts, %q, %q", old, new)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package article
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+var ErrInvalidEntityType = errors.New("invalid entity type")
+
+// TypeSpec configures the recognition of an entity type.
+type TypeSpec struct {
+	Type EntityType
+	// Label is the name of the type presented to a recognizer, e.g. the key of the
+	// type in the json answer of a language model.
+	Label string
+	// Description explains the type to a recognizer.
+	Description string
+}
+
+// BuiltinTypes are the entity types with a predefined spec.
+var BuiltinTypes = []TypeSpec{
+	{Type: Person, Label: "Person", Description: "people, including fictional characters"},
+	{Type: Location, Label: "Location", Description: "countries, cities, regions and landmarks"},
+	{Type: Organisation, Label: "Organization", Description: "companies, agencies, institutions and parties"},
+	{Type: Date, Label: "Date", Description: "absolute or relative dates and periods"},
+	{Type: Event, Label: "Event", Description: "named events, e.g. elections, wars and sports events"},
+	{Type: Product, Label: "Product", Description: "products, vehicles and software"},
+	{Type: Law, Label: "Law", Description: "named laws, treaties and regulations"},
+	{Type: WorkOfArt, Label: "WorkOfArt", Description: "titles of books, songs, films and other works"},
+	{Type: Money, Label: "Money", Description: "monetary amounts including the currency"},
+	{Type: Misc, Label: "Misc", Description: "other named entities"},
+}
+
+// DefaultTypes are the entity types recognized, if no types are configured.
+var DefaultTypes = BuiltinTypes[:3:3]
+
+// ParseTypes parses a comma separated list of entity types, e.g.
+// "person,location,event". A builtin type is given by its name, a custom type by
+// its name and a description, e.g. "weapon=weapons and weapon systems". The name
+// of a custom type is used as its label. An empty list returns the DefaultTypes.
+func ParseTypes(s string) ([]TypeSpec, error) {
+	if strings.TrimSpace(s) == "" {
+		return DefaultTypes, nil
+	}
+
+	var specs []TypeSpec
+	seen := make(map[EntityType]bool)
+	for _, item := range strings.Split(s, ",") {
+		name, desc, custom := strings.Cut(item, "=")
+		name = strings.TrimSpace(name)
+		if !validTypeName(name) {
+			return nil, fmt.Errorf("type %q, %w", name, ErrInvalidEntityType)
+		}
+
+		spec, ok := builtinType(EntityType(strings.ToLower(name)))
+		if custom {
+			spec = TypeSpec{Type: EntityType(strings.ToLower(name)), Label: name, Description: strings.TrimSpace(desc)}
+		} else if !ok {
+			return nil, fmt.Errorf("unknown type %q without description, %w", name, ErrInvalidEntityType)
+		}
+
+		if seen[spec.Type] {
+			return nil, fmt.Errorf("duplicate type %q, %w", name, ErrInvalidEntityType)
+		}
+		seen[spec.Type] = true
+		specs = append(specs, spec)
+	}
+
+	return specs, nil
+}
+
+func builtinType(t EntityType) (TypeSpec, bool) {
+	for _, spec := range BuiltinTypes {
+		if spec.Type == t {
+			return spec, true
+		}
+	}
+	return TypeSpec{}, false
+}
+
+// validTypeName reports whether name is not empty and only consists of letters,
+// digits and underscores.
+func validTypeName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' {
+			return false
+		}
+	}
+	return true
+}
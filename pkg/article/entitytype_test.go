@@ -0,0 +1,80 @@
+package article
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseTypes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		s       string
+		want    []TypeSpec
+		wantErr error
+	}{
+		{
+			name: "empty",
+			s:    " ",
+			want: DefaultTypes,
+		},
+		{
+			name: "builtin",
+			s:    "person, Event,work_of_art",
+			want: []TypeSpec{
+				{Type: Person, Label: "Person", Description: "people, including fictional characters"},
+				{Type: Event, Label: "Event", Description: "named events, e.g. elections, wars and sports events"},
+				{Type: WorkOfArt, Label: "WorkOfArt", Description: "titles of books, songs, films and other works"},
+			},
+		},
+		{
+			name: "custom",
+			s:    "Weapon=weapons and weapon systems,money",
+			want: []TypeSpec{
+				{Type: "weapon", Label: "Weapon", Description: "weapons and weapon systems"},
+				{Type: Money, Label: "Money", Description: "monetary amounts including the currency"},
+			},
+		},
+		{
+			name: "override builtin",
+			s:    "date=dates without periods",
+			want: []TypeSpec{
+				{Type: Date, Label: "date", Description: "dates without periods"},
+			},
+		},
+		{
+			name:    "unknown without description",
+			s:       "person,weapon",
+			wantErr: ErrInvalidEntityType,
+		},
+		{
+			name:    "invalid name",
+			s:       "person,work of art=works",
+			wantErr: ErrInvalidEntityType,
+		},
+		{
+			name:    "empty item",
+			s:       "person,,location",
+			wantErr: ErrInvalidEntityType,
+		},
+		{
+			name:    "duplicate",
+			s:       "person,Person",
+			wantErr: ErrInvalidEntityType,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTypes(tt.s)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParseTypes() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseTypes() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package article
+
+import (
+	"github.com/Br0ce/articleDB/pkg/ids"
+)
+
+// IDFunc derives the ID of an article from its content, so every submission of the
+// same article gets the same ID.
+type IDFunc func(ar Article) string
+
+// AddrID derives the ID from the canonical addr of ar as UUIDv5. It is empty, if ar
+// has no addr.
+func AddrID(ar Article) string {
+	key := CanonicalKey(ar.Addr)
+	if key == "" {
+		return ""
+	}
+	return ids.NameID(key)
+}
+
+// AddrPublishedID derives the ID from the canonical addr and the date of publication
+// of ar as UUIDv5, e.g. for sources reusing the addr of a live blog every day. Without
+// a date of publication it equals the AddrID. It is empty, if ar has no addr.
+func AddrPublishedID(ar Article) string {
+	key := CanonicalKey(ar.Addr)
+	if key == "" {
+		return ""
+	}
+	if ar.Published.IsZero() {
+		return ids.NameID(key)
+	}
+	return ids.NameID(key + " " + formatPublished(ar.Published))
+}
@@ -0,0 +1,80 @@
+package article
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/Br0ce/articleDB/pkg/ids"
+)
+
+func TestContentIDs(t *testing.T) {
+	t.Parallel()
+
+	addr := url.URL{Scheme: "https", Host: "news.example.com", Path: "/story"}
+	tracked := url.URL{Scheme: "https", Host: "News.Example.com", Path: "/story/", RawQuery: "utm_source=feed"}
+	published := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		fn        IDFunc
+		a         Article
+		b         Article
+		wantEqual bool
+	}{
+		{
+			name:      "addr of same canonical addr",
+			fn:        AddrID,
+			a:         Article{Addr: addr, Title: "first"},
+			b:         Article{Addr: tracked, Title: "second", Published: published},
+			wantEqual: true,
+		},
+		{
+			name:      "addr of other addr",
+			fn:        AddrID,
+			a:         Article{Addr: addr},
+			b:         Article{Addr: url.URL{Scheme: "https", Host: "news.example.com", Path: "/other"}},
+			wantEqual: false,
+		},
+		{
+			name:      "addr and published of same article",
+			fn:        AddrPublishedID,
+			a:         Article{Addr: addr, Published: published},
+			b:         Article{Addr: tracked, Published: published.In(time.FixedZone("CEST", 2*60*60))},
+			wantEqual: true,
+		},
+		{
+			name:      "addr and published of other date",
+			fn:        AddrPublishedID,
+			a:         Article{Addr: addr, Published: published},
+			b:         Article{Addr: addr, Published: published.AddDate(0, 0, 1)},
+			wantEqual: false,
+		},
+		{
+			name:      "addr and published without date",
+			fn:        AddrPublishedID,
+			a:         Article{Addr: addr},
+			b:         Article{Addr: addr, Published: published},
+			wantEqual: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := tt.fn(tt.a), tt.fn(tt.b)
+			if !ids.ValidID(a) || !ids.ValidID(b) {
+				t.Fatalf("invalid IDs %v and %v", a, b)
+			}
+			if (a == b) != tt.wantEqual {
+				t.Errorf("IDs %v and %v, want equal %v", a, b, tt.wantEqual)
+			}
+		})
+	}
+
+	if got := AddrID(Article{}); got != "" {
+		t.Errorf("AddrID() without addr = %v, want empty", got)
+	}
+	if got, want := AddrPublishedID(Article{Addr: addr}), AddrID(Article{Addr: addr}); got != want {
+		t.Errorf("AddrPublishedID() without date = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,50 @@
+package article
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrInvalidLanguage = errors.New("invalid language")
+
+// Language is the ISO 639-1 code of the language of an article, e.g. "en". The
+// empty language is unknown.
+type Language string
+
+const (
+	LanguageUnknown Language = ""
+	English         Language = "en"
+	German          Language = "de"
+)
+
+// Languages are the languages, that are detected and analyzed.
+var Languages = []Language{English, German}
+
+// Name returns the English name of the language, e.g. to instruct a language model.
+// An unsupported language returns its code.
+func (l Language) Name() string {
+	switch l {
+	case English:
+		return "English"
+	case German:
+		return "German"
+	default:
+		return string(l)
+	}
+}
+
+// ParseLanguage parses the code of a supported language ignoring case. The empty
+// string returns LanguageUnknown.
+func ParseLanguage(s string) (Language, error) {
+	l := Language(strings.ToLower(strings.TrimSpace(s)))
+	if l == LanguageUnknown {
+		return l, nil
+	}
+	for _, supported := range Languages {
+		if l == supported {
+			return l, nil
+		}
+	}
+	return LanguageUnknown, fmt.Errorf("language %q, %w", s, ErrInvalidLanguage)
+}
@@ -0,0 +1,50 @@
+package article
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseLanguage(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		s       string
+		want    Language
+		wantErr error
+	}{
+		{
+			name: "empty",
+			s:    "",
+			want: LanguageUnknown,
+		},
+		{
+			name: "english",
+			s:    "en",
+			want: English,
+		},
+		{
+			name: "german ignoring case",
+			s:    " DE ",
+			want: German,
+		},
+		{
+			name:    "unsupported",
+			s:       "fr",
+			wantErr: ErrInvalidLanguage,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseLanguage(tt.s)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParseLanguage() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseLanguage() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
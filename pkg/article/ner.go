@@ -0,0 +1,195 @@
+package article
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// EntityType is the type of a named entity.
+type EntityType string
+
+const (
+	Person       EntityType = "person"
+	Location     EntityType = "location"
+	Organisation EntityType = "organisation"
+	Date         EntityType = "date"
+	Event        EntityType = "event"
+	Product      EntityType = "product"
+	Law          EntityType = "law"
+	WorkOfArt    EntityType = "work_of_art"
+	Money        EntityType = "money"
+	Misc         EntityType = "misc"
+)
+
+// Mention is an occurrence of an entity in the body of an article.
+type Mention struct {
+	// Start and End are the byte offsets of the mention in the body. End is
+	// exclusive, so body[Start:End] is the mentioned text.
+	Start int
+	End   int
+}
+
+// Entity is a named entity found in an article.
+type Entity struct {
+	// Name is the canonical name of the entity.
+	Name string
+	Type EntityType
+	// Mentions are the occurrences of the entity in the body ordered by offset.
+	Mentions []Mention
+	// Count is the number of mentions. An entity, that has been recognized but is
+	// not found literally in the body, has a count of 1 and no mentions.
+	Count int
+	// Confidence is the confidence of the recognizer between 0 and 1. It is 0, if
+	// the recognizer does not provide a confidence.
+	Confidence float64
+}
+
+// NER holds the named entities found in an article.
+type NER struct {
+	Entities []Entity
+}
+
+// NewNER returns the NER of the given entities found in body. Entities with the
+// same type and name are merged. The mentions of entities without mentions are
+// located in body.
+func NewNER(body string, entities ...Entity) NER {
+	var ner NER
+	type key struct {
+		name string
+		typ  EntityType
+	}
+	index := make(map[key]int)
+
+	for _, e := range entities {
+		e.Name = strings.TrimSpace(e.Name)
+		if e.Name == "" {
+			continue
+		}
+
+		k := key{name: e.Name, typ: e.Type}
+		if i, ok := index[k]; ok {
+			ner.Entities[i].Confidence = max(ner.Entities[i].Confidence, e.Confidence)
+			continue
+		}
+
+		if len(e.Mentions) == 0 {
+			e.Mentions = Locate(body, e.Name)
+		}
+		e.Count = max(e.Count, len(e.Mentions), 1)
+
+		index[k] = len(ner.Entities)
+		ner.Entities = append(ner.Entities, e)
+	}
+
+	return ner
+}
+
+// EntitiesOf returns an entity of the given type for every name.
+func EntitiesOf(t EntityType, names ...string) []Entity {
+	entities := make([]Entity, 0, len(names))
+	for _, name := range names {
+		entities = append(entities, Entity{Name: name, Type: t})
+	}
+	return entities
+}
+
+// Locate returns the mentions of name in body. Only whole words are matched, e.g.
+// "EU" is not found in "EUROPE".
+func Locate(body, name string) []Mention {
+	if name == "" {
+		return nil
+	}
+
+	var mentions []Mention
+	for offset := 0; offset < len(body); {
+		i := strings.Index(body[offset:], name)
+		if i < 0 {
+			break
+		}
+		start := offset + i
+		end := start + len(name)
+
+		if boundary(body, start, end) {
+			mentions = append(mentions, Mention{Start: start, End: end})
+			offset = end
+			continue
+		}
+		_, size := utf8.DecodeRuneInString(body[start:])
+		offset = start + size
+	}
+
+	return mentions
+}
+
+// boundary reports whether body[start:end] is not preceded or followed by a letter
+// or digit.
+func boundary(body string, start, end int) bool {
+	if start > 0 {
+		r, _ := utf8.DecodeLastRuneInString(body[:start])
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return false
+		}
+	}
+	if end < len(body) {
+		r, _ := utf8.DecodeRuneInString(body[end:])
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// Pers returns the names of the persons.
+func (n NER) Pers() []string {
+	return n.Names(Person)
+}
+
+// Locs returns the names of the locations.
+func (n NER) Locs() []string {
+	return n.Names(Location)
+}
+
+// Orgs returns the names of the organisations.
+func (n NER) Orgs() []string {
+	return n.Names(Organisation)
+}
+
+// Names returns the names of the entities of type t.
+func (n NER) Names(t EntityType) []string {
+	var names []string
+	for _, e := range n.Entities {
+		if e.Type == t {
+			names = append(names, e.Name)
+		}
+	}
+	return names
+}
+
+// Salient returns the entities ordered by salience. Entities mentioned more often
+// come first, on a tie the entity with the higher confidence and then the earlier
+// first mention wins.
+func (n NER) Salient() []Entity {
+	entities := append([]Entity(nil), n.Entities...)
+	sort.SliceStable(entities, func(i, j int) bool {
+		a, b := entities[i], entities[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		if a.Confidence != b.Confidence {
+			return a.Confidence > b.Confidence
+		}
+		return firstMention(a) < firstMention(b)
+	})
+	return entities
+}
+
+// firstMention returns the offset of the first mention of e. Entities without
+// mentions come last.
+func firstMention(e Entity) int {
+	if len(e.Mentions) == 0 {
+		return int(^uint(0) >> 1)
+	}
+	return e.Mentions[0].Start
+}
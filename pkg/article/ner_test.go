@@ -0,0 +1,167 @@
+package article
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLocate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		body string
+		find string
+		want []Mention
+	}{
+		{
+			name: "whole words",
+			body: "The EU and the EU council.",
+			find: "EU",
+			want: []Mention{{Start: 4, End: 6}, {Start: 15, End: 17}},
+		},
+		{
+			name: "not inside words",
+			body: "EUROPE and NEU",
+			find: "EU",
+			want: nil,
+		},
+		{
+			name: "multi byte runes",
+			body: "Élisabeth Borne traf Élisabeth.",
+			find: "Élisabeth",
+			want: []Mention{{Start: 0, End: 10}, {Start: 22, End: 32}},
+		},
+		{
+			name: "punctuation is a boundary",
+			body: "(Berlin), Berlin-Mitte",
+			find: "Berlin",
+			want: []Mention{{Start: 1, End: 7}, {Start: 10, End: 16}},
+		},
+		{
+			name: "empty name",
+			body: "Berlin",
+			find: "",
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Locate(tt.body, tt.find)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Locate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewNER(t *testing.T) {
+	t.Parallel()
+
+	body := "Olaf Scholz met the EU. Scholz and the EU agreed."
+
+	tests := []struct {
+		name     string
+		entities []Entity
+		want     NER
+	}{
+		{
+			name: "locate mentions",
+			entities: []Entity{
+				{Name: "EU", Type: Organisation, Confidence: 0.8},
+				{Name: "Olaf Scholz", Type: Person},
+			},
+			want: NER{Entities: []Entity{
+				{Name: "EU", Type: Organisation, Mentions: []Mention{{Start: 20, End: 22}, {Start: 39, End: 41}}, Count: 2, Confidence: 0.8},
+				{Name: "Olaf Scholz", Type: Person, Mentions: []Mention{{Start: 0, End: 11}}, Count: 1},
+			}},
+		},
+		{
+			name: "merge same name and type",
+			entities: []Entity{
+				{Name: " EU ", Type: Organisation, Confidence: 0.5},
+				{Name: "EU", Type: Organisation, Confidence: 0.9},
+				{Name: "EU", Type: Location},
+			},
+			want: NER{Entities: []Entity{
+				{Name: "EU", Type: Organisation, Mentions: []Mention{{Start: 20, End: 22}, {Start: 39, End: 41}}, Count: 2, Confidence: 0.9},
+				{Name: "EU", Type: Location, Mentions: []Mention{{Start: 20, End: 22}, {Start: 39, End: 41}}, Count: 2},
+			}},
+		},
+		{
+			name: "keep given mentions and count",
+			entities: []Entity{
+				{Name: "Scholz", Type: Person, Mentions: []Mention{{Start: 24, End: 30}}, Count: 3},
+			},
+			want: NER{Entities: []Entity{
+				{Name: "Scholz", Type: Person, Mentions: []Mention{{Start: 24, End: 30}}, Count: 3},
+			}},
+		},
+		{
+			name: "not in body",
+			entities: []Entity{
+				{Name: "NATO", Type: Organisation},
+				{Name: "  ", Type: Organisation},
+			},
+			want: NER{Entities: []Entity{
+				{Name: "NATO", Type: Organisation, Count: 1},
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewNER(body, tt.entities...)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewNER() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNER_Salient(t *testing.T) {
+	t.Parallel()
+
+	ner := NER{Entities: []Entity{
+		{Name: "no mention", Type: Person, Count: 1},
+		{Name: "late", Type: Person, Mentions: []Mention{{Start: 30, End: 34}}, Count: 1},
+		{Name: "early", Type: Location, Mentions: []Mention{{Start: 0, End: 5}}, Count: 1},
+		{Name: "confident", Type: Person, Count: 1, Confidence: 0.9},
+		{Name: "frequent", Type: Organisation, Mentions: []Mention{{Start: 10, End: 18}, {Start: 40, End: 48}}, Count: 2},
+	}}
+
+	var got []string
+	for _, e := range ner.Salient() {
+		got = append(got, e.Name)
+	}
+
+	want := []string{"frequent", "confident", "early", "late", "no mention"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("NER.Salient() = %v, want %v", got, want)
+	}
+	if ner.Entities[0].Name != "no mention" {
+		t.Errorf("NER.Salient() changed the order of the entities")
+	}
+}
+
+func TestNER_names(t *testing.T) {
+	t.Parallel()
+
+	ner := NER{Entities: []Entity{
+		{Name: "Olaf Scholz", Type: Person},
+		{Name: "Berlin", Type: Location},
+		{Name: "EU", Type: Organisation},
+		{Name: "Jane Doe", Type: Person},
+	}}
+
+	if got, want := ner.Pers(), []string{"Olaf Scholz", "Jane Doe"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("NER.Pers() = %v, want %v", got, want)
+	}
+	if got, want := ner.Locs(), []string{"Berlin"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("NER.Locs() = %v, want %v", got, want)
+	}
+	if got, want := ner.Orgs(), []string{"EU"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("NER.Orgs() = %v, want %v", got, want)
+	}
+}
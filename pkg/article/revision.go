@@ -0,0 +1,33 @@
+package article
+
+import (
+	"context"
+	"time"
+)
+
+// Revision is a version of an article. Every change of an original field at the
+// source results in a new revision.
+type Revision struct {
+	ArticleID string
+	// Number counts the revisions of an article starting with 1. Numbers are not
+	// reused, even if old revisions are dropped.
+	Number int
+	// Created is the time the revision was recorded.
+	Created time.Time
+	// Article is the article as of the revision without its embedding.
+	Article Article
+	// Diff holds the changes to the previous revision. It is empty for the first
+	// revision.
+	Diff Diff
+}
+
+// RevisionStore stores the revisions of articles.
+type RevisionStore interface {
+	// Add stores rev as the next revision of its article and returns it with its
+	// number.
+	Add(ctx context.Context, rev Revision) (Revision, error)
+	// List returns the kept revisions of the article ordered by number.
+	List(ctx context.Context, articleID string) ([]Revision, error)
+	// Get returns the revision of the article with the given number.
+	Get(ctx context.Context, articleID string, number int) (Revision, error)
+}
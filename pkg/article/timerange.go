@@ -0,0 +1,102 @@
+package article
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var ErrInvalidRange = errors.New("invalid range")
+
+// TimeField names a time of an article, that is indexed for range queries.
+type TimeField string
+
+const (
+	TimePublished TimeField = "published_at"
+	TimeCreated   TimeField = "created_at"
+)
+
+// Time returns the time of ar named by f.
+func (f TimeField) Time(ar Article) time.Time {
+	if f == TimeCreated {
+		return ar.Created
+	}
+	return ar.Published
+}
+
+// Order is the order of the articles of a range query.
+type Order int
+
+const (
+	Ascending Order = iota
+	Descending
+)
+
+// RangeQuery selects the articles with a time in [From, To). A zero From or To
+// leaves the range open on that side. Articles without the time are treated as the
+// oldest ones, so they are only selected by a range without From.
+type RangeQuery struct {
+	// Field is the time the range is applied on. It defaults to TimePublished.
+	Field TimeField
+	From  time.Time
+	To    time.Time
+	Order Order
+	// Limit is the maximal number of selected articles. It is unlimited, if 0.
+	Limit int
+	// Text filters the articles containing the text in the title or the body,
+	// ignoring case. It is a post-filter, that checks every article in the range
+	// for the substring without the text index, so it is best combined with a
+	// narrow range or a limit. Use a search for terms in large ranges.
+	Text string
+	// Entities filters the articles mentioning all the named entities, ignoring
+	// case.
+	Entities []string
+}
+
+// Validate returns ErrInvalidRange, if the query is invalid.
+func (q RangeQuery) Validate() error {
+	switch {
+	case q.Field != "" && q.Field != TimePublished && q.Field != TimeCreated:
+		return fmt.Errorf("field %q, %w", q.Field, ErrInvalidRange)
+	case !q.From.IsZero() && !q.To.IsZero() && !q.From.Before(q.To):
+		return fmt.Errorf("from is not before to, %w", ErrInvalidRange)
+	case q.Order != Ascending && q.Order != Descending:
+		return fmt.Errorf("order %d, %w", q.Order, ErrInvalidRange)
+	case q.Limit < 0:
+		return fmt.Errorf("negative limit, %w", ErrInvalidRange)
+	}
+	return nil
+}
+
+// Match reports whether ar passes the text and the entity filters of the query.
+// The range itself is not checked.
+func (q RangeQuery) Match(ar Article) bool {
+	if q.Text != "" {
+		text := strings.ToLower(q.Text)
+		if !strings.Contains(strings.ToLower(ar.Title), text) && !strings.Contains(strings.ToLower(ar.Body), text) {
+			return false
+		}
+	}
+
+	for _, name := range q.Entities {
+		found := false
+		for _, e := range ar.NER.Entities {
+			if strings.EqualFold(e.Name, name) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Ranger selects articles by a time range, e.g. the inmem.Article.
+type Ranger interface {
+	Range(ctx context.Context, q RangeQuery) ([]Article, error)
+}
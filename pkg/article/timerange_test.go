@@ -0,0 +1,117 @@
+package article
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRangeQuery_Validate(t *testing.T) {
+	t.Parallel()
+
+	day := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		q       RangeQuery
+		wantErr error
+	}{
+		{
+			name: "open range",
+			q:    RangeQuery{},
+		},
+		{
+			name: "closed range",
+			q:    RangeQuery{Field: TimeCreated, From: day, To: day.AddDate(0, 0, 1), Order: Descending, Limit: 10},
+		},
+		{
+			name:    "unknown field",
+			q:       RangeQuery{Field: "updated_at"},
+			wantErr: ErrInvalidRange,
+		},
+		{
+			name:    "empty range",
+			q:       RangeQuery{From: day, To: day},
+			wantErr: ErrInvalidRange,
+		},
+		{
+			name:    "unknown order",
+			q:       RangeQuery{Order: 2},
+			wantErr: ErrInvalidRange,
+		},
+		{
+			name:    "negative limit",
+			q:       RangeQuery{Limit: -1},
+			wantErr: ErrInvalidRange,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.q.Validate()
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("RangeQuery.Validate() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRangeQuery_Match(t *testing.T) {
+	t.Parallel()
+
+	ar := Article{
+		Title: "Storm warning",
+		Body:  "Heavy rain in Berlin.",
+		NER:   NER{Entities: EntitiesOf(Location, "Berlin")},
+	}
+
+	tests := []struct {
+		name string
+		q    RangeQuery
+		want bool
+	}{
+		{
+			name: "no filter",
+			q:    RangeQuery{},
+			want: true,
+		},
+		{
+			name: "text in title",
+			q:    RangeQuery{Text: "STORM"},
+			want: true,
+		},
+		{
+			name: "text in body",
+			q:    RangeQuery{Text: "heavy rain"},
+			want: true,
+		},
+		{
+			name: "text missing",
+			q:    RangeQuery{Text: "snow"},
+			want: false,
+		},
+		{
+			name: "entity",
+			q:    RangeQuery{Entities: []string{"berlin"}},
+			want: true,
+		},
+		{
+			name: "all entities required",
+			q:    RangeQuery{Entities: []string{"Berlin", "Hamburg"}},
+			want: false,
+		},
+		{
+			name: "text and entity",
+			q:    RangeQuery{Text: "rain", Entities: []string{"Berlin"}},
+			want: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.q.Match(ar); got != tt.want {
+				t.Errorf("RangeQuery.Match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package bulk
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+
+	"github.com/Br0ce/articleDB/pkg/article"
+	"github.com/Br0ce/articleDB/pkg/db"
+)
+
+const DefaultParallelism = 4
+
+// Adder adds an article, e.g. the adder.Adder, which enriches the article before
+// storing it. With adder.WithoutEnrichment, it only stores the normalized and
+// validated article with its keywords.
+type Adder interface {
+	Add(ctx context.Context, ar article.Article) (string, error)
+}
+
+// Result is the outcome of importing a row.
+type Result struct {
+	Line int
+	ID   string
+	// Skipped is set, if the article is already stored. The ID is the ID of the
+	// stored article, if the adder returns it.
+	Skipped bool
+	Err     error
+}
+
+// Summary counts the outcomes of an import.
+type Summary struct {
+	Read    int
+	Added   int
+	Skipped int
+	Failed  int
+}
+
+// Importer adds the rows of import files.
+type Importer struct {
+	adder       Adder
+	parallelism int
+	log         *slog.Logger
+}
+
+type Option func(i *Importer)
+
+// New returns an Importer, that adds the articles with adder. Without options
+// DefaultParallelism articles are added concurrently.
+func New(adder Adder, log *slog.Logger, opts ...Option) *Importer {
+	i := &Importer{
+		adder:       adder,
+		parallelism: DefaultParallelism,
+		log:         log,
+	}
+
+	for _, opt := range opts {
+		opt(i)
+	}
+
+	return i
+}
+
+// WithParallelism sets the number of articles added concurrently.
+func WithParallelism(n int) Option {
+	return func(i *Importer) {
+		if n > 0 {
+			i.parallelism = n
+		}
+	}
+}
+
+// Import adds all valid rows of r. The result of every row is passed to report,
+// invalid rows are reported without being added. Rows, that are rejected by the
+// adder with db.ErrAlreadyExists, are skipped, so an import can be repeated. The
+// results are reported in the order of completion, report is never called
+// concurrently. Import only fails, if r can not be read any further or ctx is done.
+func (i *Importer) Import(ctx context.Context, r *Reader, report func(Result)) (Summary, error) {
+	i.log.Info("start import", "method", "Import", "parallelism", i.parallelism)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	rows := make(chan Row)
+	results := make(chan Result)
+
+	var readErr error
+	go func() {
+		defer close(rows)
+		for {
+			row, err := r.Next()
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			if err != nil {
+				readErr = err
+				cancel()
+				return
+			}
+
+			select {
+			case rows <- row:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for n := 0; n < i.parallelism; n++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for row := range rows {
+				results <- i.add(ctx, row)
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	var sum Summary
+	for res := range results {
+		sum.Read++
+		switch {
+		case res.Skipped:
+			sum.Skipped++
+		case res.Err != nil:
+			sum.Failed++
+		default:
+			sum.Added++
+		}
+		report(res)
+	}
+
+	i.log.Info("import done", "method", "Import",
+		"read", sum.Read, "added", sum.Added, "skipped", sum.Skipped, "failed", sum.Failed)
+
+	// readErr is written before rows is closed, so it is safe to read it after all
+	// workers stopped.
+	if readErr != nil {
+		return sum, readErr
+	}
+	return sum, ctx.Err()
+}
+
+func (i *Importer) add(ctx context.Context, row Row) Result {
+	if row.Err != nil {
+		return Result{Line: row.Line, Err: row.Err}
+	}
+
+	id, err := i.adder.Add(ctx, row.Article)
+	if errors.Is(err, db.ErrAlreadyExists) {
+		return Result{Line: row.Line, ID: id, Skipped: true}
+	}
+	return Result{Line: row.Line, ID: id, Err: err}
+}
@@ -0,0 +1,118 @@
+package bulk
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/Br0ce/articleDB/pkg/article"
+	"github.com/Br0ce/articleDB/pkg/db"
+	"github.com/Br0ce/articleDB/pkg/logger"
+)
+
+// adderFunc is an Adder safe for concurrent use.
+type adderFunc func(ctx context.Context, ar article.Article) (string, error)
+
+func (f adderFunc) Add(ctx context.Context, ar article.Article) (string, error) {
+	return f(ctx, ar)
+}
+
+func TestImporter_Import(t *testing.T) {
+	t.Parallel()
+
+	f, err := os.Open("testdata/articles.csv")
+	if err != nil {
+		t.Fatalf("could not open fixture, %s", err.Error())
+	}
+	defer f.Close()
+
+	r, err := NewReader(f, CSV, nil)
+	if err != nil {
+		t.Fatalf("NewReader() error = %v", err)
+	}
+
+	var mu sync.Mutex
+	var added []string
+	adder := adderFunc(func(ctx context.Context, ar article.Article) (string, error) {
+		if ar.Body == "" {
+			return "", errors.New("body is required")
+		}
+		if ar.Title == "Football final tonight" {
+			return "", db.ErrAlreadyExists
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		added = append(added, ar.Title)
+		return "1234", nil
+	})
+
+	var failed, skipped []int
+	got, err := New(adder, logger.NewTest(false), WithParallelism(3)).Import(context.TODO(), r, func(res Result) {
+		if res.Err != nil {
+			failed = append(failed, res.Line)
+		}
+		if res.Skipped {
+			skipped = append(skipped, res.Line)
+		}
+	})
+	if err != nil {
+		t.Fatalf("Importer.Import() error = %v", err)
+	}
+
+	// The duplicate is skipped, so a repeated import does not fail.
+	want := Summary{Read: 5, Added: 2, Skipped: 1, Failed: 2}
+	if got != want {
+		t.Errorf("Importer.Import() = %+v, want %+v", got, want)
+	}
+
+	sort.Ints(failed)
+	if len(failed) != 2 || failed[0] != 5 || failed[1] != 6 {
+		t.Errorf("failed lines = %v, want [5 6]", failed)
+	}
+	if len(skipped) != 1 || skipped[0] != 7 {
+		t.Errorf("skipped lines = %v, want [7]", skipped)
+	}
+	sort.Strings(added)
+	if len(added) != 2 || added[0] != "Energy prices keep rising" || added[1] != "Storm warning for the weekend" {
+		t.Errorf("added = %v", added)
+	}
+}
+
+// errReader fails after the first row.
+type errReader struct {
+	data string
+	done bool
+}
+
+func (e *errReader) Read(p []byte) (int, error) {
+	if e.done {
+		return 0, errors.New("connection reset")
+	}
+	e.done = true
+	return copy(p, e.data), nil
+}
+
+func TestImporter_Import_readError(t *testing.T) {
+	t.Parallel()
+
+	line := `{"title": "Energy", "url": "https://news.example.com/energy", "body": "Prices rose."}` + "\n"
+	r, err := NewReader(&errReader{data: line}, NDJSON, nil)
+	if err != nil {
+		t.Fatalf("NewReader() error = %v", err)
+	}
+
+	adder := adderFunc(func(ctx context.Context, ar article.Article) (string, error) {
+		return "1234", nil
+	})
+
+	got, err := New(adder, logger.NewTest(false)).Import(context.TODO(), r, func(Result) {})
+	if err == nil {
+		t.Fatal("Importer.Import() without error")
+	}
+	if got.Added > 1 {
+		t.Errorf("Importer.Import() = %+v", got)
+	}
+}
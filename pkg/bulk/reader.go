@@ -0,0 +1,299 @@
+// Package bulk imports articles from NDJSON and CSV files. Every line of a file is
+// validated on its own, so a bad line is reported without aborting the import.
+package bulk
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Br0ce/articleDB/pkg/article"
+)
+
+var (
+	ErrUnknownFormat = errors.New("unknown format, must be ndjson or csv")
+	ErrUnknownField  = errors.New("unknown field")
+	ErrMissingField  = errors.New("missing field")
+	ErrInvalidField  = errors.New("invalid field")
+)
+
+// Format is the format of an import file.
+type Format string
+
+const (
+	NDJSON Format = "ndjson"
+	CSV    Format = "csv"
+)
+
+// ParseFormat returns the format with the given name.
+func ParseFormat(name string) (Format, error) {
+	switch f := Format(strings.ToLower(strings.TrimSpace(name))); f {
+	case NDJSON, CSV:
+		return f, nil
+	case "jsonl":
+		return NDJSON, nil
+	default:
+		return "", fmt.Errorf("%q, %w", name, ErrUnknownFormat)
+	}
+}
+
+// The fields of an article, that can be imported.
+const (
+	FieldTitle     = "title"
+	FieldURL       = "url"
+	FieldAuthor    = "author"
+	FieldPublished = "published"
+	FieldBody      = "body"
+)
+
+var fields = []string{FieldTitle, FieldURL, FieldAuthor, FieldPublished, FieldBody}
+
+// Mapping maps the fields of an article to the columns of a CSV file or the keys of
+// an NDJSON object. Fields without mapping are read from the column or key with
+// the name of the field.
+type Mapping map[string]string
+
+// ParseMapping parses a mapping of the form "title=headline,url=link".
+func ParseMapping(s string) (Mapping, error) {
+	m := make(Mapping)
+	if strings.TrimSpace(s) == "" {
+		return m, nil
+	}
+
+	for _, pair := range strings.Split(s, ",") {
+		field, column, ok := strings.Cut(pair, "=")
+		field = strings.TrimSpace(field)
+		column = strings.TrimSpace(column)
+		if !ok || column == "" {
+			return nil, fmt.Errorf("mapping %q must be of the form field=column", pair)
+		}
+		if !known(field) {
+			return nil, fmt.Errorf("%q, %w", field, ErrUnknownField)
+		}
+		m[field] = column
+	}
+
+	return m, nil
+}
+
+func known(field string) bool {
+	for _, f := range fields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// column returns the column or key of the field.
+func (m Mapping) column(field string) string {
+	if c, ok := m[field]; ok {
+		return c
+	}
+	return field
+}
+
+// Row is a line of an import file. Err is set, if the line is not a valid article.
+type Row struct {
+	// Line is the line number of the row in the file starting at 1.
+	Line    int
+	Article article.Article
+	Err     error
+}
+
+// Reader reads the rows of an import file.
+type Reader struct {
+	format  Format
+	mapping Mapping
+
+	// ndjson
+	lines *bufio.Reader
+	line  int
+
+	// csv
+	records *csv.Reader
+	header  map[string]int
+}
+
+// NewReader returns a Reader for the file in the given format.
+func NewReader(r io.Reader, format Format, mapping Mapping) (*Reader, error) {
+	if mapping == nil {
+		mapping = make(Mapping)
+	}
+
+	rd := &Reader{format: format, mapping: mapping}
+	switch format {
+	case NDJSON:
+		rd.lines = bufio.NewReader(r)
+	case CSV:
+		rd.records = csv.NewReader(r)
+		rd.records.FieldsPerRecord = -1
+		rd.records.ReuseRecord = true
+	default:
+		return nil, fmt.Errorf("%q, %w", format, ErrUnknownFormat)
+	}
+
+	return rd, nil
+}
+
+// Next returns the next row. It returns io.EOF, if there are no more rows. Other
+// errors are returned, if the file can not be read any further. Invalid lines are
+// returned as rows with Err set.
+func (r *Reader) Next() (Row, error) {
+	if r.format == CSV {
+		return r.nextCSV()
+	}
+	return r.nextNDJSON()
+}
+
+func (r *Reader) nextNDJSON() (Row, error) {
+	for {
+		data, err := r.lines.ReadBytes('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return Row{}, err
+		}
+		if len(data) == 0 && errors.Is(err, io.EOF) {
+			return Row{}, io.EOF
+		}
+		r.line++
+
+		data = bytes.TrimSpace(data)
+		if len(data) == 0 {
+			continue
+		}
+
+		row := Row{Line: r.line}
+		var obj map[string]json.RawMessage
+		if jerr := json.Unmarshal(data, &obj); jerr != nil {
+			row.Err = fmt.Errorf("invalid json, %w", jerr)
+			return row, nil
+		}
+
+		values := make(map[string]string)
+		for _, f := range fields {
+			raw, ok := obj[r.mapping.column(f)]
+			if !ok || string(raw) == "null" {
+				continue
+			}
+			var v string
+			if jerr := json.Unmarshal(raw, &v); jerr != nil {
+				row.Err = fmt.Errorf("%s must be a string, %w", f, ErrInvalidField)
+				return row, nil
+			}
+			values[f] = v
+		}
+
+		row.Article, row.Err = toArticle(values)
+		return row, nil
+	}
+}
+
+func (r *Reader) nextCSV() (Row, error) {
+	if r.header == nil {
+		err := r.readHeader()
+		if err != nil {
+			return Row{}, err
+		}
+	}
+
+	record, err := r.records.Read()
+	var perr *csv.ParseError
+	if errors.As(err, &perr) {
+		return Row{Line: perr.StartLine, Err: perr.Err}, nil
+	}
+	if err != nil {
+		return Row{}, err
+	}
+
+	line, _ := r.records.FieldPos(0)
+	row := Row{Line: line}
+	values := make(map[string]string)
+	for _, f := range fields {
+		i, ok := r.header[r.mapping.column(f)]
+		if !ok || i >= len(record) {
+			continue
+		}
+		values[f] = record[i]
+	}
+
+	row.Article, row.Err = toArticle(values)
+	return row, nil
+}
+
+// readHeader reads the header line and checks that the mapped columns exist.
+func (r *Reader) readHeader() error {
+	record, err := r.records.Read()
+	if errors.Is(err, io.EOF) {
+		return io.EOF
+	}
+	if err != nil {
+		return fmt.Errorf("could not read header, %w", err)
+	}
+
+	r.header = make(map[string]int, len(record))
+	for i, name := range record {
+		r.header[strings.TrimSpace(name)] = i
+	}
+
+	var missing []string
+	for _, f := range []string{FieldTitle, FieldURL, FieldBody} {
+		if _, ok := r.header[r.mapping.column(f)]; !ok {
+			missing = append(missing, r.mapping.column(f))
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return fmt.Errorf("header has no column %s, %w", strings.Join(missing, ", "), ErrMissingField)
+	}
+
+	return nil
+}
+
+// publishedLayouts are the accepted formats of the publication date.
+var publishedLayouts = []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02"}
+
+// toArticle returns the article the values describe. The article is validated by
+// the adder, so only values, that can not be converted, are rejected here.
+func toArticle(values map[string]string) (article.Article, error) {
+	for f, v := range values {
+		values[f] = strings.TrimSpace(v)
+	}
+
+	u, err := url.Parse(values[FieldURL])
+	if err != nil {
+		return article.Article{}, fmt.Errorf("url %q is no url, %w", values[FieldURL], ErrInvalidField)
+	}
+
+	var published time.Time
+	if v := values[FieldPublished]; v != "" {
+		published, err = parsePublished(v)
+		if err != nil {
+			return article.Article{}, err
+		}
+	}
+
+	return article.Article{
+		Title:     values[FieldTitle],
+		Addr:      *u,
+		Author:    values[FieldAuthor],
+		Published: published,
+		Body:      values[FieldBody],
+	}, nil
+}
+
+func parsePublished(v string) (time.Time, error) {
+	for _, layout := range publishedLayouts {
+		if t, err := time.Parse(layout, v); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("published %q is no date, %w", v, ErrInvalidField)
+}
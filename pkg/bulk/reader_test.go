@@ -0,0 +1,209 @@
+package bulk
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// readAll returns the line numbers, titles and errors of all rows of the fixture.
+func readAll(t *testing.T, file string, format Format, mapping Mapping) ([]int, []string, []error) {
+	f, err := os.Open(file)
+	if err != nil {
+		t.Fatalf("could not open fixture, %s", err.Error())
+	}
+	defer f.Close()
+
+	r, err := NewReader(f, format, mapping)
+	if err != nil {
+		t.Fatalf("NewReader() error = %v", err)
+	}
+
+	var lines []int
+	var titles []string
+	var errs []error
+	for {
+		row, err := r.Next()
+		if errors.Is(err, io.EOF) {
+			return lines, titles, errs
+		}
+		if err != nil {
+			t.Fatalf("Reader.Next() error = %v", err)
+		}
+		lines = append(lines, row.Line)
+		titles = append(titles, row.Article.Title)
+		errs = append(errs, row.Err)
+	}
+}
+
+func TestReader_Next(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		file       string
+		format     Format
+		mapping    Mapping
+		wantLines  []int
+		wantTitles []string
+		wantErrs   []error
+	}{
+		{
+			name:      "ndjson",
+			file:      "testdata/articles.ndjson",
+			format:    NDJSON,
+			mapping:   Mapping{FieldTitle: "headline", FieldURL: "link"},
+			wantLines: []int{1, 3, 4, 5, 6, 7, 8},
+			wantTitles: []string{
+				"Energy prices keep rising",
+				"Storm warning for the weekend",
+				"No body",
+				"Relative link",
+				"", "", "",
+			},
+			// Missing fields and relative urls are rejected by the validation of the
+			// adder.
+			wantErrs: []error{nil, nil, nil, nil, errors.New("invalid json"), ErrInvalidField, ErrInvalidField},
+		},
+		{
+			name:      "csv",
+			file:      "testdata/articles.csv",
+			format:    CSV,
+			wantLines: []int{2, 3, 5, 6, 7},
+			wantTitles: []string{
+				"Energy prices keep rising",
+				"Storm warning for the weekend",
+				"No body",
+				"",
+				"Football final tonight",
+			},
+			wantErrs: []error{nil, nil, nil, csv.ErrBareQuote, nil},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines, titles, errs := readAll(t, tt.file, tt.format, tt.mapping)
+			if !reflect.DeepEqual(lines, tt.wantLines) {
+				t.Errorf("lines = %v, want %v", lines, tt.wantLines)
+			}
+			if !reflect.DeepEqual(titles, tt.wantTitles) {
+				t.Errorf("titles = %v, want %v", titles, tt.wantTitles)
+			}
+			if len(errs) != len(tt.wantErrs) {
+				t.Fatalf("errs = %v, want %v", errs, tt.wantErrs)
+			}
+			for i, err := range errs {
+				want := tt.wantErrs[i]
+				if (err == nil) != (want == nil) {
+					t.Errorf("line %d error = %v, want %v", lines[i], err, want)
+					continue
+				}
+				if err != nil && !errors.Is(err, want) && !strings.Contains(err.Error(), want.Error()) {
+					t.Errorf("line %d error = %v, want %v", lines[i], err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestReader_Next_article(t *testing.T) {
+	t.Parallel()
+
+	data := "title,url,author,published,body\n" +
+		"Energy,https://news.example.com/energy,Jane Doe,2023-06-01T14:00:00+02:00, Prices rose. \n"
+
+	r, err := NewReader(strings.NewReader(data), CSV, nil)
+	if err != nil {
+		t.Fatalf("NewReader() error = %v", err)
+	}
+
+	row, err := r.Next()
+	if err != nil || row.Err != nil {
+		t.Fatalf("Reader.Next() error = %v, row error %v", err, row.Err)
+	}
+
+	got := row.Article
+	if got.Title != "Energy" || got.Addr.String() != "https://news.example.com/energy" || got.Author != "Jane Doe" ||
+		!got.Published.Equal(time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)) || got.Body != "Prices rose." {
+		t.Errorf("Reader.Next() article = %+v", got)
+	}
+}
+
+func TestNewReader_missingColumn(t *testing.T) {
+	t.Parallel()
+
+	r, err := NewReader(strings.NewReader("headline,url,body\n"), CSV, nil)
+	if err != nil {
+		t.Fatalf("NewReader() error = %v", err)
+	}
+
+	_, err = r.Next()
+	if !errors.Is(err, ErrMissingField) {
+		t.Errorf("Reader.Next() error = %v, want %v", err, ErrMissingField)
+	}
+}
+
+func TestParseMapping(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		in      string
+		want    Mapping
+		wantErr bool
+	}{
+		{
+			name: "pass",
+			in:   "title=headline, url = link",
+			want: Mapping{FieldTitle: "headline", FieldURL: "link"},
+		},
+		{
+			name: "empty",
+			in:   "",
+			want: Mapping{},
+		},
+		{
+			name:    "unknown field",
+			in:      "summary=teaser",
+			wantErr: true,
+		},
+		{
+			name:    "no column",
+			in:      "title",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseMapping(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseMapping() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseMapping() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	t.Parallel()
+
+	for in, want := range map[string]Format{"ndjson": NDJSON, "JSONL": NDJSON, "csv": CSV} {
+		got, err := ParseFormat(in)
+		if err != nil || got != want {
+			t.Errorf("ParseFormat(%q) = %v, %v, want %v", in, got, err, want)
+		}
+	}
+
+	if _, err := ParseFormat("xml"); !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("ParseFormat(xml) error = %v, want %v", err, ErrUnknownFormat)
+	}
+}
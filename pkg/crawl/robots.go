@@ -0,0 +1,154 @@
+package crawl
+
+import (
+	"bufio"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Robots holds the rules of a robots.txt file.
+// See RFC 9309 for the format.
+type Robots struct {
+	groups []group
+}
+
+type group struct {
+	agents []string
+	rules  []rule
+	delay  time.Duration
+}
+
+type rule struct {
+	allow   bool
+	pattern string
+	re      *regexp.Regexp
+}
+
+// AllowAll are the rules of a missing robots.txt.
+var AllowAll = &Robots{}
+
+// DisallowAll are the rules used, if the robots.txt of a host is unavailable.
+var DisallowAll = &Robots{groups: []group{{
+	agents: []string{"*"},
+	rules:  []rule{newRule(false, "/")},
+}}}
+
+// ParseRobots parses a robots.txt file. Unknown lines are ignored.
+func ParseRobots(r io.Reader) (*Robots, error) {
+	robots := &Robots{}
+	var cur *group
+	// inAgents is set while reading consecutive user-agent lines, which belong to
+	// the same group.
+	inAgents := false
+
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := sc.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "user-agent":
+			if !inAgents {
+				robots.groups = append(robots.groups, group{})
+				cur = &robots.groups[len(robots.groups)-1]
+			}
+			cur.agents = append(cur.agents, strings.ToLower(value))
+			inAgents = true
+			continue
+		case "allow", "disallow":
+			// An empty disallow allows everything and is the same as no rule.
+			if cur != nil && value != "" {
+				cur.rules = append(cur.rules, newRule(key == "allow", value))
+			}
+		case "crawl-delay":
+			if cur != nil {
+				if secs, err := strconv.ParseFloat(value, 64); err == nil && secs > 0 {
+					cur.delay = time.Duration(secs * float64(time.Second))
+				}
+			}
+		}
+		inAgents = false
+	}
+
+	return robots, sc.Err()
+}
+
+func newRule(allow bool, pattern string) rule {
+	expr := regexp.QuoteMeta(pattern)
+	expr = strings.ReplaceAll(expr, `\*`, ".*")
+	if strings.HasSuffix(expr, `\$`) {
+		expr = strings.TrimSuffix(expr, `\$`) + "$"
+	}
+
+	return rule{
+		allow:   allow,
+		pattern: pattern,
+		re:      regexp.MustCompile("^" + expr),
+	}
+}
+
+// group returns the group matching the agent. The group with the longest matching
+// agent name wins, the wildcard group matches every agent.
+func (r *Robots) group(agent string) *group {
+	agent = strings.ToLower(agent)
+
+	var best *group
+	bestLen := -1
+	for i, g := range r.groups {
+		for _, a := range g.agents {
+			switch {
+			case a == "*" && bestLen < 0:
+				best, bestLen = &r.groups[i], 0
+			case a != "*" && strings.Contains(agent, a) && len(a) > bestLen:
+				best, bestLen = &r.groups[i], len(a)
+			}
+		}
+	}
+
+	return best
+}
+
+// Allowed reports whether the agent may fetch the given path. The most specific
+// matching rule wins, on a tie an allow rule wins.
+func (r *Robots) Allowed(agent, path string) bool {
+	g := r.group(agent)
+	if g == nil {
+		return true
+	}
+	if path == "" {
+		path = "/"
+	}
+
+	allowed := true
+	matchLen := -1
+	for _, ru := range g.rules {
+		if !ru.re.MatchString(path) {
+			continue
+		}
+		if len(ru.pattern) > matchLen || (len(ru.pattern) == matchLen && ru.allow) {
+			allowed, matchLen = ru.allow, len(ru.pattern)
+		}
+	}
+
+	return allowed
+}
+
+// CrawlDelay returns the delay between two requests requested for the agent.
+func (r *Robots) CrawlDelay(agent string) time.Duration {
+	if g := r.group(agent); g != nil {
+		return g.delay
+	}
+	return 0
+}
@@ -0,0 +1,80 @@
+package crawl
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const robotsTxt = `
+# Example robots.txt
+User-agent: *
+Disallow: /private/
+Allow: /private/public
+Disallow: /*.pdf$
+Crawl-delay: 2
+
+User-agent: articleDB
+User-agent: otherbot
+Disallow: /archive
+Allow: /archive/2023/
+Crawl-delay: 0.5
+
+User-agent: blockedbot
+Disallow: /
+`
+
+func TestRobots_Allowed(t *testing.T) {
+	t.Parallel()
+
+	robots, err := ParseRobots(strings.NewReader(robotsTxt))
+	if err != nil {
+		t.Fatalf("ParseRobots() error = %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		agent string
+		path  string
+		want  bool
+	}{
+		{name: "wildcard allowed", agent: "somebot", path: "/news/story", want: true},
+		{name: "wildcard disallowed", agent: "somebot", path: "/private/data", want: false},
+		{name: "more specific allow", agent: "somebot", path: "/private/public/page", want: true},
+		{name: "wildcard pattern", agent: "somebot", path: "/files/report.pdf", want: false},
+		{name: "end anchor", agent: "somebot", path: "/files/report.pdf.html", want: true},
+		{name: "specific group", agent: DefaultUserAgent, path: "/archive/2022/story", want: false},
+		{name: "specific group allow", agent: DefaultUserAgent, path: "/archive/2023/story", want: true},
+		{name: "specific group ignores wildcard", agent: DefaultUserAgent, path: "/private/data", want: true},
+		{name: "second agent of group", agent: "OtherBot/2.0", path: "/archive", want: false},
+		{name: "blocked", agent: "blockedbot", path: "/", want: false},
+		{name: "empty path", agent: "somebot", path: "", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := robots.Allowed(tt.agent, tt.path); got != tt.want {
+				t.Errorf("Robots.Allowed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRobots_CrawlDelay(t *testing.T) {
+	t.Parallel()
+
+	robots, err := ParseRobots(strings.NewReader(robotsTxt))
+	if err != nil {
+		t.Fatalf("ParseRobots() error = %v", err)
+	}
+
+	if got := robots.CrawlDelay("somebot"); got != 2*time.Second {
+		t.Errorf("Robots.CrawlDelay() = %v, want 2s", got)
+	}
+	if got := robots.CrawlDelay(DefaultUserAgent); got != 500*time.Millisecond {
+		t.Errorf("Robots.CrawlDelay() = %v, want 500ms", got)
+	}
+	if got := AllowAll.CrawlDelay("somebot"); got != 0 {
+		t.Errorf("Robots.CrawlDelay() = %v, want 0", got)
+	}
+}
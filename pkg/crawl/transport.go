@@ -0,0 +1,485 @@
+// Package crawl provides an http transport, that fetches pages politely. It respects
+// the robots.txt of a host, limits the number of concurrent requests and the request
+// rate per host, identifies itself by a User-Agent and guards against oversized and
+// slow responses. All fetching of external pages, e.g. feeds and articles, should
+// share one transport, so the limits apply to all of them.
+package crawl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"sync"
+	"syscall"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	DefaultUserAgent   = "articleDB/1.0 (+https://github.com/Br0ce/articleDB)"
+	DefaultMaxSize     = 10 << 20
+	DefaultTimeout     = 30 * time.Second
+	DefaultConcurrency = 2
+	DefaultRobotsTTL   = 24 * time.Hour
+
+	// maxRobotsRedirects is the number of consecutive redirects followed to a
+	// robots.txt (RFC 9309).
+	maxRobotsRedirects = 5
+)
+
+var (
+	ErrDisallowed = errors.New("disallowed by robots.txt")
+	ErrTooLarge   = errors.New("response too large")
+	ErrPrivate    = errors.New("address is not public")
+	ErrTemporary  = errors.New("temporary failure")
+)
+
+// DefaultClient is the client shared by all fetching paths, if no other client is
+// configured.
+var DefaultClient = NewClient(NewTransport())
+
+// Temporary reports whether the request failing with err may succeed, when it is
+// repeated later, e.g. after a timeout or a server error.
+func Temporary(err error) bool {
+	var netErr net.Error
+	return errors.Is(err, ErrTemporary) ||
+		errors.Is(err, context.DeadlineExceeded) ||
+		(errors.As(err, &netErr) && netErr.Timeout())
+}
+
+// TemporaryStatus reports whether a response with the status code may succeed, when
+// the request is repeated later.
+func TemporaryStatus(code int) bool {
+	return code >= 500 || code == http.StatusTooManyRequests || code == http.StatusRequestTimeout
+}
+
+// NewClient returns an http.Client using the given transport.
+func NewClient(t *Transport) *http.Client {
+	return &http.Client{Transport: t}
+}
+
+type robotsEntry struct {
+	robots  *Robots
+	expires time.Time
+}
+
+// host limits the requests to a single host.
+type host struct {
+	slots chan struct{}
+	next  time.Time
+	mu    sync.Mutex
+}
+
+// Transport is a polite http.RoundTripper.
+type Transport struct {
+	base        http.RoundTripper
+	userAgent   string
+	maxSize     int64
+	timeout     time.Duration
+	concurrency int
+	delay       time.Duration
+	robotsTTL   time.Duration
+	private     bool
+
+	robots map[string]robotsEntry
+	hosts  map[string]*host
+	mu     sync.Mutex
+
+	// fetching collapses concurrent fetches of the robots.txt of a host.
+	fetching singleflight.Group
+}
+
+type Option func(t *Transport)
+
+// NewTransport returns a Transport. Without options the defaults are used, there is
+// no delay between requests to the same host besides the crawl delay requested by
+// its robots.txt and only public addresses are connected to.
+func NewTransport(opts ...Option) *Transport {
+	t := &Transport{
+		userAgent:   DefaultUserAgent,
+		maxSize:     DefaultMaxSize,
+		timeout:     DefaultTimeout,
+		concurrency: DefaultConcurrency,
+		robotsTTL:   DefaultRobotsTTL,
+		robots:      make(map[string]robotsEntry),
+		hosts:       make(map[string]*host),
+	}
+
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	if t.base == nil {
+		t.base = http.DefaultTransport
+		if !t.private {
+			t.base = publicTransport()
+		}
+	}
+
+	return t
+}
+
+// WithBase sets the transport performing the actual requests. The base transport
+// is responsible for rejecting private addresses.
+func WithBase(base http.RoundTripper) Option {
+	return func(t *Transport) {
+		t.base = base
+	}
+}
+
+// WithUserAgent sets the User-Agent of all requests. The agent is also used to
+// find the matching rules in a robots.txt.
+func WithUserAgent(agent string) Option {
+	return func(t *Transport) {
+		t.userAgent = agent
+	}
+}
+
+// WithMaxSize sets the maximal size of a response body in bytes. Reading a larger
+// body fails with ErrTooLarge.
+func WithMaxSize(size int64) Option {
+	return func(t *Transport) {
+		t.maxSize = size
+	}
+}
+
+// WithTimeout sets the maximal duration of a request including reading the body.
+// Durations of 0 or less are ignored, so every request keeps a timeout.
+func WithTimeout(d time.Duration) Option {
+	return func(t *Transport) {
+		if d > 0 {
+			t.timeout = d
+		}
+	}
+}
+
+// WithConcurrency sets the maximal number of concurrent requests per host.
+func WithConcurrency(n int) Option {
+	return func(t *Transport) {
+		if n > 0 {
+			t.concurrency = n
+		}
+	}
+}
+
+// WithDelay sets the minimal delay between the start of two requests to the same
+// host. A larger crawl delay of the robots.txt of the host takes precedence.
+func WithDelay(d time.Duration) Option {
+	return func(t *Transport) {
+		t.delay = d
+	}
+}
+
+// WithRobotsTTL sets how long a robots.txt is cached.
+func WithRobotsTTL(d time.Duration) Option {
+	return func(t *Transport) {
+		t.robotsTTL = d
+	}
+}
+
+// WithPrivateNetworks allows requests to loopback, private and link-local
+// addresses, e.g. for an intranet or tests.
+func WithPrivateNetworks() Option {
+	return func(t *Transport) {
+		t.private = true
+	}
+}
+
+// publicTransport returns a copy of http.DefaultTransport, that only connects to
+// public addresses. The address is checked after the name is resolved, so neither
+// a redirect nor a host name resolving to a private address gets through. No proxy
+// is used, because only the address of the proxy could be checked.
+func publicTransport() *http.Transport {
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   controlPublic,
+	}
+
+	tr := http.DefaultTransport.(*http.Transport).Clone()
+	tr.Proxy = nil
+	tr.DialContext = dialer.DialContext
+	return tr
+}
+
+// controlPublic fails with ErrPrivate, if address is not a public address.
+func controlPublic(network, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("%s, %w", address, ErrPrivate)
+	}
+	if !IsPublic(addrPort.Addr()) {
+		return fmt.Errorf("%s, %w", address, ErrPrivate)
+	}
+	return nil
+}
+
+// IsPublic reports whether addr is a public unicast address. Loopback, private,
+// link-local, e.g. cloud metadata services at 169.254.169.254, shared, multicast
+// and unspecified addresses are not public.
+func IsPublic(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsValid() &&
+		addr.IsGlobalUnicast() &&
+		!addr.IsPrivate() &&
+		!sharedPrefix.Contains(addr)
+}
+
+// sharedPrefix is the shared address space of carrier-grade NAT (RFC 6598).
+var sharedPrefix = netip.MustParsePrefix("100.64.0.0/10")
+
+// RoundTrip performs the request, if the robots.txt of the host allows it, and
+// waits for the limits of the host. The request counts against the concurrency of
+// the host until its body is closed.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, cancel := context.WithTimeout(req.Context(), t.timeout)
+
+	robots := t.robotsFor(ctx, req)
+	if !robots.Allowed(t.userAgent, req.URL.EscapedPath()) {
+		cancel()
+		return nil, fmt.Errorf("%s, %w", req.URL.String(), ErrDisallowed)
+	}
+
+	h := t.host(req.URL.Host)
+	err := h.acquire(ctx, max(t.delay, robots.CrawlDelay(t.userAgent)))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	resp, err := t.base.RoundTrip(t.prepare(ctx, req))
+	if err != nil {
+		h.release()
+		cancel()
+		return nil, err
+	}
+
+	if t.maxSize > 0 && resp.ContentLength > t.maxSize {
+		resp.Body.Close()
+		h.release()
+		cancel()
+		return nil, fmt.Errorf("%d bytes, %w", resp.ContentLength, ErrTooLarge)
+	}
+
+	resp.Body = &guardedBody{
+		body:      resp.Body,
+		remaining: t.maxSize,
+		limited:   t.maxSize > 0,
+		done: func() {
+			h.release()
+			cancel()
+		},
+	}
+
+	return resp, nil
+}
+
+// prepare returns a copy of req with the context and the User-Agent set.
+func (t *Transport) prepare(ctx context.Context, req *http.Request) *http.Request {
+	req = req.Clone(ctx)
+	if req.Header.Get("User-Agent") == "" {
+		req.Header.Set("User-Agent", t.userAgent)
+	}
+	return req
+}
+
+func (t *Transport) host(name string) *host {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	h, ok := t.hosts[name]
+	if !ok {
+		h = &host{slots: make(chan struct{}, t.concurrency)}
+		t.hosts[name] = h
+	}
+	return h
+}
+
+// robotsFor returns the cached robots.txt rules of the host of req. Missing rules
+// are fetched. Requests for the robots.txt itself are always allowed.
+func (t *Transport) robotsFor(ctx context.Context, req *http.Request) *Robots {
+	if req.URL.Path == "/robots.txt" {
+		return AllowAll
+	}
+
+	key := req.URL.Scheme + "://" + req.URL.Host
+
+	t.mu.Lock()
+	entry, ok := t.robots[key]
+	t.mu.Unlock()
+	if ok && time.Now().Before(entry.expires) {
+		return entry.robots
+	}
+
+	// The fetch is shared by all requests to the host, so it must not be canceled
+	// with the request starting it.
+	v, _, _ := t.fetching.Do(key, func() (any, error) {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), t.timeout)
+		defer cancel()
+
+		robots, ttl := t.fetchRobots(ctx, key)
+
+		t.mu.Lock()
+		t.robots[key] = robotsEntry{robots: robots, expires: time.Now().Add(ttl)}
+		t.mu.Unlock()
+
+		return robots, nil
+	})
+
+	return v.(*Robots)
+}
+
+// fetchRobots fetches the robots.txt at origin and returns its rules and how long
+// they should be cached. Up to five consecutive redirects are followed. A missing
+// robots.txt, or one behind too many redirects, allows everything. If the robots.txt
+// is unavailable, everything is disallowed and the rules are cached shortly, so the
+// host is asked again soon.
+func (t *Transport) fetchRobots(ctx context.Context, origin string) (*Robots, time.Duration) {
+	retry := time.Minute
+
+	addr, err := url.Parse(origin + "/robots.txt")
+	if err != nil {
+		return DisallowAll, retry
+	}
+
+	for redirects := 0; ; redirects++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, addr.String(), nil)
+		if err != nil {
+			return DisallowAll, retry
+		}
+		req.Header.Set("User-Agent", t.userAgent)
+
+		resp, err := t.base.RoundTrip(req)
+		if errors.Is(err, ErrPrivate) {
+			// The request itself fails for the same reason.
+			return AllowAll, retry
+		}
+		if err != nil {
+			return DisallowAll, retry
+		}
+
+		loc, redirected := redirect(resp)
+		if !redirected {
+			robots, ttl := t.robotsOf(resp, retry)
+			resp.Body.Close()
+			return robots, ttl
+		}
+		resp.Body.Close()
+
+		if redirects == maxRobotsRedirects {
+			return AllowAll, t.robotsTTL
+		}
+		addr, err = addr.Parse(loc)
+		if err != nil || (addr.Scheme != "http" && addr.Scheme != "https") {
+			return DisallowAll, retry
+		}
+	}
+}
+
+// redirect returns the location a response redirects to.
+func redirect(resp *http.Response) (string, bool) {
+	switch resp.StatusCode {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusSeeOther,
+		http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+		loc := resp.Header.Get("Location")
+		return loc, loc != ""
+	default:
+		return "", false
+	}
+}
+
+// robotsOf returns the rules of the final robots.txt response and how long they
+// should be cached.
+func (t *Transport) robotsOf(resp *http.Response, retry time.Duration) (*Robots, time.Duration) {
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		robots, err := ParseRobots(io.LimitReader(resp.Body, 500<<10))
+		if err != nil {
+			return DisallowAll, retry
+		}
+		return robots, t.robotsTTL
+	case resp.StatusCode >= 400 && resp.StatusCode < 500:
+		return AllowAll, t.robotsTTL
+	default:
+		return DisallowAll, retry
+	}
+}
+
+// acquire waits for a free slot of the host and for the delay since the last
+// request to the host.
+func (h *host) acquire(ctx context.Context, delay time.Duration) error {
+	select {
+	case h.slots <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	h.mu.Lock()
+	now := time.Now()
+	start := now
+	if h.next.After(now) {
+		start = h.next
+	}
+	h.next = start.Add(delay)
+	h.mu.Unlock()
+
+	if wait := start.Sub(now); wait > 0 {
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			h.release()
+			return ctx.Err()
+		}
+	}
+
+	return nil
+}
+
+func (h *host) release() {
+	<-h.slots
+}
+
+// guardedBody fails with ErrTooLarge, if more than remaining bytes are read. The
+// done func is called once, when the body is closed.
+type guardedBody struct {
+	body      io.ReadCloser
+	remaining int64
+	limited   bool
+	done      func()
+	once      sync.Once
+}
+
+func (b *guardedBody) Read(p []byte) (int, error) {
+	if b.limited && b.remaining <= 0 {
+		// Check whether the body really continues.
+		var one [1]byte
+		n, err := b.body.Read(one[:])
+		if n > 0 {
+			return 0, ErrTooLarge
+		}
+		return 0, err
+	}
+
+	if b.limited && int64(len(p)) > b.remaining {
+		p = p[:b.remaining]
+	}
+
+	n, err := b.body.Read(p)
+	b.remaining -= int64(n)
+	return n, err
+}
+
+func (b *guardedBody) Close() error {
+	err := b.body.Close()
+	b.once.Do(b.done)
+	return err
+}
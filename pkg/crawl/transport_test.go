@@ -0,0 +1,301 @@
+package crawl
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestTransport_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/robots.txt", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "User-agent: *\nDisallow: /private\n")
+	})
+	mux.HandleFunc("/agent", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, r.Header.Get("User-Agent"))
+	})
+	mux.HandleFunc("/private", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("disallowed page requested")
+	})
+	mux.HandleFunc("/large", func(w http.ResponseWriter, r *http.Request) {
+		// Without content length the size is only detected while reading.
+		w.(http.Flusher).Flush()
+		_, _ = io.WriteString(w, strings.Repeat("x", 2048))
+	})
+	mux.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(time.Second):
+		case <-r.Context().Done():
+		}
+	})
+	svr := httptest.NewServer(mux)
+	defer svr.Close()
+
+	client := NewClient(NewTransport(
+		WithUserAgent("testbot/1.0"),
+		WithMaxSize(1024),
+		WithTimeout(50*time.Millisecond),
+		WithPrivateNetworks(),
+	))
+
+	tests := []struct {
+		name    string
+		path    string
+		want    string
+		wantErr error
+	}{
+		{name: "user agent", path: "/agent", want: "testbot/1.0"},
+		{name: "disallowed", path: "/private", wantErr: ErrDisallowed},
+		{name: "too large", path: "/large", wantErr: ErrTooLarge},
+		{name: "timeout", path: "/slow", wantErr: context.DeadlineExceeded},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := get(client, svr.URL+tt.path)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("get() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && got != tt.want {
+				t.Errorf("get() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func get(client *http.Client, addr string) (string, error) {
+	resp, err := client.Get(addr)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	bb, err := io.ReadAll(resp.Body)
+	return string(bb), err
+}
+
+func TestTransport_zeroTimeout(t *testing.T) {
+	t.Parallel()
+
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "page")
+	}))
+	defer svr.Close()
+
+	tr := NewTransport(WithTimeout(0), WithPrivateNetworks())
+	if tr.timeout != DefaultTimeout {
+		t.Errorf("timeout = %v, want %v", tr.timeout, DefaultTimeout)
+	}
+	got, err := get(NewClient(tr), svr.URL+"/page")
+	if err != nil {
+		t.Fatalf("get() error = %v", err)
+	}
+	if got != "page" {
+		t.Errorf("get() = %v, want page", got)
+	}
+}
+
+func TestTransport_robotsUnavailable(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		status  int
+		wantErr error
+	}{
+		{name: "missing", status: http.StatusNotFound},
+		{name: "server error", status: http.StatusServiceUnavailable, wantErr: ErrDisallowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/robots.txt" {
+					w.WriteHeader(tt.status)
+					return
+				}
+				_, _ = io.WriteString(w, "page")
+			}))
+			defer svr.Close()
+
+			client := NewClient(NewTransport(WithPrivateNetworks()))
+			_, err := get(client, svr.URL+"/page")
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("get() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTransport_hostLimits(t *testing.T) {
+	t.Parallel()
+
+	var cur, peak int32
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		n := atomic.AddInt32(&cur, 1)
+		defer atomic.AddInt32(&cur, -1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+	}))
+	defer svr.Close()
+
+	delay := 10 * time.Millisecond
+	client := NewClient(NewTransport(WithConcurrency(2), WithDelay(delay), WithPrivateNetworks()))
+
+	num := 6
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < num; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := get(client, svr.URL+"/page"); err != nil {
+				t.Errorf("get() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if peak > 2 {
+		t.Errorf("concurrent requests = %v, want at most 2", peak)
+	}
+	if elapsed := time.Since(start); elapsed < time.Duration(num-1)*delay {
+		t.Errorf("elapsed = %v, want at least %v", elapsed, time.Duration(num-1)*delay)
+	}
+}
+
+func TestTransport_private(t *testing.T) {
+	t.Parallel()
+
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("private address requested")
+	}))
+	defer svr.Close()
+
+	client := NewClient(NewTransport())
+	if _, err := get(client, svr.URL+"/page"); !errors.Is(err, ErrPrivate) {
+		t.Errorf("get() error = %v, want %v", err, ErrPrivate)
+	}
+}
+
+func TestIsPublic(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		addr string
+		want bool
+	}{
+		{addr: "93.184.216.34", want: true},
+		{addr: "2606:2800:220:1:248:1893:25c8:1946", want: true},
+		{addr: "127.0.0.1"},
+		{addr: "::1"},
+		{addr: "10.1.2.3"},
+		{addr: "172.16.0.1"},
+		{addr: "192.168.1.1"},
+		{addr: "169.254.169.254"},
+		{addr: "100.64.0.1"},
+		{addr: "0.0.0.0"},
+		{addr: "224.0.0.1"},
+		{addr: "fc00::1"},
+		{addr: "fe80::1"},
+		{addr: "::ffff:127.0.0.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			if got := IsPublic(netip.MustParseAddr(tt.addr)); got != tt.want {
+				t.Errorf("IsPublic() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTransport_robotsRedirect(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		redirects int
+		wantErr   error
+	}{
+		{name: "followed", redirects: 5, wantErr: ErrDisallowed},
+		{name: "too many", redirects: 6},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			mux.HandleFunc("/robots.txt", func(w http.ResponseWriter, r *http.Request) {
+				http.Redirect(w, r, "/redirect?n=1", http.StatusMovedPermanently)
+			})
+			mux.HandleFunc("/redirect", func(w http.ResponseWriter, r *http.Request) {
+				n, _ := strconv.Atoi(r.URL.Query().Get("n"))
+				if n < tt.redirects {
+					http.Redirect(w, r, "/redirect?n="+strconv.Itoa(n+1), http.StatusFound)
+					return
+				}
+				_, _ = io.WriteString(w, "User-agent: *\nDisallow: /private\n")
+			})
+			mux.HandleFunc("/private", func(w http.ResponseWriter, r *http.Request) {})
+			svr := httptest.NewServer(mux)
+			defer svr.Close()
+
+			client := NewClient(NewTransport(WithPrivateNetworks()))
+			if _, err := get(client, svr.URL+"/private"); !errors.Is(err, tt.wantErr) {
+				t.Errorf("get() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTransport_robotsOnce(t *testing.T) {
+	t.Parallel()
+
+	var fetches int32
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			atomic.AddInt32(&fetches, 1)
+			time.Sleep(20 * time.Millisecond)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer svr.Close()
+
+	client := NewClient(NewTransport(WithConcurrency(10), WithPrivateNetworks()))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := get(client, svr.URL+"/page"); err != nil {
+				t.Errorf("get() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if fetches != 1 {
+		t.Errorf("robots.txt fetches = %v, want 1", fetches)
+	}
+}
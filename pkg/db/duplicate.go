@@ -0,0 +1,28 @@
+package db
+
+// DuplicatePolicy defines how a store handles an item that is added, although an
+// item with the same canonical address is already present.
+type DuplicatePolicy int
+
+const (
+	// DuplicateReject rejects the new item with an ErrAlreadyExists.
+	DuplicateReject DuplicatePolicy = iota
+	// DuplicateUpsert replaces the present item with the new one. The new item
+	// takes over the ID of the present item.
+	DuplicateUpsert
+	// DuplicateKeepBoth stores the new item next to the present one under a new ID.
+	DuplicateKeepBoth
+)
+
+func (p DuplicatePolicy) String() string {
+	switch p {
+	case DuplicateReject:
+		return "reject"
+	case DuplicateUpsert:
+		return "upsert"
+	case DuplicateKeepBoth:
+		return "keep-both"
+	default:
+		return "unknown"
+	}
+}
@@ -2,4 +2,7 @@ package db
 
 import "errors"
 
-var ErrNotFound = errors.New("item not found")
+var (
+	ErrNotFound      = errors.New("item not found")
+	ErrAlreadyExists = errors.New("item already exists")
+)
@@ -2,41 +2,221 @@ package inmem
 
 import (
 	"context"
+	"net/url"
+	"sort"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/Br0ce/articleDB/pkg/article"
 	"github.com/Br0ce/articleDB/pkg/db"
 	"github.com/Br0ce/articleDB/pkg/ids"
+	"github.com/Br0ce/articleDB/pkg/query"
+	"github.com/Br0ce/articleDB/pkg/suggest"
 )
 
 // Article is an inmemory implemetation for the article.DB interface.
 type Article struct {
 	items map[string]article.Article
-	mu    sync.RWMutex
+	// addrs maps the canonical addr of an article to its ID.
+	addrs map[string]string
+	// published and created index the articles by their times for range queries.
+	published timeIndex
+	created   timeIndex
+	// text and entities index the text fields and the named entities for searches.
+	text     textIndex
+	entities entityIndex
+	// suggestions completes prefixes of the titles and the entity names.
+	suggestions suggest.Trie
+	duplicate   db.DuplicatePolicy
+	idGen       ids.Generator
+	// providedIDs keeps the valid ID of an added article instead of generating one.
+	providedIDs bool
+	mu          sync.RWMutex
 }
 
+type ArticleOption func(a *Article)
+
 // NewArticle is a factory for inmem.Article, that implements the
-// article.DB interface.
-func NewArticle() *Article {
-	return &Article{
+// article.DB interface. Without options duplicates are rejected and random IDs are
+// assigned.
+func NewArticle(opts ...ArticleOption) *Article {
+	a := &Article{
 		items: make(map[string]article.Article),
+		addrs: make(map[string]string),
+		idGen: ids.Random,
+	}
+
+	for _, opt := range opts {
+		opt(a)
+	}
+
+	return a
+}
+
+// WithDuplicatePolicy sets the policy how an article is handled, if an article with
+// the same canonical addr is already present.
+func WithDuplicatePolicy(policy db.DuplicatePolicy) ArticleOption {
+	return func(a *Article) {
+		a.duplicate = policy
+	}
+}
+
+// WithIDGenerator sets the generator of the IDs assigned by Add, e.g. an
+// ids.TimeOrdered generator for IDs sortable by creation time.
+func WithIDGenerator(g ids.Generator) ArticleOption {
+	return func(a *Article) {
+		a.idGen = g
+	}
+}
+
+// WithProvidedIDs keeps the ID of an added article, if it is a valid ID, e.g. a
+// content ID derived by an article.IDFunc. Only articles without a valid ID get a
+// generated ID. An article with the ID of a stored article is rejected with
+// db.ErrAlreadyExists, so adding the same article again is idempotent.
+func WithProvidedIDs() ArticleOption {
+	return func(a *Article) {
+		a.providedIDs = true
 	}
 }
 
 // Add adds an article.Article to the db and returns it assigend ID for retrieval.
-// The ID will be assigned to the article.Article.ID field by overriding its old value.
+// The ID will be assigned to the article.Article.ID field by overriding its old value,
+// unless provided IDs are kept.
+// If an article with the same canonical addr is already present, the duplicate policy
+// decides whether the article is rejected with db.ErrAlreadyExists, replaces the
+// present article or is stored under a new ID. Articles without addr are never
+// considered duplicates.
 func (a *Article) Add(ctx context.Context, item article.Article) (string, error) {
 	a.mu.Lock()
 	defer a.mu.Unlock()
 
-	id := ids.UniqueID()
+	key := article.CanonicalKey(item.Addr)
+	if presentID, ok := a.addrs[key]; ok && key != "" {
+		switch a.duplicate {
+		case db.DuplicateUpsert:
+			item.ID = presentID
+			a.store(item)
+			return presentID, nil
+		case db.DuplicateKeepBoth:
+		default:
+			return "", db.ErrAlreadyExists
+		}
+	}
+
+	id := item.ID
+	if !a.providedIDs || !ids.ValidID(id) {
+		id = a.idGen.NewID()
+	} else if _, ok := a.items[id]; ok {
+		return "", db.ErrAlreadyExists
+	}
 	item.ID = id
 
-	a.items[id] = item
+	a.store(item)
+	if _, ok := a.addrs[key]; !ok && key != "" {
+		a.addrs[key] = id
+	}
 
 	return id, nil
 }
 
+// Restore stores the article under its own ID, e.g. when restoring a backup. An
+// article with the same ID is replaced. If another article with the same canonical
+// addr is present, the article is rejected with db.ErrAlreadyExists, unless the
+// duplicate policy keeps both.
+func (a *Article) Restore(ctx context.Context, item article.Article) error {
+	if !ids.ValidID(item.ID) {
+		return ids.ErrInvalidID
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return a.put(item)
+}
+
+// Update replaces the stored article with the ID of the given article. It returns
+// db.ErrNotFound, if no article with the ID is present. If another article with the
+// same canonical addr is present, the article is rejected with db.ErrAlreadyExists,
+// unless the duplicate policy keeps both.
+func (a *Article) Update(ctx context.Context, item article.Article) error {
+	if !ids.ValidID(item.ID) {
+		return ids.ErrInvalidID
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if _, ok := a.items[item.ID]; !ok {
+		return db.ErrNotFound
+	}
+
+	return a.put(item)
+}
+
+// put stores the article under its ID and keeps the addr index in sync. The caller
+// must hold the lock.
+func (a *Article) put(item article.Article) error {
+	key := article.CanonicalKey(item.Addr)
+	presentID, ok := a.addrs[key]
+	if ok && key != "" && presentID != item.ID && a.duplicate != db.DuplicateKeepBoth {
+		return db.ErrAlreadyExists
+	}
+
+	if old, ok := a.items[item.ID]; ok {
+		if oldKey := article.CanonicalKey(old.Addr); a.addrs[oldKey] == item.ID {
+			delete(a.addrs, oldKey)
+		}
+	}
+
+	a.store(item)
+	if _, ok := a.addrs[key]; !ok && key != "" {
+		a.addrs[key] = item.ID
+	}
+
+	return nil
+}
+
+// store stores the article under its ID and keeps the indexes in sync. The caller
+// must hold the lock.
+func (a *Article) store(item article.Article) {
+	if a.text == nil {
+		a.text = make(textIndex)
+		a.entities = make(entityIndex)
+	}
+
+	if old, ok := a.items[item.ID]; ok {
+		a.published.remove(old.Published, old.ID)
+		a.created.remove(old.Created, old.ID)
+		a.text.remove(old)
+		a.entities.remove(old)
+		eachSuggestion(old, a.suggestions.Remove)
+	}
+
+	a.items[item.ID] = item
+	a.published.insert(item.Published, item.ID)
+	a.created.insert(item.Created, item.ID)
+	a.text.add(item)
+	a.entities.add(item)
+	eachSuggestion(item, a.suggestions.Add)
+}
+
+// eachSuggestion calls fn with the title and the distinct entities of the article.
+func eachSuggestion(ar article.Article, fn func(text string, kind suggest.Kind, typ article.EntityType)) {
+	if ar.Title != "" {
+		fn(ar.Title, suggest.KindTitle, "")
+	}
+	seen := make(map[entityKey]bool)
+	for _, e := range ar.NER.Entities {
+		key := entityKey{typ: e.Type, name: strings.ToLower(strings.TrimSpace(e.Name))}
+		if key.name == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		fn(e.Name, suggest.KindEntity, e.Type)
+	}
+}
+
 // Get returns the article.Article for the given ID.
 func (a *Article) Get(ctx context.Context, id string) (article.Article, error) {
 	if !ids.ValidID(id) {
@@ -53,3 +233,153 @@ func (a *Article) Get(ctx context.Context, id string) (article.Article, error) {
 
 	return item, nil
 }
+
+// GetByAddr returns the article with the same canonical addr as the given addr. It
+// returns db.ErrNotFound, if no such article is present.
+func (a *Article) GetByAddr(ctx context.Context, addr url.URL) (article.Article, error) {
+	key := article.CanonicalKey(addr)
+	if key == "" {
+		return article.Article{}, db.ErrNotFound
+	}
+
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	id, ok := a.addrs[key]
+	if !ok {
+		return article.Article{}, db.ErrNotFound
+	}
+
+	return a.items[id], nil
+}
+
+// List returns all stored articles ordered by ID.
+func (a *Article) List(ctx context.Context) ([]article.Article, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	items := make([]article.Article, 0, len(a.items))
+	for _, item := range a.items {
+		items = append(items, item)
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].ID < items[j].ID
+	})
+
+	return items, nil
+}
+
+// Range returns the articles in the time range of the query, that pass its filters.
+// The articles are selected from an index of the time, ordered by the time and on a
+// tie by ID.
+func (a *Article) Range(ctx context.Context, q article.RangeQuery) ([]article.Article, error) {
+	err := q.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	index := &a.published
+	if q.Field == article.TimeCreated {
+		index = &a.created
+	}
+
+	var items []article.Article
+	index.scan(q.From, q.To, q.Order == article.Descending, func(id string) bool {
+		item := a.items[id]
+		if q.Match(item) {
+			items = append(items, item)
+		}
+		return q.Limit == 0 || len(items) < q.Limit
+	})
+
+	return items, nil
+}
+
+// Search runs the plan against the indexes and returns up to limit hits, the latest
+// published article first. A limit of 0 returns all hits. The hits carry the
+// offsets of the terms of the plan in the title, the body and the summary. A term is
+// only matched in the field it is searched in.
+func (a *Article) Search(ctx context.Context, p *query.Plan, limit int) ([]query.Hit, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	matched := p.Run(indexView{a: a})
+
+	items := make([]article.Article, 0, len(matched))
+	for id := range matched {
+		items = append(items, a.items[id])
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if !items[i].Published.Equal(items[j].Published) {
+			return items[i].Published.After(items[j].Published)
+		}
+		return items[i].ID < items[j].ID
+	})
+
+	if limit > 0 && len(items) > limit {
+		items = items[:limit]
+	}
+
+	terms := p.Terms()
+	hits := make([]query.Hit, 0, len(items))
+	for _, item := range items {
+		hit := query.Hit{Article: item, Matches: make(map[query.TextField][]query.Match)}
+		for _, field := range []query.TextField{query.TextTitle, query.TextBody, query.TextSummary} {
+			if matches := a.text.matches(item, field, terms); len(matches) > 0 {
+				hit.Matches[field] = matches
+			}
+		}
+		hits = append(hits, hit)
+	}
+	return hits, nil
+}
+
+// Suggest returns up to limit titles and entity names, that have a word starting
+// with the prefix or within a small edit distance of it. A limit of 0 returns all
+// suggestions.
+func (a *Article) Suggest(ctx context.Context, prefix string, limit int) ([]suggest.Suggestion, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	return a.suggestions.Complete(prefix, limit), nil
+}
+
+// indexView provides the indexes of the article to a query.Plan. The caller must
+// hold the lock.
+type indexView struct {
+	a *Article
+}
+
+func (v indexView) Phrase(field query.TextField, terms []string, distance int) query.Set {
+	return v.a.text.phrase(field, terms, distance)
+}
+
+func (v indexView) Entity(t article.EntityType, name string, distance int) query.Set {
+	return v.a.entities.lookup(t, name, distance)
+}
+
+func (v indexView) Range(field article.TimeField, from, to time.Time) query.Set {
+	index := &v.a.published
+	if field == article.TimeCreated {
+		index = &v.a.created
+	}
+
+	result := make(query.Set)
+	index.scan(from, to, false, func(id string) bool {
+		result[id] = struct{}{}
+		return true
+	})
+	return result
+}
+
+func (v indexView) All() query.Set {
+	result := make(query.Set, len(v.a.items))
+	for id := range v.a.items {
+		result[id] = struct{}{}
+	}
+	return result
+}
@@ -2,11 +2,18 @@ package inmem
 
 import (
 	"context"
+	"errors"
+	"net/url"
 	"reflect"
+	"sort"
 	"testing"
+	"time"
 
 	"github.com/Br0ce/articleDB/pkg/article"
+	"github.com/Br0ce/articleDB/pkg/db"
 	"github.com/Br0ce/articleDB/pkg/ids"
+	"github.com/Br0ce/articleDB/pkg/query"
+	"github.com/Br0ce/articleDB/pkg/suggest"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -46,6 +53,7 @@ func TestArticle_Add(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			a := &Article{
 				items: tt.items,
+				idGen: ids.Random,
 			}
 
 			id, err := a.Add(tt.args.ctx, tt.args.item)
@@ -67,10 +75,154 @@ func TestArticle_Add(t *testing.T) {
 	}
 }
 
+func TestArticle_Add_duplicate(t *testing.T) {
+	t.Parallel()
+
+	first := article.Article{
+		Title: "first",
+		Addr:  url.URL{Scheme: "https", Host: "news.example.com", Path: "/story"},
+	}
+	second := article.Article{
+		Title: "second",
+		Addr: url.URL{
+			Scheme:   "https",
+			Host:     "News.Example.com",
+			Path:     "/story/",
+			RawQuery: "utm_source=feed",
+		},
+	}
+
+	tests := []struct {
+		name      string
+		policy    db.DuplicatePolicy
+		wantErr   error
+		wantSame  bool
+		wantLen   int
+		wantTitle string
+	}{
+		{
+			name:      "reject",
+			policy:    db.DuplicateReject,
+			wantErr:   db.ErrAlreadyExists,
+			wantLen:   1,
+			wantTitle: "first",
+		},
+		{
+			name:      "upsert",
+			policy:    db.DuplicateUpsert,
+			wantSame:  true,
+			wantLen:   1,
+			wantTitle: "second",
+		},
+		{
+			name:      "keep both",
+			policy:    db.DuplicateKeepBoth,
+			wantLen:   2,
+			wantTitle: "first",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := NewArticle(WithDuplicatePolicy(tt.policy))
+			ctx := context.TODO()
+
+			firstID, err := a.Add(ctx, first)
+			if err != nil {
+				t.Fatalf("could not add first article, %s", err.Error())
+			}
+
+			secondID, err := a.Add(ctx, second)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Article.Add() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && (firstID == secondID) != tt.wantSame {
+				t.Errorf("Article.Add() same id = %v, want %v", firstID == secondID, tt.wantSame)
+			}
+
+			if len(a.items) != tt.wantLen {
+				t.Errorf("items len = %v, want %v", len(a.items), tt.wantLen)
+			}
+
+			got, err := a.Get(ctx, firstID)
+			if err != nil {
+				t.Fatalf("could not get first article, %s", err.Error())
+			}
+			if got.Title != tt.wantTitle {
+				t.Errorf("title = %v, want %v", got.Title, tt.wantTitle)
+			}
+		})
+	}
+}
+
+func TestArticle_Add_providedIDs(t *testing.T) {
+	t.Parallel()
+
+	contentID := ids.NameID("https://news.example.com/story")
+
+	tests := []struct {
+		name     string
+		opts     []ArticleOption
+		ids      []string
+		wantErrs []error
+		wantKept []bool
+	}{
+		{
+			name:     "keep valid id",
+			opts:     []ArticleOption{WithProvidedIDs()},
+			ids:      []string{contentID},
+			wantErrs: []error{nil},
+			wantKept: []bool{true},
+		},
+		{
+			name:     "reject present id",
+			opts:     []ArticleOption{WithProvidedIDs(), WithDuplicatePolicy(db.DuplicateKeepBoth)},
+			ids:      []string{contentID, contentID},
+			wantErrs: []error{nil, db.ErrAlreadyExists},
+			wantKept: []bool{true, false},
+		},
+		{
+			name:     "generate id for invalid id",
+			opts:     []ArticleOption{WithProvidedIDs()},
+			ids:      []string{"", "1234"},
+			wantErrs: []error{nil, nil},
+			wantKept: []bool{false, false},
+		},
+		{
+			name:     "overwrite id without provided ids",
+			ids:      []string{contentID},
+			wantErrs: []error{nil},
+			wantKept: []bool{false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := NewArticle(tt.opts...)
+
+			for i, id := range tt.ids {
+				got, err := a.Add(context.TODO(), article.Article{ID: id})
+				if !errors.Is(err, tt.wantErrs[i]) {
+					t.Fatalf("Article.Add() error = %v, want %v", err, tt.wantErrs[i])
+				}
+				if err != nil {
+					continue
+				}
+				if (got == id) != tt.wantKept[i] {
+					t.Errorf("Article.Add() = %v, kept id %v = %v, want %v", got, id, got == id, tt.wantKept[i])
+				}
+				if !ids.ValidID(got) {
+					t.Errorf("Article.Add() = invalid id %v", got)
+				}
+			}
+		})
+	}
+}
+
 func TestArticle_AddAndGet_parallel(t *testing.T) {
 	t.Parallel()
 
-	db := Article{items: make(map[string]article.Article)}
+	db := Article{items: make(map[string]article.Article), idGen: ids.Random}
 
 	eg := new(errgroup.Group)
 	ctx := context.TODO()
@@ -213,4 +365,723 @@ func TestNewArticle(t *testing.T) {
 	if got.items == nil {
 		t.Error("Article items is nil")
 	}
+	if got.addrs == nil {
+		t.Error("Article addrs is nil")
+	}
+	if got.duplicate != db.DuplicateReject {
+		t.Errorf("Article duplicate policy = %v, want %v", got.duplicate, db.DuplicateReject)
+	}
+	if got.idGen == nil {
+		t.Error("Article id generator is nil")
+	}
+}
+
+func TestArticle_List(t *testing.T) {
+	t.Parallel()
+
+	a := NewArticle()
+	ctx := context.TODO()
+
+	num := 10
+	for i := 0; i < num; i++ {
+		if _, err := a.Add(ctx, article.Article{}); err != nil {
+			t.Fatalf("could not add article, %s", err.Error())
+		}
+	}
+
+	got, err := a.List(ctx)
+	if err != nil {
+		t.Fatalf("Article.List() error = %v", err)
+	}
+
+	if len(got) != num {
+		t.Fatalf("Article.List() len = %v, want %v", len(got), num)
+	}
+
+	for i := 1; i < len(got); i++ {
+		if got[i-1].ID >= got[i].ID {
+			t.Errorf("Article.List() not ordered by id")
+		}
+	}
+}
+
+func TestArticle_List_timeOrdered(t *testing.T) {
+	t.Parallel()
+
+	a := NewArticle(WithIDGenerator(ids.NewTimeOrdered()))
+	ctx := context.TODO()
+
+	var want []string
+	for i := 0; i < 100; i++ {
+		id, err := a.Add(ctx, article.Article{})
+		if err != nil {
+			t.Fatalf("could not add article, %s", err.Error())
+		}
+		want = append(want, id)
+	}
+
+	items, err := a.List(ctx)
+	if err != nil {
+		t.Fatalf("Article.List() error = %v", err)
+	}
+
+	var got []string
+	for _, item := range items {
+		got = append(got, item.ID)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Article.List() is not in insertion order")
+	}
+}
+
+func TestArticle_Restore(t *testing.T) {
+	t.Parallel()
+
+	addr := url.URL{Scheme: "https", Host: "news.example.com", Path: "/story"}
+	present := article.Article{ID: ids.UniqueID(), Title: "present", Addr: addr}
+
+	tests := []struct {
+		name    string
+		policy  db.DuplicatePolicy
+		item    article.Article
+		wantErr error
+		wantLen int
+	}{
+		{
+			name:    "new id",
+			item:    article.Article{ID: ids.UniqueID(), Title: "restored", Addr: url.URL{Scheme: "https", Host: "news.example.com", Path: "/other"}},
+			wantLen: 2,
+		},
+		{
+			name:    "same id",
+			item:    article.Article{ID: present.ID, Title: "restored", Addr: addr},
+			wantLen: 1,
+		},
+		{
+			name:    "duplicate addr",
+			item:    article.Article{ID: ids.UniqueID(), Title: "restored", Addr: addr},
+			wantErr: db.ErrAlreadyExists,
+			wantLen: 1,
+		},
+		{
+			name:    "duplicate addr kept",
+			policy:  db.DuplicateKeepBoth,
+			item:    article.Article{ID: ids.UniqueID(), Title: "restored", Addr: addr},
+			wantLen: 2,
+		},
+		{
+			name:    "invalid id",
+			item:    article.Article{ID: "1234", Title: "restored"},
+			wantErr: ids.ErrInvalidID,
+			wantLen: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := NewArticle(WithDuplicatePolicy(tt.policy))
+			ctx := context.TODO()
+
+			err := a.Restore(ctx, present)
+			if err != nil {
+				t.Fatalf("could not restore present article, %s", err.Error())
+			}
+
+			err = a.Restore(ctx, tt.item)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Article.Restore() error = %v, want %v", err, tt.wantErr)
+			}
+			if len(a.items) != tt.wantLen {
+				t.Errorf("items len = %v, want %v", len(a.items), tt.wantLen)
+			}
+			if err != nil {
+				return
+			}
+
+			got, err := a.Get(ctx, tt.item.ID)
+			if err != nil {
+				t.Fatalf("could not get restored article, %s", err.Error())
+			}
+			if !reflect.DeepEqual(got, tt.item) {
+				t.Errorf("Article.Get() = %+v, want %+v", got, tt.item)
+			}
+		})
+	}
+}
+
+func TestArticle_Update(t *testing.T) {
+	t.Parallel()
+
+	addr := url.URL{Scheme: "https", Host: "news.example.com", Path: "/story"}
+	otherAddr := url.URL{Scheme: "https", Host: "news.example.com", Path: "/other"}
+	present := article.Article{ID: ids.UniqueID(), Title: "present", Addr: addr}
+	other := article.Article{ID: ids.UniqueID(), Title: "other", Addr: otherAddr}
+
+	tests := []struct {
+		name    string
+		item    article.Article
+		wantErr error
+	}{
+		{
+			name: "same addr",
+			item: article.Article{ID: present.ID, Title: "updated", Addr: addr},
+		},
+		{
+			name: "moved addr",
+			item: article.Article{ID: present.ID, Title: "updated", Addr: url.URL{Scheme: "https", Host: "news.example.com", Path: "/moved"}},
+		},
+		{
+			name:    "addr of other article",
+			item:    article.Article{ID: present.ID, Title: "updated", Addr: otherAddr},
+			wantErr: db.ErrAlreadyExists,
+		},
+		{
+			name:    "not found",
+			item:    article.Article{ID: ids.UniqueID(), Title: "updated", Addr: addr},
+			wantErr: db.ErrNotFound,
+		},
+		{
+			name:    "invalid id",
+			item:    article.Article{ID: "1234", Title: "updated"},
+			wantErr: ids.ErrInvalidID,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := NewArticle()
+			ctx := context.TODO()
+
+			for _, ar := range []article.Article{present, other} {
+				err := a.Restore(ctx, ar)
+				if err != nil {
+					t.Fatalf("could not restore article, %s", err.Error())
+				}
+			}
+
+			err := a.Update(ctx, tt.item)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Article.Update() error = %v, want %v", err, tt.wantErr)
+			}
+			if len(a.items) != 2 {
+				t.Errorf("items len = %v, want 2", len(a.items))
+			}
+			if err != nil {
+				return
+			}
+
+			got, err := a.Get(ctx, tt.item.ID)
+			if err != nil {
+				t.Fatalf("could not get updated article, %s", err.Error())
+			}
+			if !reflect.DeepEqual(got, tt.item) {
+				t.Errorf("Article.Get() = %+v, want %+v", got, tt.item)
+			}
+
+			// The new addr is a duplicate of the updated article.
+			_, err = a.Add(ctx, article.Article{Title: "new", Addr: tt.item.Addr})
+			if !errors.Is(err, db.ErrAlreadyExists) {
+				t.Errorf("Article.Add() error = %v, want %v", err, db.ErrAlreadyExists)
+			}
+		})
+	}
+}
+
+func TestArticle_GetByAddr(t *testing.T) {
+	t.Parallel()
+
+	a := NewArticle()
+	ctx := context.TODO()
+	id, err := a.Add(ctx, article.Article{Title: "story", Addr: url.URL{Scheme: "https", Host: "news.example.com", Path: "/story"}})
+	if err != nil {
+		t.Fatalf("could not add article, %s", err.Error())
+	}
+
+	tests := []struct {
+		name    string
+		addr    url.URL
+		wantID  string
+		wantErr error
+	}{
+		{
+			name:   "canonical addr",
+			addr:   url.URL{Scheme: "HTTPS", Host: "news.example.com", Path: "/story/", RawQuery: "utm_source=feed"},
+			wantID: id,
+		},
+		{
+			name:    "other addr",
+			addr:    url.URL{Scheme: "https", Host: "news.example.com", Path: "/other"},
+			wantErr: db.ErrNotFound,
+		},
+		{
+			name:    "no addr",
+			wantErr: db.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := a.GetByAddr(ctx, tt.addr)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Article.GetByAddr() error = %v, want %v", err, tt.wantErr)
+			}
+			if got.ID != tt.wantID {
+				t.Errorf("Article.GetByAddr() ID = %v, want %v", got.ID, tt.wantID)
+			}
+		})
+	}
+}
+
+func TestArticle_Range(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.TODO()
+	day := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	a := NewArticle()
+
+	add := func(title string, published, created time.Time, names ...string) {
+		_, err := a.Add(ctx, article.Article{
+			Title:     title,
+			Published: published,
+			Created:   created,
+			NER:       article.NER{Entities: article.EntitiesOf(article.Location, names...)},
+		})
+		if err != nil {
+			t.Fatalf("could not add article, %s", err.Error())
+		}
+	}
+	add("undated", time.Time{}, day.AddDate(0, 0, 3))
+	add("second", day.AddDate(0, 0, 1), day.AddDate(0, 0, 1), "Berlin")
+	add("first", day, day.AddDate(0, 0, 2), "Berlin")
+	add("third", day.AddDate(0, 0, 2), day, "Hamburg")
+
+	tests := []struct {
+		name    string
+		q       article.RangeQuery
+		want    []string
+		wantErr error
+	}{
+		{
+			name: "all ascending",
+			q:    article.RangeQuery{},
+			want: []string{"undated", "first", "second", "third"},
+		},
+		{
+			name: "from is inclusive, to is exclusive",
+			q:    article.RangeQuery{From: day, To: day.AddDate(0, 0, 2)},
+			want: []string{"first", "second"},
+		},
+		{
+			name: "descending with limit",
+			q:    article.RangeQuery{From: day, Order: article.Descending, Limit: 2},
+			want: []string{"third", "second"},
+		},
+		{
+			name: "created",
+			q:    article.RangeQuery{Field: article.TimeCreated, To: day.AddDate(0, 0, 3)},
+			want: []string{"third", "second", "first"},
+		},
+		{
+			name: "filters with limit",
+			q:    article.RangeQuery{Entities: []string{"Berlin"}, Text: "s", Limit: 1},
+			want: []string{"first"},
+		},
+		{
+			name:    "invalid range",
+			q:       article.RangeQuery{From: day, To: day},
+			wantErr: article.ErrInvalidRange,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items, err := a.Range(ctx, tt.q)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Article.Range() error = %v, want %v", err, tt.wantErr)
+			}
+
+			var got []string
+			for _, item := range items {
+				got = append(got, item.Title)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Article.Range() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestArticle_Range_update(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.TODO()
+	day := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	a := NewArticle()
+
+	id, err := a.Add(ctx, article.Article{Title: "story", Published: day})
+	if err != nil {
+		t.Fatalf("could not add article, %s", err.Error())
+	}
+	err = a.Update(ctx, article.Article{ID: id, Title: "story", Published: day.AddDate(0, 0, 1)})
+	if err != nil {
+		t.Fatalf("could not update article, %s", err.Error())
+	}
+
+	got, err := a.Range(ctx, article.RangeQuery{From: day, To: day.AddDate(0, 0, 1)})
+	if err != nil {
+		t.Fatalf("Article.Range() error = %v", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("Article.Range() found the old date, %v", got)
+	}
+
+	got, err = a.Range(ctx, article.RangeQuery{From: day.AddDate(0, 0, 1)})
+	if err != nil {
+		t.Fatalf("Article.Range() error = %v", err)
+	}
+	if len(got) != 1 || got[0].ID != id {
+		t.Errorf("Article.Range() = %v, want the updated article", got)
+	}
+	if len(a.published.entries) != 1 || len(a.created.entries) != 1 {
+		t.Errorf("index len = %v, %v, want 1", len(a.published.entries), len(a.created.entries))
+	}
+}
+
+func TestArticle_Search(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.TODO()
+	day := time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)
+	a := NewArticle()
+
+	add := func(ar article.Article) string {
+		id, err := a.Add(ctx, ar)
+		if err != nil {
+			t.Fatalf("could not add article, %s", err.Error())
+		}
+		return id
+	}
+	talks := add(article.Article{
+		Title:     "Talks on the energy crisis",
+		Body:      "Olaf Scholz met the EU about the energy crisis.",
+		Author:    "Jane Doe",
+		Published: day,
+		NER: article.NER{Entities: append(article.EntitiesOf(article.Person, "Olaf Scholz"),
+			article.EntitiesOf(article.Organisation, "EU")...)},
+	})
+	prices := add(article.Article{
+		Title:     "Prices",
+		Body:      "The crisis of energy prices.",
+		Published: day.AddDate(0, 1, 0),
+		NER:       article.NER{Entities: article.EntitiesOf(article.Organisation, "EU")},
+	})
+	later := add(article.Article{
+		Title:     "Energy crisis again",
+		Published: day.AddDate(1, 0, 0),
+		NER:       article.NER{Entities: article.EntitiesOf(article.Organisation, "eu")},
+	})
+
+	tests := []struct {
+		name  string
+		q     string
+		limit int
+		want  []string
+	}{
+		{
+			name: "compound query",
+			q:    `person:"Olaf Scholz" AND org:EU AND published:[2023-01-01 TO 2023-06-30] AND "energy crisis"`,
+			want: []string{talks},
+		},
+		{
+			name: "phrase needs consecutive terms",
+			q:    `"energy crisis"`,
+			want: []string{later, talks},
+		},
+		{
+			name: "entity ignores case, latest first",
+			q:    `org:EU`,
+			want: []string{later, prices, talks},
+		},
+		{
+			name:  "limit",
+			q:     `entity:eu`,
+			limit: 1,
+			want:  []string{later},
+		},
+		{
+			name: "field and not",
+			q:    `energy NOT title:again author:doe OR title:prices`,
+			want: []string{prices, talks},
+		},
+		{
+			name: "fuzzy name",
+			q:    `person:"Olaf Scholtz"~`,
+			want: []string{talks},
+		},
+		{
+			name: "fuzzy phrase",
+			q:    `"enrgy crsis"~1`,
+			want: []string{later, talks},
+		},
+		{
+			name: "fuzziness depends on the length",
+			q:    `org:EUU~2 OR ue~2`,
+			want: []string{later, prices, talks},
+		},
+		{
+			name: "exact without fuzziness",
+			q:    `scholtz`,
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := query.Compile(tt.q)
+			if err != nil {
+				t.Fatalf("could not compile query, %s", err.Error())
+			}
+			hits, err := a.Search(ctx, p, tt.limit)
+			if err != nil {
+				t.Fatalf("Article.Search() error = %v", err)
+			}
+
+			var got []string
+			for _, hit := range hits {
+				got = append(got, hit.Article.ID)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Article.Search() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("matches", func(t *testing.T) {
+		p, err := query.Compile(`"energy crisis" NOT olaf`)
+		if err != nil {
+			t.Fatalf("could not compile query, %s", err.Error())
+		}
+		hits, err := a.Search(ctx, p, 0)
+		if err != nil {
+			t.Fatalf("Article.Search() error = %v", err)
+		}
+
+		want := map[query.TextField][]query.Match{
+			query.TextTitle: {{Start: 0, End: 6}, {Start: 7, End: 13}},
+		}
+		if len(hits) != 1 || !reflect.DeepEqual(hits[0].Matches, want) {
+			t.Errorf("Article.Search() = %+v, want matches %v", hits, want)
+		}
+	})
+
+	t.Run("matches in their field", func(t *testing.T) {
+		p, err := query.Compile(`title:energy AND crisis AND author:doe`)
+		if err != nil {
+			t.Fatalf("could not compile query, %s", err.Error())
+		}
+		hits, err := a.Search(ctx, p, 0)
+		if err != nil {
+			t.Fatalf("Article.Search() error = %v", err)
+		}
+
+		want := map[query.TextField][]query.Match{
+			query.TextTitle: {{Start: 13, End: 19}, {Start: 20, End: 26}},
+			query.TextBody:  {{Start: 40, End: 46}},
+		}
+		if len(hits) != 1 || !reflect.DeepEqual(hits[0].Matches, want) {
+			t.Errorf("Article.Search() = %+v, want matches %v", hits, want)
+		}
+	})
+
+	t.Run("fuzzy matches", func(t *testing.T) {
+		p, err := query.Compile(`title:crsis~ again`)
+		if err != nil {
+			t.Fatalf("could not compile query, %s", err.Error())
+		}
+		hits, err := a.Search(ctx, p, 0)
+		if err != nil {
+			t.Fatalf("Article.Search() error = %v", err)
+		}
+
+		want := map[query.TextField][]query.Match{
+			query.TextTitle: {{Start: 7, End: 13}, {Start: 14, End: 19}},
+		}
+		if len(hits) != 1 || !reflect.DeepEqual(hits[0].Matches, want) {
+			t.Errorf("Article.Search() = %+v, want matches %v", hits, want)
+		}
+	})
+
+	t.Run("update reindexes", func(t *testing.T) {
+		err := a.Update(ctx, article.Article{ID: prices, Title: "Prices", Body: "Cheap oil."})
+		if err != nil {
+			t.Fatalf("could not update article, %s", err.Error())
+		}
+
+		for q, want := range map[string]int{"energy": 2, "oil": 1, "org:EU": 2} {
+			p, err := query.Compile(q)
+			if err != nil {
+				t.Fatalf("could not compile query, %s", err.Error())
+			}
+			got, err := a.Search(ctx, p, 0)
+			if err != nil {
+				t.Fatalf("Article.Search() error = %v", err)
+			}
+			if len(got) != want {
+				t.Errorf("Article.Search(%v) len = %v, want %v", q, len(got), want)
+			}
+		}
+	})
+}
+
+func TestArticle_Search_languages(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.TODO()
+	a := NewArticle()
+
+	add := func(ar article.Article) string {
+		id, err := a.Add(ctx, ar)
+		if err != nil {
+			t.Fatalf("could not add article, %s", err.Error())
+		}
+		return id
+	}
+	english := add(article.Article{
+		Title:    "Prices of houses rise",
+		Body:     "The price of a house in Berlin rose again.",
+		Language: article.English,
+	})
+	german := add(article.Article{
+		Title:    "Die Preise für Häuser steigen",
+		Body:     "Der Preis eines Hauses in Berlin stieg erneut.",
+		Language: article.German,
+	})
+	unknown := add(article.Article{
+		Title: "Houses",
+		Body:  "The houses of Berlin.",
+	})
+
+	tests := []struct {
+		name        string
+		q           string
+		want        []string
+		wantMatches map[query.TextField][]query.Match
+	}{
+		{
+			name: "stemmed in the language of the article",
+			q:    `title:house`,
+			want: []string{english},
+			wantMatches: map[query.TextField][]query.Match{
+				query.TextTitle: {{Start: 10, End: 16}},
+			},
+		},
+		{
+			name: "umlauts and inflections",
+			q:    `title:haus`,
+			want: []string{german},
+			wantMatches: map[query.TextField][]query.Match{
+				query.TextTitle: {{Start: 16, End: 23}},
+			},
+		},
+		{
+			name: "phrase skips stopwords",
+			q:    `"preise für häuser"`,
+			want: []string{german},
+		},
+		{
+			name: "phrase keeps gaps of stopwords",
+			q:    `"preise häuser"`,
+			want: nil,
+		},
+		{
+			name: "stopword matches any word in its gap",
+			q:    `"houses of berlin"`,
+			want: []string{english, unknown},
+		},
+		{
+			name: "stopwords are only indexed without language",
+			q:    `the`,
+			want: []string{unknown},
+		},
+		{
+			name: "any language",
+			q:    `berlin`,
+			want: []string{english, german, unknown},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := query.Compile(tt.q)
+			if err != nil {
+				t.Fatalf("could not compile query, %s", err.Error())
+			}
+			hits, err := a.Search(ctx, p, 0)
+			if err != nil {
+				t.Fatalf("Article.Search() error = %v", err)
+			}
+
+			var got []string
+			for _, hit := range hits {
+				got = append(got, hit.Article.ID)
+			}
+			sort.Strings(got)
+			sort.Strings(tt.want)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("Article.Search() = %v, want %v", got, tt.want)
+			}
+			if tt.wantMatches != nil && !reflect.DeepEqual(hits[0].Matches, tt.wantMatches) {
+				t.Errorf("Article.Search() matches = %v, want %v", hits[0].Matches, tt.wantMatches)
+			}
+		})
+	}
+}
+
+func TestArticle_Suggest(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.TODO()
+	a := NewArticle()
+
+	add := func(ar article.Article) string {
+		id, err := a.Add(ctx, ar)
+		if err != nil {
+			t.Fatalf("could not add article, %s", err.Error())
+		}
+		return id
+	}
+	add(article.Article{
+		Title: "Scholz visits Paris",
+		NER: article.NER{Entities: append(article.EntitiesOf(article.Person, "Olaf Scholz", "Olaf Scholz"),
+			article.EntitiesOf(article.Location, "Paris")...)},
+	})
+	id := add(article.Article{
+		Title: "Energy prices",
+		NER:   article.NER{Entities: article.EntitiesOf(article.Person, "Olaf Scholz")},
+	})
+
+	got, err := a.Suggest(ctx, "scholtz", 10)
+	if err != nil {
+		t.Fatalf("Article.Suggest() error = %v", err)
+	}
+	want := []suggest.Suggestion{
+		{Text: "Olaf Scholz", Kind: suggest.KindEntity, Type: article.Person, Count: 2, Distance: 1},
+		{Text: "Scholz visits Paris", Kind: suggest.KindTitle, Count: 1, Distance: 1},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Article.Suggest() = %+v, want %+v", got, want)
+	}
+
+	err = a.Update(ctx, article.Article{ID: id, Title: "Oil prices"})
+	if err != nil {
+		t.Fatalf("could not update article, %s", err.Error())
+	}
+	got, err = a.Suggest(ctx, "pri", 1)
+	if err != nil {
+		t.Fatalf("Article.Suggest() error = %v", err)
+	}
+	want = []suggest.Suggestion{{Text: "Oil prices", Kind: suggest.KindTitle, Count: 1}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Article.Suggest() after update = %+v, want %+v", got, want)
+	}
 }
@@ -0,0 +1,139 @@
+package inmem
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/Br0ce/articleDB/pkg/article"
+	"github.com/Br0ce/articleDB/pkg/db"
+	"github.com/Br0ce/articleDB/pkg/ids"
+)
+
+// DefaultRetention is the number of revisions kept per article, if no retention
+// is set.
+const DefaultRetention = 20
+
+// Revision is an inmemory implementation of the article.RevisionStore interface.
+type Revision struct {
+	items     map[string][]article.Revision
+	retention int
+	mu        sync.RWMutex
+}
+
+type RevisionOption func(r *Revision)
+
+// NewRevision returns an inmem.Revision, that keeps the DefaultRetention latest
+// revisions of every article.
+func NewRevision(opts ...RevisionOption) *Revision {
+	r := &Revision{
+		items:     make(map[string][]article.Revision),
+		retention: DefaultRetention,
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
+}
+
+// WithRetention sets the number of revisions kept per article. Older revisions are
+// dropped. A retention of 0 or less keeps all revisions.
+func WithRetention(n int) RevisionOption {
+	return func(r *Revision) {
+		r.retention = n
+	}
+}
+
+// Add stores rev as the next revision of its article. The number is assigned and
+// the creation time is set, if it is zero.
+func (r *Revision) Add(ctx context.Context, rev article.Revision) (article.Revision, error) {
+	if !ids.ValidID(rev.ArticleID) {
+		return article.Revision{}, ids.ErrInvalidID
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	revs := r.items[rev.ArticleID]
+	rev.Number = 1
+	if len(revs) > 0 {
+		rev.Number = revs[len(revs)-1].Number + 1
+	}
+	if rev.Created.IsZero() {
+		rev.Created = time.Now().UTC()
+	}
+	rev.Article.Embedding = nil
+
+	revs = append(revs, rev)
+	if r.retention > 0 && len(revs) > r.retention {
+		// The kept revisions are copied, so the dropped ones can be collected.
+		revs = append([]article.Revision(nil), revs[len(revs)-r.retention:]...)
+	}
+	r.items[rev.ArticleID] = revs
+
+	return rev, nil
+}
+
+// Restore stores rev with its own number, e.g. from a backup. A stored revision
+// with the same number is replaced.
+func (r *Revision) Restore(ctx context.Context, rev article.Revision) error {
+	if !ids.ValidID(rev.ArticleID) {
+		return ids.ErrInvalidID
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rev.Article.Embedding = nil
+
+	revs := r.items[rev.ArticleID]
+	i, found := slices.BinarySearchFunc(revs, rev.Number, func(kept article.Revision, number int) int {
+		return kept.Number - number
+	})
+	if found {
+		revs[i] = rev
+	} else {
+		revs = slices.Insert(revs, i, rev)
+	}
+	if r.retention > 0 && len(revs) > r.retention {
+		revs = append([]article.Revision(nil), revs[len(revs)-r.retention:]...)
+	}
+	r.items[rev.ArticleID] = revs
+
+	return nil
+}
+
+// List returns the kept revisions of the article ordered by number. It returns an
+// empty list, if the article has no revisions.
+func (r *Revision) List(ctx context.Context, articleID string) ([]article.Revision, error) {
+	if !ids.ValidID(articleID) {
+		return nil, ids.ErrInvalidID
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return append([]article.Revision{}, r.items[articleID]...), nil
+}
+
+// Get returns the revision of the article with the given number. It returns
+// db.ErrNotFound, if the revision does not exist or has been dropped.
+func (r *Revision) Get(ctx context.Context, articleID string, number int) (article.Revision, error) {
+	if !ids.ValidID(articleID) {
+		return article.Revision{}, ids.ErrInvalidID
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, rev := range r.items[articleID] {
+		if rev.Number == number {
+			return rev, nil
+		}
+	}
+
+	return article.Revision{}, db.ErrNotFound
+}
@@ -0,0 +1,177 @@
+package inmem
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Br0ce/articleDB/pkg/article"
+	"github.com/Br0ce/articleDB/pkg/db"
+	"github.com/Br0ce/articleDB/pkg/ids"
+)
+
+func TestRevision_Add(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		retention   int
+		adds        int
+		wantNumbers []int
+	}{
+		{
+			name:        "below retention",
+			retention:   3,
+			adds:        2,
+			wantNumbers: []int{1, 2},
+		},
+		{
+			name:        "retention exceeded",
+			retention:   3,
+			adds:        5,
+			wantNumbers: []int{3, 4, 5},
+		},
+		{
+			name:        "unbounded",
+			retention:   0,
+			adds:        4,
+			wantNumbers: []int{1, 2, 3, 4},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRevision(WithRetention(tt.retention))
+			ctx := context.TODO()
+			id := ids.UniqueID()
+
+			for i := 0; i < tt.adds; i++ {
+				rev, err := r.Add(ctx, article.Revision{
+					ArticleID: id,
+					Article:   article.Article{ID: id, Embedding: []float32{1}},
+				})
+				if err != nil {
+					t.Fatalf("Revision.Add() error = %v", err)
+				}
+				if rev.Number != i+1 || rev.Created.IsZero() || rev.Article.Embedding != nil {
+					t.Errorf("Revision.Add() = %+v", rev)
+				}
+			}
+
+			revs, err := r.List(ctx, id)
+			if err != nil {
+				t.Fatalf("Revision.List() error = %v", err)
+			}
+			var numbers []int
+			for _, rev := range revs {
+				numbers = append(numbers, rev.Number)
+			}
+			if !reflect.DeepEqual(numbers, tt.wantNumbers) {
+				t.Errorf("Revision.List() numbers = %v, want %v", numbers, tt.wantNumbers)
+			}
+		})
+	}
+}
+
+func TestRevision_Get(t *testing.T) {
+	t.Parallel()
+
+	r := NewRevision(WithRetention(2))
+	ctx := context.TODO()
+	id := ids.UniqueID()
+	created := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	for _, title := range []string{"first", "second", "third"} {
+		_, err := r.Add(ctx, article.Revision{ArticleID: id, Created: created, Article: article.Article{Title: title}})
+		if err != nil {
+			t.Fatalf("could not add revision, %s", err.Error())
+		}
+	}
+
+	tests := []struct {
+		name      string
+		articleID string
+		number    int
+		wantTitle string
+		wantErr   error
+	}{
+		{
+			name:      "kept",
+			articleID: id,
+			number:    3,
+			wantTitle: "third",
+		},
+		{
+			name:      "dropped",
+			articleID: id,
+			number:    1,
+			wantErr:   db.ErrNotFound,
+		},
+		{
+			name:      "unknown article",
+			articleID: ids.UniqueID(),
+			number:    1,
+			wantErr:   db.ErrNotFound,
+		},
+		{
+			name:      "invalid id",
+			articleID: "1234",
+			number:    1,
+			wantErr:   ids.ErrInvalidID,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := r.Get(ctx, tt.articleID, tt.number)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Revision.Get() error = %v, want %v", err, tt.wantErr)
+			}
+			if got.Article.Title != tt.wantTitle {
+				t.Errorf("Revision.Get() title = %q, want %q", got.Article.Title, tt.wantTitle)
+			}
+			if err == nil && !got.Created.Equal(created) {
+				t.Errorf("Revision.Get() created = %v, want %v", got.Created, created)
+			}
+		})
+	}
+}
+
+func TestRevision_Restore(t *testing.T) {
+	t.Parallel()
+
+	r := NewRevision(WithRetention(3))
+	ctx := context.TODO()
+	id := ids.UniqueID()
+
+	for _, number := range []int{7, 5, 9, 6, 5} {
+		err := r.Restore(ctx, article.Revision{ArticleID: id, Number: number, Article: article.Article{ID: id}})
+		if err != nil {
+			t.Fatalf("Revision.Restore() error = %v", err)
+		}
+	}
+
+	revs, err := r.List(ctx, id)
+	if err != nil {
+		t.Fatalf("Revision.List() error = %v", err)
+	}
+	var numbers []int
+	for _, rev := range revs {
+		numbers = append(numbers, rev.Number)
+	}
+	if want := []int{6, 7, 9}; !reflect.DeepEqual(numbers, want) {
+		t.Errorf("numbers = %v, want %v", numbers, want)
+	}
+
+	// New revisions continue after the restored ones.
+	rev, err := r.Add(ctx, article.Revision{ArticleID: id, Article: article.Article{ID: id}})
+	if err != nil || rev.Number != 10 {
+		t.Errorf("Revision.Add() = %v, %v, want number 10", rev.Number, err)
+	}
+
+	if err := r.Restore(ctx, article.Revision{ArticleID: "invalid", Number: 1}); !errors.Is(err, ids.ErrInvalidID) {
+		t.Errorf("Revision.Restore() error = %v, want %v", err, ids.ErrInvalidID)
+	}
+}
@@ -0,0 +1,271 @@
+package inmem
+
+import (
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/Br0ce/articleDB/pkg/analysis"
+	"github.com/Br0ce/articleDB/pkg/article"
+	"github.com/Br0ce/articleDB/pkg/query"
+)
+
+// occurrence is an occurrence of a term in a field. The position is the index of
+// the term among the terms of the field, start and end are its byte offsets.
+type occurrence struct {
+	pos   int
+	start int
+	end   int
+}
+
+// postings maps the IDs of the articles containing a term to the occurrences of
+// the term in the field ordered by position.
+type postings map[string][]occurrence
+
+// textIndex is an inverted index of the text fields of the articles. The fields of
+// an article are analyzed in its language, so the index holds the terms of every
+// language apart.
+type textIndex map[article.Language]map[query.TextField]map[string]postings
+
+var indexedFields = []query.TextField{query.TextTitle, query.TextBody, query.TextSummary, query.TextAuthor}
+
+func fieldText(ar article.Article, field query.TextField) string {
+	switch field {
+	case query.TextTitle:
+		return ar.Title
+	case query.TextBody:
+		return ar.Body
+	case query.TextSummary:
+		return ar.Summary
+	case query.TextAuthor:
+		return ar.Author
+	default:
+		return ""
+	}
+}
+
+func (x textIndex) add(ar article.Article) {
+	an := analysis.For(ar.Language)
+	fields, ok := x[an.Language()]
+	if !ok {
+		fields = make(map[query.TextField]map[string]postings)
+		x[an.Language()] = fields
+	}
+
+	for _, field := range indexedFields {
+		terms, ok := fields[field]
+		if !ok {
+			terms = make(map[string]postings)
+			fields[field] = terms
+		}
+		for _, token := range an.Analyze(fieldText(ar, field)) {
+			p, ok := terms[token.Term]
+			if !ok {
+				p = make(postings)
+				terms[token.Term] = p
+			}
+			p[ar.ID] = append(p[ar.ID], occurrence{pos: token.Pos, start: token.Start, end: token.End})
+		}
+	}
+}
+
+func (x textIndex) remove(ar article.Article) {
+	an := analysis.For(ar.Language)
+	fields := x[an.Language()]
+	for _, field := range indexedFields {
+		terms := fields[field]
+		for _, token := range an.Analyze(fieldText(ar, field)) {
+			p, ok := terms[token.Term]
+			if !ok {
+				continue
+			}
+			delete(p, ar.ID)
+			if len(p) == 0 {
+				delete(terms, token.Term)
+			}
+		}
+	}
+}
+
+// phrase returns the articles containing the words consecutively in the field.
+// TextAny matches the title and the body. The words are analyzed in the language of
+// the articles, so stopwords are skipped and other forms of the words match. A
+// distance above 0 matches terms within the edit distance of the analyzed words
+// too, see analysis.Fuzziness.
+func (x textIndex) phrase(field query.TextField, words []string, distance int) query.Set {
+	if field == query.TextAny {
+		result := x.phrase(query.TextTitle, words, distance)
+		for id := range x.phrase(query.TextBody, words, distance) {
+			result[id] = struct{}{}
+		}
+		return result
+	}
+
+	result := make(query.Set)
+	for lang, fields := range x {
+		tokens := analysis.For(lang).Analyze(strings.Join(words, " "))
+		if len(tokens) == 0 {
+			continue
+		}
+		for id := range phrase(fields[field], tokens, distance) {
+			result[id] = struct{}{}
+		}
+	}
+	return result
+}
+
+// phrase returns the articles containing the tokens in the terms of a field. The
+// tokens keep the gaps of their positions, e.g. of dropped stopwords.
+func phrase(terms map[string]postings, tokens []analysis.Token, distance int) query.Set {
+	result := make(query.Set)
+	lists := make([][]postings, 0, len(tokens))
+	for _, token := range tokens {
+		variants := fuzzy(terms, token.Term, distance)
+		if len(variants) == 0 {
+			return result
+		}
+		lists = append(lists, variants)
+	}
+
+	for _, p := range lists[0] {
+		for id, occurrences := range p {
+			if _, ok := result[id]; ok {
+				continue
+			}
+			for _, o := range occurrences {
+				if followedBy(lists[1:], tokens[1:], id, o.pos-tokens[0].Pos) {
+					result[id] = struct{}{}
+					break
+				}
+			}
+		}
+	}
+	return result
+}
+
+// fuzzy returns the postings of the term and of the terms within its edit
+// distance. The distance is limited by analysis.Fuzziness, a distance of 0 only
+// looks up the term itself.
+func fuzzy(terms map[string]postings, term string, distance int) []postings {
+	distance = analysis.Fuzziness(term, distance)
+	if distance == 0 {
+		if p, ok := terms[term]; ok {
+			return []postings{p}
+		}
+		return nil
+	}
+
+	var variants []postings
+	for other, p := range terms {
+		if analysis.EditDistance(term, other, distance) <= distance {
+			variants = append(variants, p)
+		}
+	}
+	return variants
+}
+
+// followedBy reports whether any term of each of the lists is at the position of
+// the token relative to the position base in the article.
+func followedBy(lists [][]postings, tokens []analysis.Token, id string, base int) bool {
+	for i, variants := range lists {
+		want := base + tokens[i].Pos
+		if !slices.ContainsFunc(variants, func(p postings) bool { return at(p[id], want) }) {
+			return false
+		}
+	}
+	return true
+}
+
+// at reports whether an occurrence is at the position.
+func at(occurrences []occurrence, pos int) bool {
+	j := sort.Search(len(occurrences), func(j int) bool { return occurrences[j].pos >= pos })
+	return j < len(occurrences) && occurrences[j].pos == pos
+}
+
+// matches returns the offsets of the terms of the field in the article ordered by
+// offset. The terms are analyzed in the language of the article and match within
+// their distance.
+func (x textIndex) matches(ar article.Article, field query.TextField, terms []query.Term) []query.Match {
+	an := analysis.For(ar.Language)
+	index := x[an.Language()][field]
+
+	var matches []query.Match
+	for _, term := range terms {
+		if !term.In(field) {
+			continue
+		}
+		for _, token := range an.Analyze(term.Value) {
+			for _, p := range fuzzy(index, token.Term, term.Distance) {
+				for _, o := range p[ar.ID] {
+					matches = append(matches, query.Match{Start: o.start, End: o.end})
+				}
+			}
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].Start < matches[j].Start
+	})
+	return slices.Compact(matches)
+}
+
+type entityKey struct {
+	typ  article.EntityType
+	name string
+}
+
+// entityIndex maps the named entities to the articles mentioning them. Names are
+// lowercased, the empty type indexes entities of any type.
+type entityIndex map[entityKey]query.Set
+
+func (x entityIndex) keys(ar article.Article) []entityKey {
+	keys := make([]entityKey, 0, 2*len(ar.NER.Entities))
+	for _, e := range ar.NER.Entities {
+		name := strings.ToLower(strings.TrimSpace(e.Name))
+		keys = append(keys, entityKey{typ: e.Type, name: name}, entityKey{name: name})
+	}
+	return keys
+}
+
+func (x entityIndex) add(ar article.Article) {
+	for _, key := range x.keys(ar) {
+		ids, ok := x[key]
+		if !ok {
+			ids = make(query.Set)
+			x[key] = ids
+		}
+		ids[ar.ID] = struct{}{}
+	}
+}
+
+func (x entityIndex) remove(ar article.Article) {
+	for _, key := range x.keys(ar) {
+		delete(x[key], ar.ID)
+		if len(x[key]) == 0 {
+			delete(x, key)
+		}
+	}
+}
+
+// lookup returns the articles mentioning the entity. A distance above 0 matches
+// names within the edit distance too, see analysis.Fuzziness.
+func (x entityIndex) lookup(t article.EntityType, name string, distance int) query.Set {
+	name = strings.ToLower(strings.TrimSpace(name))
+	distance = analysis.Fuzziness(name, distance)
+
+	result := make(query.Set)
+	if distance == 0 {
+		for id := range x[entityKey{typ: t, name: name}] {
+			result[id] = struct{}{}
+		}
+		return result
+	}
+	for key, ids := range x {
+		if key.typ != t || analysis.EditDistance(key.name, name, distance) > distance {
+			continue
+		}
+		for id := range ids {
+			result[id] = struct{}{}
+		}
+	}
+	return result
+}
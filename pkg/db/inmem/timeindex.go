@@ -0,0 +1,108 @@
+package inmem
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+type timeEntry struct {
+	t  time.Time
+	id string
+}
+
+func (e timeEntry) less(o timeEntry) bool {
+	if !e.t.Equal(o.t) {
+		return e.t.Before(o.t)
+	}
+	return e.id < o.id
+}
+
+// timeIndex holds the IDs of the articles ordered by a time. Articles with the
+// same time are ordered by ID. Entries inserted out of order are kept apart and
+// merged into the ordered ones before the next scan or remove, so adding many
+// articles does not shift the index for every article.
+type timeIndex struct {
+	entries []timeEntry
+	pending []timeEntry
+	// mu guards the merge, as scans run under the read lock of the store.
+	mu sync.Mutex
+}
+
+func (x *timeIndex) insert(t time.Time, id string) {
+	e := timeEntry{t: t, id: id}
+	if len(x.pending) == 0 && (len(x.entries) == 0 || !e.less(x.entries[len(x.entries)-1])) {
+		x.entries = append(x.entries, e)
+		return
+	}
+	x.pending = append(x.pending, e)
+}
+
+func (x *timeIndex) remove(t time.Time, id string) {
+	x.merge()
+
+	e := timeEntry{t: t, id: id}
+	i := sort.Search(len(x.entries), func(i int) bool { return !x.entries[i].less(e) })
+	if i < len(x.entries) && x.entries[i].id == id && x.entries[i].t.Equal(t) {
+		x.entries = append(x.entries[:i], x.entries[i+1:]...)
+	}
+}
+
+// merge sorts the pending entries and merges them into the ordered ones.
+func (x *timeIndex) merge() {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	if len(x.pending) == 0 {
+		return
+	}
+	sort.Slice(x.pending, func(i, j int) bool { return x.pending[i].less(x.pending[j]) })
+
+	merged := make([]timeEntry, 0, len(x.entries)+len(x.pending))
+	i, j := 0, 0
+	for i < len(x.entries) && j < len(x.pending) {
+		if x.pending[j].less(x.entries[i]) {
+			merged = append(merged, x.pending[j])
+			j++
+		} else {
+			merged = append(merged, x.entries[i])
+			i++
+		}
+	}
+	merged = append(merged, x.entries[i:]...)
+	merged = append(merged, x.pending[j:]...)
+
+	x.entries = merged
+	x.pending = nil
+}
+
+// scan calls fn with the IDs of the times in [from, to) in ascending or descending
+// order, until fn returns false. A zero from or to leaves the range open on that
+// side.
+func (x *timeIndex) scan(from, to time.Time, desc bool, fn func(id string) bool) {
+	x.merge()
+
+	entries := x.entries
+	lo := 0
+	if !from.IsZero() {
+		lo = sort.Search(len(entries), func(i int) bool { return !entries[i].t.Before(from) })
+	}
+	hi := len(entries)
+	if !to.IsZero() {
+		hi = sort.Search(len(entries), func(i int) bool { return !entries[i].t.Before(to) })
+	}
+
+	if desc {
+		for i := hi - 1; i >= lo; i-- {
+			if !fn(entries[i].id) {
+				return
+			}
+		}
+		return
+	}
+	for i := lo; i < hi; i++ {
+		if !fn(entries[i].id) {
+			return
+		}
+	}
+}
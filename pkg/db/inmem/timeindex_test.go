@@ -0,0 +1,86 @@
+package inmem
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestTimeIndex_scan(t *testing.T) {
+	t.Parallel()
+
+	day := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	var x timeIndex
+	x.insert(day.AddDate(0, 0, 2), "c")
+	x.insert(day.AddDate(0, 0, 3), "d")
+	x.insert(day, "a")
+	x.insert(day.AddDate(0, 0, 2), "b")
+	x.insert(day.AddDate(0, 0, 1), "x")
+	x.remove(day.AddDate(0, 0, 1), "x")
+	x.insert(day.AddDate(0, 0, 1), "e")
+
+	tests := []struct {
+		name string
+		from time.Time
+		to   time.Time
+		desc bool
+		want []string
+	}{
+		{
+			name: "all",
+			want: []string{"a", "e", "b", "c", "d"},
+		},
+		{
+			name: "descending",
+			desc: true,
+			want: []string{"d", "c", "b", "e", "a"},
+		},
+		{
+			name: "range",
+			from: day.AddDate(0, 0, 1),
+			to:   day.AddDate(0, 0, 3),
+			want: []string{"e", "b", "c"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			x.scan(tt.from, tt.to, tt.desc, func(id string) bool {
+				got = append(got, id)
+				return true
+			})
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("timeIndex.scan() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTimeIndex_scan_concurrent(t *testing.T) {
+	t.Parallel()
+
+	day := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	var x timeIndex
+	for i := 100; i > 0; i-- {
+		x.insert(day.Add(time.Duration(i)*time.Minute), "id")
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			n := 0
+			x.scan(time.Time{}, time.Time{}, false, func(id string) bool {
+				n++
+				return true
+			})
+			if n != 100 {
+				t.Errorf("timeIndex.scan() found %v entries, want 100", n)
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,183 @@
+// Package dedup finds near-duplicate articles, e.g. wire stories republished with
+// small edits by different outlets, by comparing the fingerprints of their bodies.
+package dedup
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/Br0ce/articleDB/pkg/article"
+	"github.com/Br0ce/articleDB/pkg/simhash"
+)
+
+var ErrInvalidThreshold = errors.New("threshold must be in (0, 1]")
+
+// Lister lists all stored articles.
+type Lister interface {
+	List(ctx context.Context) ([]article.Article, error)
+}
+
+// Match is an article similar to a given fingerprint.
+type Match struct {
+	Article article.Article
+	// Similarity is in the range [0, 1], where 1 means identical fingerprints.
+	Similarity float64
+}
+
+// Cluster groups near-duplicate articles.
+type Cluster struct {
+	// Canonical is the ID of the original article of the cluster, which is the
+	// earliest published one.
+	Canonical string
+	// IDs holds the IDs of all articles of the cluster including the canonical one.
+	IDs []string
+}
+
+// Detector finds near-duplicates among the stored articles.
+type Detector struct {
+	db Lister
+}
+
+// New returns a Detector, that searches the articles listed by db.
+func New(db Lister) *Detector {
+	return &Detector{db: db}
+}
+
+// NearDuplicates returns all articles with a similarity of at least threshold to
+// the given fingerprint, ordered by decreasing similarity. Articles without a
+// fingerprint are ignored.
+func (d *Detector) NearDuplicates(ctx context.Context, fingerprint uint64, threshold float64) ([]Match, error) {
+	if threshold <= 0 || threshold > 1 {
+		return nil, ErrInvalidThreshold
+	}
+
+	items, err := d.db.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []Match
+	for _, item := range items {
+		if item.Fingerprint == 0 {
+			continue
+		}
+
+		sim := simhash.Similarity(fingerprint, item.Fingerprint)
+		if sim >= threshold {
+			matches = append(matches, Match{Article: item, Similarity: sim})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Similarity > matches[j].Similarity
+	})
+
+	return matches, nil
+}
+
+// Canonical returns the canonical article among the near-duplicates of the given
+// fingerprint. The bool is false, if there are no near-duplicates.
+func (d *Detector) Canonical(ctx context.Context, fingerprint uint64, threshold float64) (article.Article, bool, error) {
+	matches, err := d.NearDuplicates(ctx, fingerprint, threshold)
+	if err != nil {
+		return article.Article{}, false, err
+	}
+
+	if len(matches) == 0 {
+		return article.Article{}, false, nil
+	}
+
+	canonical := matches[0].Article
+	for _, m := range matches[1:] {
+		if earlier(m.Article, canonical) {
+			canonical = m.Article
+		}
+	}
+
+	return canonical, true, nil
+}
+
+// Clusters groups all stored articles, that are near-duplicates of each other with
+// a similarity of at least threshold. Similarity is treated as transitive, so two
+// articles can be in the same cluster although they are only similar through a third
+// one. Articles without near-duplicates are not part of any cluster.
+func (d *Detector) Clusters(ctx context.Context, threshold float64) ([]Cluster, error) {
+	if threshold <= 0 || threshold > 1 {
+		return nil, ErrInvalidThreshold
+	}
+
+	all, err := d.db.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []article.Article
+	for _, item := range all {
+		if item.Fingerprint != 0 {
+			items = append(items, item)
+		}
+	}
+
+	parent := make([]int, len(items))
+	for i := range parent {
+		parent[i] = i
+	}
+
+	var find func(i int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	for i := range items {
+		for j := i + 1; j < len(items); j++ {
+			if simhash.Similarity(items[i].Fingerprint, items[j].Fingerprint) >= threshold {
+				parent[find(i)] = find(j)
+			}
+		}
+	}
+
+	groups := make(map[int][]article.Article)
+	for i, item := range items {
+		root := find(i)
+		groups[root] = append(groups[root], item)
+	}
+
+	var clusters []Cluster
+	for _, group := range groups {
+		if len(group) < 2 {
+			continue
+		}
+
+		sort.Slice(group, func(i, j int) bool {
+			return earlier(group[i], group[j])
+		})
+
+		cluster := Cluster{Canonical: group[0].ID}
+		for _, item := range group {
+			cluster.IDs = append(cluster.IDs, item.ID)
+		}
+		clusters = append(clusters, cluster)
+	}
+
+	sort.Slice(clusters, func(i, j int) bool {
+		return clusters[i].Canonical < clusters[j].Canonical
+	})
+
+	return clusters, nil
+}
+
+// earlier reports whether a was published before b. Ties are broken by the
+// creation time and the ID.
+func earlier(a, b article.Article) bool {
+	if !a.Published.Equal(b.Published) {
+		return a.Published.Before(b.Published)
+	}
+	if !a.Created.Equal(b.Created) {
+		return a.Created.Before(b.Created)
+	}
+	return a.ID < b.ID
+}
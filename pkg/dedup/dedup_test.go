@@ -0,0 +1,162 @@
+package dedup
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Br0ce/articleDB/pkg/article"
+	"github.com/Br0ce/articleDB/pkg/mock"
+)
+
+func testArticles() []article.Article {
+	published := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	return []article.Article{
+		{ID: "a", Fingerprint: 0b0000, Published: published},
+		{ID: "b", Fingerprint: 0b1111_0000, Published: published.Add(-time.Hour)},
+		{ID: "c", Fingerprint: 0b1111_0001, Published: published},
+		{ID: "d", Fingerprint: 0b1111_0011, Published: published.Add(time.Hour)},
+		{ID: "e", Fingerprint: ^uint64(0), Published: published},
+	}
+}
+
+func TestDetector_NearDuplicates(t *testing.T) {
+	t.Parallel()
+
+	db := &mock.DB{ListFn: func(ctx context.Context) ([]article.Article, error) {
+		return testArticles(), nil
+	}}
+
+	tests := []struct {
+		name        string
+		fingerprint uint64
+		threshold   float64
+		want        []string
+		wantErr     bool
+	}{
+		{
+			name:        "exact",
+			fingerprint: 0b1111_0001,
+			threshold:   1,
+			want:        []string{"c"},
+		},
+		{
+			name:        "near",
+			fingerprint: 0b1111_0001,
+			threshold:   1 - 1.0/64,
+			want:        []string{"c", "b", "d"},
+		},
+		{
+			name:        "none",
+			fingerprint: 0b1010_1010_1010,
+			threshold:   1,
+		},
+		{
+			name:        "invalid threshold",
+			fingerprint: 0b1111_0001,
+			threshold:   0,
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := New(db)
+
+			got, err := d.NearDuplicates(context.TODO(), tt.fingerprint, tt.threshold)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Detector.NearDuplicates() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			var gotIDs []string
+			for _, m := range got {
+				gotIDs = append(gotIDs, m.Article.ID)
+			}
+			if !reflect.DeepEqual(gotIDs, tt.want) {
+				t.Errorf("Detector.NearDuplicates() = %v, want %v", gotIDs, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetector_Canonical(t *testing.T) {
+	t.Parallel()
+
+	db := &mock.DB{ListFn: func(ctx context.Context) ([]article.Article, error) {
+		return testArticles(), nil
+	}}
+	d := New(db)
+
+	got, ok, err := d.Canonical(context.TODO(), 0b1111_0001, 1-1.0/64)
+	if err != nil {
+		t.Fatalf("Detector.Canonical() error = %v", err)
+	}
+	if !ok {
+		t.Fatal("Detector.Canonical() found no canonical article")
+	}
+	if got.ID != "b" {
+		t.Errorf("Detector.Canonical() = %v, want b", got.ID)
+	}
+
+	_, ok, err = d.Canonical(context.TODO(), 0b1010_1010_1010, 1)
+	if err != nil {
+		t.Fatalf("Detector.Canonical() error = %v", err)
+	}
+	if ok {
+		t.Error("Detector.Canonical() found canonical article without near-duplicates")
+	}
+}
+
+func TestDetector_Clusters(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		listFn    func(ctx context.Context) ([]article.Article, error)
+		threshold float64
+		want      []Cluster
+		wantErr   bool
+	}{
+		{
+			name: "transitive cluster",
+			listFn: func(ctx context.Context) ([]article.Article, error) {
+				return testArticles(), nil
+			},
+			threshold: 1 - 1.0/64,
+			want:      []Cluster{{Canonical: "b", IDs: []string{"b", "c", "d"}}},
+		},
+		{
+			name: "no clusters",
+			listFn: func(ctx context.Context) ([]article.Article, error) {
+				return testArticles(), nil
+			},
+			threshold: 1,
+		},
+		{
+			name: "db error",
+			listFn: func(ctx context.Context) ([]article.Article, error) {
+				return nil, errors.New("db error")
+			},
+			threshold: 1,
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := New(&mock.DB{ListFn: tt.listFn})
+
+			got, err := d.Clusters(context.TODO(), tt.threshold)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Detector.Clusters() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Detector.Clusters() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,266 @@
+package encoding
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/Br0ce/articleDB/pkg/article"
+)
+
+// ArticleVersion is the version of the wire schema of an article. It is increased,
+// whenever a field is removed or its meaning changes. Adding a field does not
+// change the version.
+const ArticleVersion = 1
+
+var (
+	ErrUnsupportedVersion = errors.New("unsupported schema version")
+	ErrInvalidArticle     = errors.New("invalid article")
+)
+
+// Article is the external representation of an article.Article, e.g. in api
+// responses and archives. Fields use snake_case, the url is a string, times are
+// RFC3339 strings in UTC and are omitted, if not set.
+type Article struct {
+	Version     int       `json:"version"`
+	ID          string    `json:"id,omitempty"`
+	Title       string    `json:"title"`
+	URL         string    `json:"url"`
+	Author      string    `json:"author,omitempty"`
+	PublishedAt string    `json:"published_at,omitempty"`
+	CreatedAt   string    `json:"created_at,omitempty"`
+	UpdatedAt   string    `json:"updated_at,omitempty"`
+	Body        string    `json:"body"`
+	Language    string    `json:"language,omitempty"`
+	Summary     string    `json:"summary,omitempty"`
+	Keywords    []string  `json:"keywords,omitempty"`
+	NER         NER       `json:"ner"`
+	Fingerprint string    `json:"fingerprint,omitempty"`
+	Embedding   []float32 `json:"embedding,omitempty"`
+	Source      *Source   `json:"source,omitempty"`
+}
+
+// NER is the external representation of an article.NER. The name lists are views
+// of the entities by type. If an article is read without entities, the entities
+// are built from the name lists.
+type NER struct {
+	Entities      []Entity `json:"entities"`
+	Persons       []string `json:"persons"`
+	Locations     []string `json:"locations"`
+	Organisations []string `json:"organisations"`
+}
+
+// Entity is the external representation of an article.Entity.
+type Entity struct {
+	Name       string    `json:"name"`
+	Type       string    `json:"type"`
+	Mentions   []Mention `json:"mentions,omitempty"`
+	Count      int       `json:"count"`
+	Confidence float64   `json:"confidence,omitempty"`
+}
+
+// Mention is the external representation of an article.Mention.
+type Mention struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// Source is the external representation of an article.Source.
+type Source struct {
+	Kind       string `json:"kind"`
+	ID         string `json:"id,omitempty"`
+	CapturedAt string `json:"captured_at,omitempty"`
+}
+
+// FromArticle returns the external representation of ar. The fingerprint is
+// written as hex string, because json numbers can not hold every uint64 exactly.
+func FromArticle(ar article.Article) Article {
+	w := Article{
+		Version:     ArticleVersion,
+		ID:          ar.ID,
+		Title:       ar.Title,
+		URL:         ar.Addr.String(),
+		Author:      ar.Author,
+		PublishedAt: formatTime(ar.Published),
+		CreatedAt:   formatTime(ar.Created),
+		UpdatedAt:   formatTime(ar.Updated),
+		Body:        ar.Body,
+		Language:    string(ar.Language),
+		Summary:     ar.Summary,
+		Keywords:    ar.Keywords,
+		NER:         fromNER(ar.NER),
+		Embedding:   ar.Embedding,
+	}
+
+	if ar.Fingerprint != 0 {
+		w.Fingerprint = strconv.FormatUint(ar.Fingerprint, 16)
+	}
+	if ar.Source != (article.Source{}) {
+		w.Source = &Source{
+			Kind:       ar.Source.Kind,
+			ID:         ar.Source.ID,
+			CapturedAt: formatTime(ar.Source.Captured),
+		}
+	}
+
+	return w
+}
+
+// ToArticle returns the article.Article represented by a. A missing version is
+// read as the current version, newer versions are rejected with
+// ErrUnsupportedVersion.
+func (a Article) ToArticle() (article.Article, error) {
+	if a.Version < 0 || a.Version > ArticleVersion {
+		return article.Article{}, fmt.Errorf("version %d, %w", a.Version, ErrUnsupportedVersion)
+	}
+
+	addr, err := url.Parse(a.URL)
+	if err != nil {
+		return article.Article{}, fmt.Errorf("url %q, %w", a.URL, ErrInvalidArticle)
+	}
+
+	ar := article.Article{
+		ID:        a.ID,
+		Title:     a.Title,
+		Addr:      *addr,
+		Author:    a.Author,
+		Body:      a.Body,
+		Language:  article.Language(a.Language),
+		Summary:   a.Summary,
+		Keywords:  a.Keywords,
+		NER:       a.NER.toNER(a.Body),
+		Embedding: a.Embedding,
+	}
+
+	times := []struct {
+		name  string
+		value string
+		dst   *time.Time
+	}{
+		{name: "published_at", value: a.PublishedAt, dst: &ar.Published},
+		{name: "created_at", value: a.CreatedAt, dst: &ar.Created},
+		{name: "updated_at", value: a.UpdatedAt, dst: &ar.Updated},
+	}
+	for _, t := range times {
+		*t.dst, err = parseTime(t.name, t.value)
+		if err != nil {
+			return article.Article{}, err
+		}
+	}
+
+	if a.Fingerprint != "" {
+		ar.Fingerprint, err = strconv.ParseUint(a.Fingerprint, 16, 64)
+		if err != nil {
+			return article.Article{}, fmt.Errorf("fingerprint %q, %w", a.Fingerprint, ErrInvalidArticle)
+		}
+	}
+
+	if a.Source != nil {
+		captured, err := parseTime("source.captured_at", a.Source.CapturedAt)
+		if err != nil {
+			return article.Article{}, err
+		}
+		ar.Source = article.Source{Kind: a.Source.Kind, ID: a.Source.ID, Captured: captured}
+	}
+
+	return ar, nil
+}
+
+func fromNER(ner article.NER) NER {
+	w := NER{
+		Entities:      make([]Entity, 0, len(ner.Entities)),
+		Persons:       nonNil(ner.Pers()),
+		Locations:     nonNil(ner.Locs()),
+		Organisations: nonNil(ner.Orgs()),
+	}
+
+	for _, e := range ner.Entities {
+		var mentions []Mention
+		for _, m := range e.Mentions {
+			mentions = append(mentions, Mention{Start: m.Start, End: m.End})
+		}
+		w.Entities = append(w.Entities, Entity{
+			Name:       e.Name,
+			Type:       string(e.Type),
+			Mentions:   mentions,
+			Count:      e.Count,
+			Confidence: e.Confidence,
+		})
+	}
+
+	return w
+}
+
+// toNER returns the article.NER represented by n. Without entities, the entities
+// are built from the name lists and located in body.
+func (n NER) toNER(body string) article.NER {
+	if len(n.Entities) == 0 {
+		var entities []article.Entity
+		entities = append(entities, article.EntitiesOf(article.Person, n.Persons...)...)
+		entities = append(entities, article.EntitiesOf(article.Location, n.Locations...)...)
+		entities = append(entities, article.EntitiesOf(article.Organisation, n.Organisations...)...)
+		return article.NewNER(body, entities...)
+	}
+
+	ner := article.NER{Entities: make([]article.Entity, 0, len(n.Entities))}
+	for _, e := range n.Entities {
+		var mentions []article.Mention
+		for _, m := range e.Mentions {
+			mentions = append(mentions, article.Mention{Start: m.Start, End: m.End})
+		}
+		ner.Entities = append(ner.Entities, article.Entity{
+			Name:       e.Name,
+			Type:       article.EntityType(e.Type),
+			Mentions:   mentions,
+			Count:      e.Count,
+			Confidence: e.Confidence,
+		})
+	}
+
+	return ner
+}
+
+// MarshalArticle encodes ar in the wire schema.
+func MarshalArticle(ar article.Article) ([]byte, error) {
+	return json.Marshal(FromArticle(ar))
+}
+
+// UnmarshalArticle decodes an article in the wire schema.
+func UnmarshalArticle(data []byte) (article.Article, error) {
+	var a Article
+	err := json.Unmarshal(data, &a)
+	if err != nil {
+		return article.Article{}, fmt.Errorf("%s, %w", err.Error(), ErrInvalidArticle)
+	}
+	return a.ToArticle()
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+func parseTime(name, value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s %q, %w", name, value, ErrInvalidArticle)
+	}
+	return t.UTC(), nil
+}
+
+// nonNil returns an empty slice for nil, so the entity lists are written as empty
+// json arrays instead of null.
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
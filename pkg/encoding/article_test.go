@@ -0,0 +1,177 @@
+package encoding
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"net/url"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Br0ce/articleDB/pkg/article"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+func testArticle() article.Article {
+	published := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+	return article.Article{
+		ID:        "6f1c2d3e-4b5a-4c6d-8e7f-9a0b1c2d3e4f",
+		Title:     "Energy prices keep rising",
+		Addr:      url.URL{Scheme: "https", Host: "news.example.com", Path: "/energy-prices", RawQuery: "page=2"},
+		Author:    "Jane Doe",
+		Created:   published.Add(90 * time.Minute),
+		Updated:   published.Add(2*time.Hour + 500*time.Millisecond),
+		Published: published,
+		Body:      "Gas and electricity prices rose again.",
+		Language:  article.English,
+		Summary:   "Prices rose.",
+		Keywords:  []string{"energy", "prices"},
+		NER: article.NER{Entities: []article.Entity{
+			{Name: "Jane Doe", Type: article.Person, Count: 1, Confidence: 0.9},
+			{Name: "Berlin", Type: article.Location, Count: 1},
+			{Name: "EU", Type: article.Organisation, Count: 1},
+		}},
+		Fingerprint: 0xfedcba9876543210,
+		Embedding:   []float32{0.6, 0.8},
+		Source: article.Source{
+			Kind:     "warc",
+			ID:       "urn:uuid:6a1b0c4e-0000-4000-8000-000000000003",
+			Captured: published.Add(-time.Hour),
+		},
+	}
+}
+
+// golden compares got with the golden file. With -update the golden file is written.
+func golden(t *testing.T, file string, got []byte) {
+	if *update {
+		if err := os.WriteFile(file, got, 0o644); err != nil {
+			t.Fatalf("could not update golden file, %s", err.Error())
+		}
+	}
+
+	want, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("could not read golden file, %s", err.Error())
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestFromArticle_golden(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		ar   article.Article
+		file string
+	}{
+		{
+			name: "full",
+			ar:   testArticle(),
+			file: "testdata/article.golden.json",
+		},
+		{
+			name: "minimal",
+			ar: article.Article{
+				Title: "Storm warning",
+				Addr:  url.URL{Scheme: "https", Host: "weather.example.com", Path: "/storm"},
+				Body:  "Heavy rain is expected.",
+			},
+			file: "testdata/article_minimal.golden.json",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.MarshalIndent(FromArticle(tt.ar), "", "  ")
+			if err != nil {
+				t.Fatalf("could not marshal article, %s", err.Error())
+			}
+			golden(t, tt.file, append(got, '\n'))
+
+			back, err := UnmarshalArticle(got)
+			if err != nil {
+				t.Fatalf("UnmarshalArticle() error = %v", err)
+			}
+			if !reflect.DeepEqual(back, tt.ar) {
+				t.Errorf("UnmarshalArticle() = %+v, want %+v", back, tt.ar)
+			}
+		})
+	}
+}
+
+func TestUnmarshalArticle(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		data    string
+		want    article.Article
+		wantErr error
+	}{
+		{
+			name: "no version",
+			data: `{"title": "Storm", "url": "https://weather.example.com/storm", "published_at": "2023-06-02T10:30:00+02:00"}`,
+			want: article.Article{
+				Title:     "Storm",
+				Addr:      url.URL{Scheme: "https", Host: "weather.example.com", Path: "/storm"},
+				Published: time.Date(2023, 6, 2, 8, 30, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "names without entities",
+			data: `{"title": "Storm", "url": "https://weather.example.com/storm", "body": "Rain in Berlin and Berlin-Mitte.", "ner": {"locations": ["Berlin"], "persons": ["Jane Doe"]}}`,
+			want: article.Article{
+				Title: "Storm",
+				Addr:  url.URL{Scheme: "https", Host: "weather.example.com", Path: "/storm"},
+				Body:  "Rain in Berlin and Berlin-Mitte.",
+				NER: article.NER{Entities: []article.Entity{
+					{Name: "Jane Doe", Type: article.Person, Count: 1},
+					{Name: "Berlin", Type: article.Location, Mentions: []article.Mention{{Start: 8, End: 14}, {Start: 19, End: 25}}, Count: 2},
+				}},
+			},
+		},
+		{
+			name:    "newer version",
+			data:    `{"version": 2, "title": "Storm"}`,
+			wantErr: ErrUnsupportedVersion,
+		},
+		{
+			name:    "invalid time",
+			data:    `{"title": "Storm", "published_at": "2023-06-02"}`,
+			wantErr: ErrInvalidArticle,
+		},
+		{
+			name:    "invalid url",
+			data:    `{"title": "Storm", "url": "https://[::1"}`,
+			wantErr: ErrInvalidArticle,
+		},
+		{
+			name:    "invalid fingerprint",
+			data:    `{"title": "Storm", "fingerprint": "xyz"}`,
+			wantErr: ErrInvalidArticle,
+		},
+		{
+			name:    "url as object",
+			data:    `{"title": "Storm", "url": {"Host": "weather.example.com"}}`,
+			wantErr: ErrInvalidArticle,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := UnmarshalArticle([]byte(tt.data))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("UnmarshalArticle() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("UnmarshalArticle() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
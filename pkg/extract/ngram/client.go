@@ -0,0 +1,67 @@
+// Package ngram provides a deterministic embedder, that runs locally without any
+// external service. Words and character trigrams of the text are hashed into a
+// fixed number of dimensions.
+package ngram
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"strings"
+	"unicode"
+
+	"github.com/Br0ce/articleDB/pkg/vector"
+)
+
+// DefaultDim is the dimension of the embeddings, if no other is configured.
+const DefaultDim = 256
+
+type Client struct {
+	dim int
+}
+
+// NewClient returns a Client, that creates embeddings of dimension dim.
+// A dim smaller than one results in DefaultDim.
+func NewClient(dim int) *Client {
+	if dim < 1 {
+		dim = DefaultDim
+	}
+	return &Client{dim: dim}
+}
+
+// Embed returns the normalized embedding of the given text. Equal texts always
+// result in equal embeddings.
+func (c *Client) Embed(ctx context.Context, text string) ([]float32, error) {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	if len(words) == 0 {
+		return nil, errors.New("could not embed, text is empty")
+	}
+
+	vec := make([]float32, c.dim)
+	for _, word := range words {
+		c.add(vec, "w:"+word, 1)
+
+		runes := []rune("^" + word + "$")
+		for i := 0; i+3 <= len(runes); i++ {
+			c.add(vec, "t:"+string(runes[i:i+3]), 0.5)
+		}
+	}
+
+	return vector.Normalize(vec), nil
+}
+
+// add hashes the feature into a dimension of vec and adds the weight. One bit of
+// the hash decides the sign, so that collisions cancel out on average.
+func (c *Client) add(vec []float32, feature string, weight float32) {
+	h := fnv.New64a()
+	// Writing to a hash never returns an error.
+	_, _ = h.Write([]byte(feature))
+	sum := h.Sum64()
+
+	if sum&(1<<63) != 0 {
+		weight = -weight
+	}
+	vec[sum%uint64(c.dim)] += weight
+}
@@ -0,0 +1,49 @@
+package ngram
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/Br0ce/articleDB/pkg/vector"
+)
+
+func TestClient_Embed(t *testing.T) {
+	t.Parallel()
+
+	c := NewClient(0)
+	ctx := context.TODO()
+
+	text := "The government announced new measures against the energy crisis."
+	got, err := c.Embed(ctx, text)
+	if err != nil {
+		t.Fatalf("Client.Embed() error = %v", err)
+	}
+	if len(got) != DefaultDim {
+		t.Fatalf("Client.Embed() dim = %v, want %v", len(got), DefaultDim)
+	}
+
+	again, err := c.Embed(ctx, text)
+	if err != nil {
+		t.Fatalf("Client.Embed() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, again) {
+		t.Error("Client.Embed() is not deterministic")
+	}
+
+	similar, err := c.Embed(ctx, "New government measures announced against the energy crisis.")
+	if err != nil {
+		t.Fatalf("Client.Embed() error = %v", err)
+	}
+	other, err := c.Embed(ctx, "Football club wins the championship after penalty shootout.")
+	if err != nil {
+		t.Fatalf("Client.Embed() error = %v", err)
+	}
+	if vector.Cosine(got, similar) <= vector.Cosine(got, other) {
+		t.Error("similar text is not closer than other text")
+	}
+
+	if _, err := c.Embed(ctx, " ... "); err == nil {
+		t.Error("Client.Embed() without error for empty text")
+	}
+}
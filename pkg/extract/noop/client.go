@@ -9,10 +9,14 @@ import (
 type Client struct {
 }
 
-func (c Client) Summarize(ctx context.Context, text string) (string, error) {
+func (c Client) Summarize(ctx context.Context, text string, lang article.Language) (string, error) {
 	return "", nil
 }
 
-func (c Client) NER(ctx context.Context, text string) (article.NER, error) {
+func (c Client) NER(ctx context.Context, text string, lang article.Language) (article.NER, error) {
 	return article.NER{}, nil
 }
+
+func (c Client) Embed(ctx context.Context, text string) ([]float32, error) {
+	return nil, nil
+}
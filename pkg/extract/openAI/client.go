@@ -7,6 +7,8 @@ import (
 	"io"
 	"log/slog"
 	"net/http"
+	"sort"
+	"strings"
 
 	"github.com/Br0ce/articleDB/pkg/article"
 	"github.com/Br0ce/articleDB/pkg/encoding"
@@ -19,9 +21,9 @@ var (
 )
 
 const (
-	gpt3TextModel = "text-davinci-003"
-	nerPrompt     = "List named entities with entity type person, type location and type organisation in the text. Return a json"
-	sumPrompt     = "Tl;dr"
+	gpt3TextModel  = "text-davinci-003"
+	embeddingModel = "text-embedding-ada-002"
+	sumPrompt      = "Tl;dr"
 )
 
 type completionDTO struct {
@@ -55,31 +57,68 @@ type usageDTO struct {
 	TotalTokens      int `json:"total_tokens"`
 }
 
-type nerDTO struct {
-	Person       []string `json:"Person"`
-	Location     []string `json:"Location"`
-	Organisation []string `json:"Organization"`
+type embeddingRequestDTO struct {
+	Model string `json:"model"`
+	Input string `json:"input"`
 }
 
+type embeddingResponseDTO struct {
+	Object string         `json:"object"`
+	Model  string         `json:"model"`
+	Data   []embeddingDTO `json:"data"`
+	Usage  usageDTO       `json:"usage"`
+}
+
+type embeddingDTO struct {
+	Object    string    `json:"object"`
+	Index     int       `json:"index"`
+	Embedding []float32 `json:"embedding"`
+}
+
+// nerDTO maps the labels of the entity types to the names of the found entities.
+type nerDTO map[string][]string
+
 type Client struct {
 	apiKey         string
 	completionAddr string
+	embeddingAddr  string
+	entityTypes    []article.TypeSpec
 	log            *slog.Logger
 }
 
-func NewClient(apiKey string, log *slog.Logger) *Client {
-	return &Client{
+type Option func(c *Client)
+
+// WithEntityTypes sets the entity types recognized by NER. Without it, the
+// article.DefaultTypes are recognized.
+func WithEntityTypes(types ...article.TypeSpec) Option {
+	return func(c *Client) {
+		c.entityTypes = types
+	}
+}
+
+func NewClient(apiKey string, log *slog.Logger, opts ...Option) *Client {
+	c := &Client{
 		apiKey:         apiKey,
 		completionAddr: "https://api.openai.com/v1/completions",
+		embeddingAddr:  "https://api.openai.com/v1/embeddings",
+		entityTypes:    article.DefaultTypes,
 		log:            log,
 	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
 }
 
-// Summarize uses the openAI api to perform a summarization of the given text.
-func (c *Client) Summarize(ctx context.Context, text string) (string, error) {
+// Summarize uses the openAI api to perform a summarization of the given text. With
+// a known language, the summary is written in that language.
+func (c *Client) Summarize(ctx context.Context, text string, lang article.Language) (string, error) {
 	c.log.Info("summarize text with openAI",
 		"method", "Summarize",
-		"lenText", len(text))
+		"lenText", len(text),
+		"lang", lang)
 
 	if text == "" {
 		return "", errors.New("could not summarize, text is empty")
@@ -87,7 +126,7 @@ func (c *Client) Summarize(ctx context.Context, text string) (string, error) {
 
 	dto := completionDTO{
 		Model:       gpt3TextModel,
-		Prompt:      fmt.Sprintf("%s\n\n%s", text, sumPrompt),
+		Prompt:      summaryPrompt(text, lang),
 		Temperature: 1,
 		MaxTokens:   220,
 		TopP:        1.0,
@@ -99,11 +138,13 @@ func (c *Client) Summarize(ctx context.Context, text string) (string, error) {
 }
 
 // NER uses the openAI api to perform named entity recognition of the given text.
-// The returned entity types are person, location and organisation.
-func (c *Client) NER(ctx context.Context, text string) (article.NER, error) {
+// The returned entity types are the configured entity types. A known language of
+// the text is named in the prompt.
+func (c *Client) NER(ctx context.Context, text string, lang article.Language) (article.NER, error) {
 	c.log.Info("perform named entity recognition with openAI",
 		"method", "NER",
-		"lenText", len(text))
+		"lenText", len(text),
+		"lang", lang)
 
 	if text == "" {
 		return article.NER{}, errors.New("could not perform ner, text is empty")
@@ -111,7 +152,7 @@ func (c *Client) NER(ctx context.Context, text string) (article.NER, error) {
 
 	dto := completionDTO{
 		Model:       gpt3TextModel,
-		Prompt:      fmt.Sprintf("%s:\n%s", nerPrompt, text),
+		Prompt:      fmt.Sprintf("%s\n\n%s", c.nerPrompt(lang), text),
 		Temperature: 1,
 		MaxTokens:   220,
 		TopP:        1.0,
@@ -124,7 +165,38 @@ func (c *Client) NER(ctx context.Context, text string) (article.NER, error) {
 		return article.NER{}, err
 	}
 
-	return c.toNER(result)
+	return c.toNER(result, text)
+}
+
+// Embed uses the openAI api to compute the embedding vector of the given text.
+func (c *Client) Embed(ctx context.Context, text string) ([]float32, error) {
+	c.log.Info("embed text with openAI",
+		"method", "Embed",
+		"lenText", len(text))
+
+	if text == "" {
+		return nil, errors.New("could not embed, text is empty")
+	}
+
+	payload, err := encoding.EncodeToReader(embeddingRequestDTO{
+		Model: embeddingModel,
+		Input: text,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var response embeddingResponseDTO
+	err = c.post(ctx, c.embeddingAddr, payload, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(response.Data) == 0 || len(response.Data[0].Embedding) == 0 {
+		return nil, ErrInvalidResponse
+	}
+
+	return response.Data[0].Embedding, nil
 }
 
 // process processes the request to openAI and returns the response as text.
@@ -152,15 +224,28 @@ func (c *Client) process(ctx context.Context, dto completionDTO) (string, error)
 	return result, nil
 }
 
-// httpRequest performs the acutal post request to the openAI api and returns a responseDTO.
+// httpRequest performs the acutal post request to the openAI completion api and
+// returns a responseDTO.
 // To timeout the httpRequest, use an appropriate context.
 // For now, there is no retrying or throttling performed.
 func (c *Client) httpRequest(ctx context.Context, payload io.Reader) (responseDTO, error) {
 	c.log.Debug("perform http request to openAI", "method", "httpRequest")
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.completionAddr, payload)
+	var dto responseDTO
+	err := c.post(ctx, c.completionAddr, payload, &dto)
+	if err != nil {
+		return responseDTO{}, err
+	}
+
+	return dto, nil
+}
+
+// post posts the payload to the given openAI addr and decodes the json response
+// into dto.
+func (c *Client) post(ctx context.Context, addr string, payload io.Reader, dto interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, addr, payload)
 	if err != nil {
-		return responseDTO{}, fmt.Errorf("%s, %w", err.Error(), ErrBadGateway)
+		return fmt.Errorf("%s, %w", err.Error(), ErrBadGateway)
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.apiKey))
@@ -168,26 +253,20 @@ func (c *Client) httpRequest(ctx context.Context, payload io.Reader) (responseDT
 	cl := http.Client{}
 	resp, err := cl.Do(req)
 	if err != nil {
-		return responseDTO{}, err
+		return err
 	}
 	defer resp.Body.Close()
 
 	c.log.Debug("response info",
-		"method", "request",
+		"method", "post",
 		"status", resp.Status,
 		"headers", resp.Header)
 
 	if resp.StatusCode >= 300 {
-		return responseDTO{}, ErrBadGateway
-	}
-
-	var dto responseDTO
-	err = encoding.DecodeJSON(resp.Body, &dto)
-	if err != nil {
-		return responseDTO{}, err
+		return ErrBadGateway
 	}
 
-	return dto, nil
+	return encoding.DecodeJSON(resp.Body, dto)
 }
 
 // resultText extracts the result text from the response and returns
@@ -218,10 +297,49 @@ func (c *Client) resultText(response responseDTO) (string, error) {
 	return text, nil
 }
 
+// summaryPrompt returns the prompt to summarize the text. With a known language, the
+// model is asked to answer in it.
+func summaryPrompt(text string, lang article.Language) string {
+	if lang == article.LanguageUnknown {
+		return fmt.Sprintf("%s\n\n%s", text, sumPrompt)
+	}
+	return fmt.Sprintf("%s\n\n%s in %s", text, sumPrompt, lang.Name())
+}
+
+// nerPrompt returns the prompt for the configured entity types. The model is asked
+// for a json object with the labels of the types as keys. With a known language,
+// the model is told the language of the text.
+func (c *Client) nerPrompt(lang article.Language) string {
+	var b strings.Builder
+	b.WriteString("List the named entities of the following types in the text.\n")
+	for _, spec := range c.types() {
+		if spec.Description == "" {
+			fmt.Fprintf(&b, "- %s\n", spec.Label)
+			continue
+		}
+		fmt.Fprintf(&b, "- %s: %s\n", spec.Label, spec.Description)
+	}
+	if lang != article.LanguageUnknown {
+		fmt.Fprintf(&b, "The text is written in %s. Write the names as they appear in the text.\n", lang.Name())
+	}
+	b.WriteString("Return a json object with the types as keys and the lists of entity names as values.\n\nText:")
+	return b.String()
+}
+
+// types returns the configured entity types or the article.DefaultTypes.
+func (c *Client) types() []article.TypeSpec {
+	if len(c.entityTypes) == 0 {
+		return article.DefaultTypes
+	}
+	return c.entityTypes
+}
+
 // toNER transforms the given text into an article.NER. The text is
 // expected to be the string respresentation of a JSON with can be unmarshalled
-// into an nerDTO.
-func (c *Client) toNER(text string) (article.NER, error) {
+// into an nerDTO. The keys are matched with the labels of the configured entity
+// types ignoring case, other keys are ignored. The mentions of the entities are
+// located in body.
+func (c *Client) toNER(text, body string) (article.NER, error) {
 	c.log.Debug("get namedEntities from result text", "method", "toNER")
 
 	if text == "" {
@@ -234,14 +352,24 @@ func (c *Client) toNER(text string) (article.NER, error) {
 		return article.NER{}, err
 	}
 
-	c.log.Debug("check unmarshal result text", "method", "toNER",
-		"Persons", ner.Person,
-		"Locations", ner.Location,
-		"Organisations", ner.Organisation)
+	c.log.Debug("check unmarshal result text", "method", "toNER", "ner", ner)
+
+	// The labels are sorted, so keys differing only in case, e.g. Person and
+	// person, always result in the same order of the entities.
+	labels := make([]string, 0, len(ner))
+	for label := range ner {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+
+	var entities []article.Entity
+	for _, spec := range c.types() {
+		for _, label := range labels {
+			if strings.EqualFold(label, spec.Label) {
+				entities = append(entities, article.EntitiesOf(spec.Type, ner[label]...)...)
+			}
+		}
+	}
 
-	return article.NER{
-		Pers: ner.Person,
-		Locs: ner.Location,
-		Orgs: ner.Organisation,
-	}, nil
+	return article.NewNER(body, entities...), nil
 }
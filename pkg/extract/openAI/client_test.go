@@ -26,6 +26,7 @@ func TestClient_Summarize(t *testing.T) {
 	type args struct {
 		ctx  context.Context
 		text string
+		lang article.Language
 	}
 
 	log := logger.NewTest(false)
@@ -34,11 +35,12 @@ func TestClient_Summarize(t *testing.T) {
 	response := "response"
 
 	tests := []struct {
-		name    string
-		fields  fields
-		args    args
-		want    string
-		wantErr bool
+		name       string
+		fields     fields
+		args       args
+		wantPrompt string
+		want       string
+		wantErr    bool
 	}{
 		{
 			name: "pass",
@@ -50,8 +52,24 @@ func TestClient_Summarize(t *testing.T) {
 				ctx:  context.TODO(),
 				text: text,
 			},
-			want:    response,
-			wantErr: false,
+			wantPrompt: fmt.Sprintf("%s\n\nTl;dr", text),
+			want:       response,
+			wantErr:    false,
+		},
+		{
+			name: "language",
+			fields: fields{
+				apiKey: "some key",
+				log:    log,
+			},
+			args: args{
+				ctx:  context.TODO(),
+				text: text,
+				lang: article.German,
+			},
+			wantPrompt: fmt.Sprintf("%s\n\nTl;dr in German", text),
+			want:       response,
+			wantErr:    false,
 		},
 		{
 			name: "empty text",
@@ -81,8 +99,8 @@ func TestClient_Summarize(t *testing.T) {
 				if dto.Model != gpt3TextModel {
 					t.Fatalf("model: want %s got %s", gpt3TextModel, dto.Model)
 				}
-				if dto.Prompt != fmt.Sprintf("%s\n\nTl;dr", text) {
-					t.Fatalf("text: want %s got %s", text, dto.Prompt)
+				if dto.Prompt != tt.wantPrompt {
+					t.Fatalf("prompt: want %s got %s", tt.wantPrompt, dto.Prompt)
 				}
 
 				resp := responseDTO{
@@ -106,7 +124,7 @@ func TestClient_Summarize(t *testing.T) {
 				log:            tt.fields.log,
 			}
 
-			got, err := c.Summarize(tt.args.ctx, tt.args.text)
+			got, err := c.Summarize(tt.args.ctx, tt.args.text, tt.args.lang)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Client.Summarize() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -119,6 +137,86 @@ func TestClient_Summarize(t *testing.T) {
 	}
 }
 
+func TestClient_Embed(t *testing.T) {
+	t.Parallel()
+
+	log := logger.NewTest(false)
+	text := "Some text"
+	embedding := []float32{0.1, -0.2, 0.3}
+
+	tests := []struct {
+		name     string
+		text     string
+		response embeddingResponseDTO
+		want     []float32
+		wantErr  bool
+	}{
+		{
+			name:     "pass",
+			text:     text,
+			response: embeddingResponseDTO{Data: []embeddingDTO{{Embedding: embedding}}},
+			want:     embedding,
+		},
+		{
+			name:    "empty text",
+			text:    "",
+			wantErr: true,
+		},
+		{
+			name:     "no embedding",
+			text:     text,
+			response: embeddingResponseDTO{},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				defer r.Body.Close()
+				var dto embeddingRequestDTO
+				err := encoding.DecodeJSON(r.Body, &dto)
+				if err != nil {
+					t.Fatalf("could not decode body, %s", err.Error())
+				}
+
+				if dto.Model != embeddingModel {
+					t.Fatalf("model: want %s got %s", embeddingModel, dto.Model)
+				}
+				if dto.Input != tt.text {
+					t.Fatalf("input: want %s got %s", tt.text, dto.Input)
+				}
+
+				bb, err := encoding.EncodeJSON(tt.response)
+				if err != nil {
+					t.Fatalf("could not encode, %s", err.Error())
+				}
+
+				_, err = w.Write(bb)
+				if err != nil {
+					t.Fatalf("could not write bytes, %s", err.Error())
+				}
+			}))
+			defer svr.Close()
+
+			c := &Client{
+				embeddingAddr: svr.URL,
+				log:           log,
+			}
+
+			got, err := c.Embed(context.TODO(), tt.text)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Client.Embed() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Client.Embed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestClient_httpRequest_pass(t *testing.T) {
 	t.Parallel()
 	apiKey := "testKey"
@@ -309,10 +407,12 @@ func TestClient_toNER(t *testing.T) {
 	t.Parallel()
 
 	log := logger.NewTest(true)
+	body := "Die Polizei in Frankreich ermittelt."
 
 	tests := []struct {
 		name    string
 		text    string
+		types   []article.TypeSpec
 		log     *slog.Logger
 		want    article.NER
 		wantErr bool
@@ -329,48 +429,132 @@ func TestClient_toNER(t *testing.T) {
 			text:    "\n\n{\"Person\": [\"Gérald Darmanin\", \"Élisabeth Borne\"], \n\"Location\": [\"Frankreich\"], \n\"Organization\": [\"Polizei\"]}",
 			log:     log,
 			wantErr: false,
-			want: article.NER{
-				Pers: []string{"Gérald Darmanin", "Élisabeth Borne"},
-				Locs: []string{"Frankreich"},
-				Orgs: []string{"Polizei"},
-			},
+			want: article.NER{Entities: []article.Entity{
+				{Name: "Gérald Darmanin", Type: article.Person, Count: 1},
+				{Name: "Élisabeth Borne", Type: article.Person, Count: 1},
+				{Name: "Frankreich", Type: article.Location, Mentions: []article.Mention{{Start: 15, End: 25}}, Count: 1},
+				{Name: "Polizei", Type: article.Organisation, Mentions: []article.Mention{{Start: 4, End: 11}}, Count: 1},
+			}},
 		},
 		{
 			name:    "pass",
 			text:    "{\"Person\": [\"Gérald Darmanin\", \"Élisabeth Borne\"], \"Location\": [\"Frankreich\"], \"Organization\": [\"Polizei\"]}",
 			log:     log,
 			wantErr: false,
-			want: article.NER{
-				Pers: []string{"Gérald Darmanin", "Élisabeth Borne"},
-				Locs: []string{"Frankreich"},
-				Orgs: []string{"Polizei"},
-			},
+			want: article.NER{Entities: []article.Entity{
+				{Name: "Gérald Darmanin", Type: article.Person, Count: 1},
+				{Name: "Élisabeth Borne", Type: article.Person, Count: 1},
+				{Name: "Frankreich", Type: article.Location, Mentions: []article.Mention{{Start: 15, End: 25}}, Count: 1},
+				{Name: "Polizei", Type: article.Organisation, Mentions: []article.Mention{{Start: 4, End: 11}}, Count: 1},
+			}},
 		},
 		{
 			name:    "pass without key",
 			text:    "{ \"Location\": [\"Frankreich\"], \"Organization\": [\"Polizei\"]}",
 			log:     log,
 			wantErr: false,
-			want: article.NER{
-				Locs: []string{"Frankreich"},
-				Orgs: []string{"Polizei"},
+			want: article.NER{Entities: []article.Entity{
+				{Name: "Frankreich", Type: article.Location, Mentions: []article.Mention{{Start: 15, End: 25}}, Count: 1},
+				{Name: "Polizei", Type: article.Organisation, Mentions: []article.Mention{{Start: 4, End: 11}}, Count: 1},
+			}},
+		},
+		{
+			name: "configured types",
+			text: "{\"organization\": [\"Polizei\"], \"Event\": [\"Tour de France\"], \"Location\": [\"Frankreich\"], \"Weapon\": []}",
+			types: []article.TypeSpec{
+				{Type: article.Organisation, Label: "Organization"},
+				{Type: article.Event, Label: "Event"},
+				{Type: "weapon", Label: "Weapon"},
 			},
+			log:     log,
+			wantErr: false,
+			want: article.NER{Entities: []article.Entity{
+				{Name: "Polizei", Type: article.Organisation, Mentions: []article.Mention{{Start: 4, End: 11}}, Count: 1},
+				{Name: "Tour de France", Type: article.Event, Count: 1},
+			}},
+		},
+		{
+			name:    "labels differing in case",
+			text:    "{\"person\": [\"Élisabeth Borne\"], \"PERSON\": [\"Emmanuel Macron\"], \"Person\": [\"Gérald Darmanin\"]}",
+			log:     log,
+			wantErr: false,
+			want: article.NER{Entities: []article.Entity{
+				{Name: "Emmanuel Macron", Type: article.Person, Count: 1},
+				{Name: "Gérald Darmanin", Type: article.Person, Count: 1},
+				{Name: "Élisabeth Borne", Type: article.Person, Count: 1},
+			}},
+		},
+		{
+			name:    "no entities",
+			text:    "{\"Person\": []}",
+			log:     log,
+			wantErr: false,
+			want:    article.NER{},
+		},
+		{
+			name:    "invalid json",
+			text:    "{\"Person\": \"Gérald Darmanin\"}",
+			log:     log,
+			wantErr: true,
+			want:    article.NER{},
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			c := &Client{
-				log: tt.log,
+				entityTypes: tt.types,
+				log:         tt.log,
 			}
 
-			got, err := c.toNER(tt.text)
+			got, err := c.toNER(tt.text, body)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Client.toNER() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
 
 			if !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("Client.toNER() = %q, want %q", got, tt.want)
+				t.Errorf("Client.toNER() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_nerPrompt(t *testing.T) {
+	t.Parallel()
+
+	c := NewClient("key", logger.NewTest(true), WithEntityTypes(
+		article.TypeSpec{Type: article.Person, Label: "Person", Description: "people"},
+		article.TypeSpec{Type: "weapon", Label: "Weapon"},
+	))
+
+	tests := []struct {
+		name string
+		lang article.Language
+		want string
+	}{
+		{
+			name: "unknown language",
+			lang: article.LanguageUnknown,
+			want: "List the named entities of the following types in the text.\n" +
+				"- Person: people\n" +
+				"- Weapon\n" +
+				"Return a json object with the types as keys and the lists of entity names as values.\n\nText:",
+		},
+		{
+			name: "german",
+			lang: article.German,
+			want: "List the named entities of the following types in the text.\n" +
+				"- Person: people\n" +
+				"- Weapon\n" +
+				"The text is written in German. Write the names as they appear in the text.\n" +
+				"Return a json object with the types as keys and the lists of entity names as values.\n\nText:",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.nerPrompt(tt.lang); got != tt.want {
+				t.Errorf("Client.nerPrompt() = %q, want %q", got, tt.want)
 			}
 		})
 	}
@@ -0,0 +1,50 @@
+package feed
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+)
+
+var ErrInvalidFeedList = errors.New("invalid feed list")
+
+// ParseFeeds reads one feed per line, the addr optionally followed by the poll
+// interval, e.g. "https://news.example.com/rss 5m". Empty lines and lines starting
+// with # are skipped. Feeds without interval are polled every DefaultInterval.
+func ParseFeeds(r io.Reader) ([]Feed, error) {
+	var feeds []Feed
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		fields := strings.Fields(text)
+		if len(fields) > 2 {
+			return nil, fmt.Errorf("line %d has more than addr and interval, %w", line, ErrInvalidFeedList)
+		}
+		u, err := url.Parse(fields[0])
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("line %d has invalid addr %q, %w", line, fields[0], ErrInvalidFeedList)
+		}
+
+		f := Feed{Addr: fields[0]}
+		if len(fields) == 2 {
+			f.Interval, err = time.ParseDuration(fields[1])
+			if err != nil || f.Interval <= 0 {
+				return nil, fmt.Errorf("line %d has invalid interval %q, %w", line, fields[1], ErrInvalidFeedList)
+			}
+		}
+		feeds = append(feeds, f)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return feeds, nil
+}
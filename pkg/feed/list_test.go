@@ -0,0 +1,59 @@
+package feed
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseFeeds(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		input   string
+		want    []Feed
+		wantErr error
+	}{
+		{
+			name: "feeds",
+			input: "# news\n" +
+				"https://news.example.com/rss\n" +
+				"\n" +
+				"  https://blog.example.com/atom.xml 5m  \n",
+			want: []Feed{
+				{Addr: "https://news.example.com/rss"},
+				{Addr: "https://blog.example.com/atom.xml", Interval: 5 * time.Minute},
+			},
+		},
+		{
+			name:    "invalid addr",
+			input:   "news.example.com/rss\n",
+			wantErr: ErrInvalidFeedList,
+		},
+		{
+			name:    "invalid interval",
+			input:   "https://news.example.com/rss often\n",
+			wantErr: ErrInvalidFeedList,
+		},
+		{
+			name:    "too many fields",
+			input:   "https://news.example.com/rss 5m 10m\n",
+			wantErr: ErrInvalidFeedList,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseFeeds(strings.NewReader(tt.input))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParseFeeds() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseFeeds() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
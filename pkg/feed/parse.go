@@ -0,0 +1,259 @@
+// Package feed polls RSS 2.0 and Atom feeds and ingests their items as articles.
+package feed
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Br0ce/articleDB/pkg/article"
+)
+
+var ErrUnknownFormat = errors.New("unknown feed format")
+
+// Item is an entry of a feed.
+type Item struct {
+	// GUID identifies the item within the feed. It may be empty.
+	GUID      string
+	Title     string
+	Link      string
+	Author    string
+	Published time.Time
+	Body      string
+}
+
+// Key returns the key, that identifies the item within the feed.
+func (it Item) Key() string {
+	switch {
+	case it.GUID != "":
+		return it.GUID
+	case it.Link != "":
+		return it.Link
+	default:
+		return it.Title + "|" + it.Published.Format(time.RFC3339)
+	}
+}
+
+// Article maps the item to an article.Article.
+func (it Item) Article() (article.Article, error) {
+	addr, err := url.Parse(it.Link)
+	if err != nil {
+		return article.Article{}, fmt.Errorf("invalid link %q, %w", it.Link, err)
+	}
+
+	return article.Article{
+		Title:     it.Title,
+		Addr:      *addr,
+		Author:    it.Author,
+		Published: it.Published,
+		Body:      it.Body,
+	}, nil
+}
+
+type rssDTO struct {
+	Channel struct {
+		Items []rssItemDTO `xml:"item"`
+	} `xml:"channel"`
+}
+
+type rssItemDTO struct {
+	GUID        string `xml:"guid"`
+	Title       string `xml:"title"`
+	Link        string `xml:"link"`
+	Author      string `xml:"author"`
+	Creator     string `xml:"http://purl.org/dc/elements/1.1/ creator"`
+	PubDate     string `xml:"pubDate"`
+	Description string `xml:"description"`
+	Content     string `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+}
+
+type atomDTO struct {
+	Entries []atomEntryDTO `xml:"entry"`
+}
+
+type atomEntryDTO struct {
+	ID      string        `xml:"id"`
+	Title   string        `xml:"title"`
+	Links   []atomLinkDTO `xml:"link"`
+	Authors []struct {
+		Name string `xml:"name"`
+	} `xml:"author"`
+	Published string      `xml:"published"`
+	Updated   string      `xml:"updated"`
+	Summary   atomTextDTO `xml:"summary"`
+	Content   atomTextDTO `xml:"content"`
+}
+
+// atomTextDTO is an atom text construct. Its content is either text, escaped html or
+// inline xhtml.
+type atomTextDTO struct {
+	Type  string `xml:"type,attr"`
+	Text  string `xml:",chardata"`
+	Inner string `xml:",innerxml"`
+}
+
+func (t atomTextDTO) value() string {
+	if t.Type == "xhtml" {
+		return t.Inner
+	}
+	return t.Text
+}
+
+type atomLinkDTO struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+}
+
+// Parse parses an RSS 2.0 or Atom feed and returns its items.
+func Parse(r io.Reader) ([]Item, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	root, err := rootName(data)
+	if err != nil {
+		return nil, err
+	}
+
+	switch root {
+	case "rss":
+		return parseRSS(data)
+	case "feed":
+		return parseAtom(data)
+	default:
+		return nil, fmt.Errorf("root element %q, %w", root, ErrUnknownFormat)
+	}
+}
+
+// rootName returns the local name of the root element of the xml document.
+func rootName(data []byte) (string, error) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return "", fmt.Errorf("%s, %w", err.Error(), ErrUnknownFormat)
+		}
+		if start, ok := tok.(xml.StartElement); ok {
+			return start.Name.Local, nil
+		}
+	}
+}
+
+func parseRSS(data []byte) ([]Item, error) {
+	var dto rssDTO
+	if err := xml.Unmarshal(data, &dto); err != nil {
+		return nil, err
+	}
+
+	items := make([]Item, 0, len(dto.Channel.Items))
+	for _, it := range dto.Channel.Items {
+		author := it.Creator
+		if author == "" {
+			author = it.Author
+		}
+		body := it.Content
+		if body == "" {
+			body = it.Description
+		}
+
+		items = append(items, Item{
+			GUID:      strings.TrimSpace(it.GUID),
+			Title:     strings.TrimSpace(it.Title),
+			Link:      strings.TrimSpace(it.Link),
+			Author:    strings.TrimSpace(author),
+			Published: parseTime(it.PubDate),
+			Body:      plainText(body),
+		})
+	}
+
+	return items, nil
+}
+
+func parseAtom(data []byte) ([]Item, error) {
+	var dto atomDTO
+	if err := xml.Unmarshal(data, &dto); err != nil {
+		return nil, err
+	}
+
+	items := make([]Item, 0, len(dto.Entries))
+	for _, e := range dto.Entries {
+		var link string
+		for _, l := range e.Links {
+			if l.Rel == "" || l.Rel == "alternate" {
+				link = l.Href
+				break
+			}
+		}
+
+		var authors []string
+		for _, a := range e.Authors {
+			if name := strings.TrimSpace(a.Name); name != "" {
+				authors = append(authors, name)
+			}
+		}
+
+		published := e.Published
+		if published == "" {
+			published = e.Updated
+		}
+		body := e.Content.value()
+		if strings.TrimSpace(body) == "" {
+			body = e.Summary.value()
+		}
+
+		items = append(items, Item{
+			GUID:      strings.TrimSpace(e.ID),
+			Title:     strings.TrimSpace(e.Title),
+			Link:      strings.TrimSpace(link),
+			Author:    strings.Join(authors, ", "),
+			Published: parseTime(published),
+			Body:      plainText(body),
+		})
+	}
+
+	return items, nil
+}
+
+// timeLayouts are the date formats used by feeds in the wild.
+var timeLayouts = []string{
+	time.RFC1123Z,
+	time.RFC1123,
+	time.RFC3339,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	"2 Jan 2006 15:04:05 -0700",
+	"2006-01-02T15:04:05",
+}
+
+// parseTime parses the given feed date. It returns the zero time, if the date
+// can not be parsed.
+func parseTime(s string) time.Time {
+	s = strings.TrimSpace(s)
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.UTC()
+		}
+	}
+	return time.Time{}
+}
+
+var (
+	tagPattern   = regexp.MustCompile(`<[^>]*>`)
+	spacePattern = regexp.MustCompile(`[ \t]+`)
+)
+
+// plainText removes html tags and entities from the feed content.
+func plainText(s string) string {
+	s = tagPattern.ReplaceAllString(s, " ")
+	s = html.UnescapeString(s)
+	s = spacePattern.ReplaceAllString(s, " ")
+	return strings.TrimSpace(s)
+}
@@ -0,0 +1,92 @@
+package feed
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		file    string
+		want    []Item
+		wantErr bool
+	}{
+		{
+			name: "rss",
+			file: "testdata/rss.xml",
+			want: []Item{
+				{
+					GUID:      "example-1",
+					Title:     "Energy prices keep rising",
+					Link:      "https://news.example.com/energy-prices",
+					Author:    "Jane Doe",
+					Published: time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC),
+					Body:      "Gas and electricity prices rose again & households are worried.",
+				},
+				{
+					Title:     "Storm warning for the weekend",
+					Link:      "https://news.example.com/storm-warning",
+					Author:    "weather@example.com (John Doe)",
+					Published: time.Date(2023, 6, 2, 8, 30, 0, 0, time.UTC),
+					Body:      "Heavy rain is expected.",
+				},
+			},
+		},
+		{
+			name: "atom",
+			file: "testdata/atom.xml",
+			want: []Item{
+				{
+					GUID:      "urn:uuid:1225c695-cfb8-4ebb-aaaa-80da344efa6a",
+					Title:     "Parliament passes new law",
+					Link:      "https://news.example.com/law",
+					Author:    "Jane Doe, John Doe",
+					Published: time.Date(2023, 6, 1, 7, 0, 0, 0, time.UTC),
+					Body:      "The parliament passed the law with a large majority.",
+				},
+				{
+					GUID:      "urn:uuid:1225c695-cfb8-4ebb-aaaa-80da344efa6b",
+					Title:     "Football final tonight",
+					Link:      "https://news.example.com/football",
+					Published: time.Date(2023, 6, 2, 10, 0, 0, 0, time.UTC),
+					Body:      "The final starts at 9pm.",
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := os.Open(tt.file)
+			if err != nil {
+				t.Fatalf("could not open fixture, %s", err.Error())
+			}
+			defer f.Close()
+
+			got, err := Parse(f)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse_unknownFormat(t *testing.T) {
+	t.Parallel()
+
+	for _, doc := range []string{"<html><body></body></html>", "no xml", ""} {
+		if _, err := Parse(strings.NewReader(doc)); err == nil {
+			t.Errorf("Parse(%q) without error", doc)
+		}
+	}
+}
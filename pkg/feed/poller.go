@@ -0,0 +1,274 @@
+package feed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Br0ce/articleDB/pkg/article"
+	"github.com/Br0ce/articleDB/pkg/crawl"
+	"github.com/Br0ce/articleDB/pkg/db"
+	"github.com/Br0ce/articleDB/pkg/validate"
+)
+
+// DefaultInterval is the poll interval of a feed without configured interval.
+const DefaultInterval = 15 * time.Minute
+
+// Adder adds an article, e.g. the adder.Adder, that extracts the features of the
+// article before storing it.
+type Adder interface {
+	Add(ctx context.Context, ar article.Article) (string, error)
+}
+
+// SeenStore remembers the items of a feed, that have already been ingested.
+type SeenStore interface {
+	Seen(feed, key string) bool
+	MarkSeen(feed, key string)
+}
+
+// Feed is a feed to poll.
+type Feed struct {
+	Addr     string
+	Interval time.Duration
+}
+
+// feedState holds the validators of the last response of a feed for
+// conditional requests.
+type feedState struct {
+	etag         string
+	lastModified string
+}
+
+// Poller polls feeds and adds their new items.
+type Poller struct {
+	feeds  []Feed
+	client *http.Client
+	adder  Adder
+	seen   SeenStore
+	log    *slog.Logger
+
+	states map[string]feedState
+	mu     sync.Mutex
+}
+
+type PollerOption func(p *Poller)
+
+// NewPoller returns a Poller, that adds new items with the given adder. Without
+// options no feeds are polled, crawl.DefaultClient is used and seen items are only
+// remembered in memory.
+func NewPoller(adder Adder, log *slog.Logger, opts ...PollerOption) *Poller {
+	p := &Poller{
+		adder:  adder,
+		log:    log,
+		states: make(map[string]feedState),
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	if p.client == nil {
+		p.client = crawl.DefaultClient
+	}
+	if p.seen == nil {
+		p.seen = NewMemorySeen()
+	}
+
+	return p
+}
+
+// WithFeed adds a feed to poll.
+func WithFeed(f Feed) PollerOption {
+	return func(p *Poller) {
+		p.feeds = append(p.feeds, f)
+	}
+}
+
+// WithHTTPClient sets the client used to fetch the feeds. The client should use a
+// crawl.Transport shared with the other fetching paths.
+func WithHTTPClient(client *http.Client) PollerOption {
+	return func(p *Poller) {
+		p.client = client
+	}
+}
+
+// WithSeenStore sets the store, that remembers ingested items.
+func WithSeenStore(seen SeenStore) PollerOption {
+	return func(p *Poller) {
+		p.seen = seen
+	}
+}
+
+// Run polls every feed in its interval until ctx is done. The first poll of every
+// feed happens immediately. Poll errors are logged and do not stop the polling.
+func (p *Poller) Run(ctx context.Context) error {
+	p.log.Info("start polling feeds", "method", "Run", "feeds", len(p.feeds))
+
+	var wg sync.WaitGroup
+	for _, f := range p.feeds {
+		wg.Add(1)
+		go func(f Feed) {
+			defer wg.Done()
+			p.run(ctx, f)
+		}(f)
+	}
+	wg.Wait()
+
+	return ctx.Err()
+}
+
+func (p *Poller) run(ctx context.Context, f Feed) {
+	interval := f.Interval
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		added, err := p.Poll(ctx, f.Addr)
+		if err != nil {
+			p.log.Error("could not poll feed", "method", "run", "feed", f.Addr, "err", err.Error())
+		} else {
+			p.log.Info("polled feed", "method", "run", "feed", f.Addr, "added", added)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll fetches the feed at addr once and adds all items, that have not been seen
+// before. It returns the number of added articles. If the feed has not been modified
+// since the last poll, nothing is added. Items, that can not be mapped to an
+// article or are rejected as duplicates or as invalid by the adder, count as seen.
+// Items, that could not be added for other reasons, are retried on the next poll.
+func (p *Poller) Poll(ctx context.Context, addr string) (int, error) {
+	p.log.Debug("poll feed", "method", "Poll", "feed", addr)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, addr, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	p.mu.Lock()
+	state := p.states[addr]
+	p.mu.Unlock()
+
+	if state.etag != "" {
+		req.Header.Set("If-None-Match", state.etag)
+	}
+	if state.lastModified != "" {
+		req.Header.Set("If-Modified-Since", state.lastModified)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified {
+		p.log.Debug("feed not modified", "method", "Poll", "feed", addr)
+		return 0, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	items, err := Parse(io.LimitReader(resp.Body, crawl.DefaultMaxSize))
+	if err != nil {
+		return 0, err
+	}
+
+	var added int
+	var errs []error
+	for _, it := range items {
+		key := it.Key()
+		if p.seen.Seen(addr, key) {
+			continue
+		}
+
+		ar, err := it.Article()
+		if err != nil {
+			// The item does not change until the feed does, so it is not retried.
+			p.log.Warn("skip unmappable item", "method", "Poll", "feed", addr, "item", key, "err", err.Error())
+			p.seen.MarkSeen(addr, key)
+			continue
+		}
+
+		_, err = p.adder.Add(ctx, ar)
+		if errors.Is(err, db.ErrAlreadyExists) {
+			p.seen.MarkSeen(addr, key)
+			continue
+		}
+		if errors.Is(err, validate.ErrInvalidArticle) {
+			// An invalid item stays invalid, so it is not retried.
+			p.log.Warn("skip invalid item", "method", "Poll", "feed", addr, "item", key, "err", err.Error())
+			p.seen.MarkSeen(addr, key)
+			continue
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("could not add item %q, %w", key, err))
+			continue
+		}
+
+		p.seen.MarkSeen(addr, key)
+		added++
+	}
+
+	// The validators are only stored, if all items have been processed. Otherwise
+	// the next poll would be answered with not modified and the failed items would
+	// never be retried.
+	if len(errs) == 0 {
+		p.mu.Lock()
+		p.states[addr] = feedState{
+			etag:         resp.Header.Get("ETag"),
+			lastModified: resp.Header.Get("Last-Modified"),
+		}
+		p.mu.Unlock()
+	}
+
+	return added, errors.Join(errs...)
+}
+
+// MemorySeen is an inmemory SeenStore.
+type MemorySeen struct {
+	keys map[string]map[string]struct{}
+	mu   sync.RWMutex
+}
+
+func NewMemorySeen() *MemorySeen {
+	return &MemorySeen{
+		keys: make(map[string]map[string]struct{}),
+	}
+}
+
+// Seen reports whether the item with the given key of the feed has been marked.
+func (m *MemorySeen) Seen(feed, key string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	_, ok := m.keys[feed][key]
+	return ok
+}
+
+// MarkSeen marks the item with the given key of the feed as seen.
+func (m *MemorySeen) MarkSeen(feed, key string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.keys[feed] == nil {
+		m.keys[feed] = make(map[string]struct{})
+	}
+	m.keys[feed][key] = struct{}{}
+}
@@ -0,0 +1,258 @@
+package feed
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Br0ce/articleDB/pkg/article"
+	"github.com/Br0ce/articleDB/pkg/crawl"
+	"github.com/Br0ce/articleDB/pkg/db"
+	"github.com/Br0ce/articleDB/pkg/logger"
+	"github.com/Br0ce/articleDB/pkg/mock"
+	"github.com/Br0ce/articleDB/pkg/validate"
+)
+
+// testClient fetches from the test servers on the loopback address.
+var testClient = crawl.NewClient(crawl.NewTransport(crawl.WithPrivateNetworks()))
+
+// feedServer serves the fixture with an ETag and answers conditional requests.
+func feedServer(t *testing.T, file string) (*httptest.Server, *int) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("could not read fixture, %s", err.Error())
+	}
+
+	var requests int
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Content-Type", "application/rss+xml")
+		_, err := w.Write(data)
+		if err != nil {
+			t.Errorf("could not write fixture, %s", err.Error())
+		}
+	}))
+
+	return svr, &requests
+}
+
+func TestPoller_Poll(t *testing.T) {
+	t.Parallel()
+
+	svr, _ := feedServer(t, "testdata/rss.xml")
+	defer svr.Close()
+
+	var added []article.Article
+	adder := &mock.Adder{AddFn: func(ctx context.Context, ar article.Article) (string, error) {
+		added = append(added, ar)
+		return "1234", nil
+	}}
+
+	p := NewPoller(adder, logger.NewTest(false), WithHTTPClient(testClient))
+	ctx := context.TODO()
+
+	got, err := p.Poll(ctx, svr.URL)
+	if err != nil {
+		t.Fatalf("Poller.Poll() error = %v", err)
+	}
+	if got != 2 {
+		t.Errorf("Poller.Poll() = %v, want 2", got)
+	}
+	if added[0].Title != "Energy prices keep rising" || added[0].Addr.String() != "https://news.example.com/energy-prices" {
+		t.Errorf("added article = %+v", added[0])
+	}
+
+	// The second poll is answered with not modified.
+	got, err = p.Poll(ctx, svr.URL)
+	if err != nil {
+		t.Fatalf("Poller.Poll() error = %v", err)
+	}
+	if got != 0 || len(added) != 2 {
+		t.Errorf("Poller.Poll() not modified = %v, added %v", got, len(added))
+	}
+}
+
+func TestPoller_Poll_seen(t *testing.T) {
+	t.Parallel()
+
+	// The server ignores conditional requests, so only the seen store prevents
+	// adding the items again.
+	data, err := os.ReadFile("testdata/atom.xml")
+	if err != nil {
+		t.Fatalf("could not read fixture, %s", err.Error())
+	}
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := w.Write(data)
+		if err != nil {
+			t.Errorf("could not write fixture, %s", err.Error())
+		}
+	}))
+	defer svr.Close()
+
+	calls := 0
+	adder := &mock.Adder{AddFn: func(ctx context.Context, ar article.Article) (string, error) {
+		calls++
+		if calls == 1 {
+			return "", db.ErrAlreadyExists
+		}
+		return "1234", nil
+	}}
+
+	p := NewPoller(adder, logger.NewTest(false), WithHTTPClient(testClient))
+	ctx := context.TODO()
+
+	got, err := p.Poll(ctx, svr.URL)
+	if err != nil {
+		t.Fatalf("Poller.Poll() error = %v", err)
+	}
+	if got != 1 {
+		t.Errorf("Poller.Poll() = %v, want 1", got)
+	}
+
+	got, err = p.Poll(ctx, svr.URL)
+	if err != nil {
+		t.Fatalf("Poller.Poll() error = %v", err)
+	}
+	if got != 0 || calls != 2 {
+		t.Errorf("Poller.Poll() = %v, calls %v, want 0 and 2", got, calls)
+	}
+}
+
+func TestPoller_Poll_invalid(t *testing.T) {
+	t.Parallel()
+
+	svr, _ := feedServer(t, "testdata/rss.xml")
+	defer svr.Close()
+
+	calls := 0
+	adder := &mock.Adder{AddFn: func(ctx context.Context, ar article.Article) (string, error) {
+		calls++
+		if calls == 1 {
+			return "", &validate.Error{Fields: []validate.FieldError{{Field: validate.FieldBody, Reason: "is empty"}}}
+		}
+		return "1234", nil
+	}}
+
+	p := NewPoller(adder, logger.NewTest(false), WithHTTPClient(testClient))
+	ctx := context.TODO()
+
+	got, err := p.Poll(ctx, svr.URL)
+	if err != nil {
+		t.Fatalf("Poller.Poll() error = %v", err)
+	}
+	if got != 1 {
+		t.Errorf("Poller.Poll() = %v, want 1", got)
+	}
+
+	// The invalid item is not retried, so the ETag is stored and the second poll is
+	// answered with not modified.
+	got, err = p.Poll(ctx, svr.URL)
+	if err != nil {
+		t.Fatalf("Poller.Poll() error = %v", err)
+	}
+	if got != 0 || calls != 2 {
+		t.Errorf("Poller.Poll() = %v, calls %v, want 0 and 2", got, calls)
+	}
+	if etag := p.states[svr.URL].etag; etag != `"v1"` {
+		t.Errorf("stored etag = %v, want %v", etag, `"v1"`)
+	}
+}
+
+func TestPoller_Poll_brokenLink(t *testing.T) {
+	t.Parallel()
+
+	data, err := os.ReadFile("testdata/broken.xml")
+	if err != nil {
+		t.Fatalf("could not read fixture, %s", err.Error())
+	}
+	var conditional []string
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			http.NotFound(w, r)
+			return
+		}
+		conditional = append(conditional, r.Header.Get("If-None-Match"))
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		_, err := w.Write(data)
+		if err != nil {
+			t.Errorf("could not write fixture, %s", err.Error())
+		}
+	}))
+	defer svr.Close()
+
+	adder := &mock.Adder{AddFn: func(ctx context.Context, ar article.Article) (string, error) {
+		return "1234", nil
+	}}
+
+	p := NewPoller(adder, logger.NewTest(false), WithHTTPClient(testClient))
+	ctx := context.TODO()
+
+	got, err := p.Poll(ctx, svr.URL)
+	if err != nil {
+		t.Fatalf("Poller.Poll() error = %v", err)
+	}
+	if got != 1 {
+		t.Errorf("Poller.Poll() = %v, want 1", got)
+	}
+
+	// The item with the broken link is not retried, so the second poll is
+	// conditional.
+	got, err = p.Poll(ctx, svr.URL)
+	if err != nil {
+		t.Fatalf("Poller.Poll() error = %v", err)
+	}
+	if got != 0 {
+		t.Errorf("Poller.Poll() = %v, want 0", got)
+	}
+	if len(conditional) != 2 || conditional[1] != `"v1"` {
+		t.Errorf("If-None-Match of the polls = %q, want the etag on the second poll", conditional)
+	}
+}
+
+func TestPoller_Run(t *testing.T) {
+	t.Parallel()
+
+	svr, requests := feedServer(t, "testdata/rss.xml")
+	defer svr.Close()
+
+	var mu sync.Mutex
+	var added int
+	adder := &mock.Adder{AddFn: func(ctx context.Context, ar article.Article) (string, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		added++
+		return "1234", nil
+	}}
+
+	p := NewPoller(adder, logger.NewTest(false), WithHTTPClient(testClient),
+		WithFeed(Feed{Addr: svr.URL, Interval: 10 * time.Millisecond}))
+
+	ctx, cancel := context.WithTimeout(context.TODO(), 55*time.Millisecond)
+	defer cancel()
+
+	if err := p.Run(ctx); err != context.DeadlineExceeded {
+		t.Errorf("Poller.Run() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if added != 2 {
+		t.Errorf("added = %v, want 2", added)
+	}
+	if *requests < 2 {
+		t.Errorf("requests = %v, want at least 2", *requests)
+	}
+}
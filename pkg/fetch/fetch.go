@@ -0,0 +1,108 @@
+// Package fetch downloads web pages and extracts the article they contain.
+package fetch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"net/url"
+
+	"github.com/Br0ce/articleDB/pkg/article"
+	"github.com/Br0ce/articleDB/pkg/crawl"
+	"github.com/Br0ce/articleDB/pkg/readability"
+)
+
+var (
+	ErrInvalidURL       = errors.New("invalid url, must be an absolute http(s) url")
+	ErrNotHTML          = errors.New("response is not html")
+	ErrUnexpectedStatus = errors.New("unexpected response status")
+)
+
+// Fetcher fetches pages and extracts their main article.
+type Fetcher struct {
+	client *http.Client
+	log    *slog.Logger
+}
+
+type Option func(f *Fetcher)
+
+// New returns a Fetcher. Without options crawl.DefaultClient is used.
+func New(log *slog.Logger, opts ...Option) *Fetcher {
+	f := &Fetcher{log: log}
+
+	for _, opt := range opts {
+		opt(f)
+	}
+
+	if f.client == nil {
+		f.client = crawl.DefaultClient
+	}
+
+	return f
+}
+
+// WithHTTPClient sets the client used to fetch the pages. The client should use a
+// crawl.Transport shared with the other fetching paths.
+func WithHTTPClient(client *http.Client) Option {
+	return func(f *Fetcher) {
+		f.client = client
+	}
+}
+
+// ParseURL parses addr and checks that it is an absolute http(s) url.
+func ParseURL(addr string) (*url.URL, error) {
+	u, err := url.Parse(addr)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, ErrInvalidURL
+	}
+	return u, nil
+}
+
+// Fetch downloads the page at addr and returns the article extracted from it.
+// The returned article has no ID and no features yet.
+func (f *Fetcher) Fetch(ctx context.Context, addr string) (article.Article, error) {
+	f.log.Info("fetch article", "method", "Fetch", "addr", addr)
+
+	u, err := ParseURL(addr)
+	if err != nil {
+		return article.Article{}, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return article.Article{}, err
+	}
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return article.Article{}, err
+	}
+	defer resp.Body.Close()
+
+	if crawl.TemporaryStatus(resp.StatusCode) {
+		return article.Article{}, fmt.Errorf("%s, %w, %w", resp.Status, ErrUnexpectedStatus, crawl.ErrTemporary)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return article.Article{}, fmt.Errorf("%s, %w", resp.Status, ErrUnexpectedStatus)
+	}
+
+	mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if err != nil || (mediaType != "text/html" && mediaType != "application/xhtml+xml") {
+		return article.Article{}, ErrNotHTML
+	}
+
+	// The client may not limit the size, so the page is capped at the size the
+	// crawl transport accepts.
+	doc, err := readability.Extract(io.LimitReader(resp.Body, crawl.DefaultMaxSize))
+	if err != nil {
+		return article.Article{}, err
+	}
+
+	// Redirects are followed, so the final url is the one of the last request.
+	return doc.Article(*resp.Request.URL), nil
+}
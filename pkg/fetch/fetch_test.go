@@ -0,0 +1,106 @@
+package fetch
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/Br0ce/articleDB/pkg/crawl"
+	"github.com/Br0ce/articleDB/pkg/logger"
+	"github.com/Br0ce/articleDB/pkg/readability"
+)
+
+func TestFetcher_Fetch(t *testing.T) {
+	t.Parallel()
+
+	page, err := os.ReadFile("../readability/testdata/opengraph.html")
+	if err != nil {
+		t.Fatalf("could not read fixture, %s", err.Error())
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/article", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, err := w.Write(page)
+		if err != nil {
+			t.Errorf("could not write page, %s", err.Error())
+		}
+	})
+	mux.HandleFunc("/json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, err := w.Write([]byte("{}"))
+		if err != nil {
+			t.Errorf("could not write json, %s", err.Error())
+		}
+	})
+	mux.HandleFunc("/empty", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, err := w.Write([]byte("<html><body></body></html>"))
+		if err != nil {
+			t.Errorf("could not write page, %s", err.Error())
+		}
+	})
+	svr := httptest.NewServer(mux)
+	defer svr.Close()
+
+	tests := []struct {
+		name    string
+		addr    string
+		wantErr error
+	}{
+		{name: "pass", addr: svr.URL + "/article"},
+		{name: "invalid url", addr: "/article", wantErr: ErrInvalidURL},
+		{name: "invalid scheme", addr: "ftp://example.com/article", wantErr: ErrInvalidURL},
+		{name: "not found", addr: svr.URL + "/missing", wantErr: ErrUnexpectedStatus},
+		{name: "not html", addr: svr.URL + "/json", wantErr: ErrNotHTML},
+		{name: "no content", addr: svr.URL + "/empty", wantErr: readability.ErrNoContent},
+	}
+	client := crawl.NewClient(crawl.NewTransport(crawl.WithPrivateNetworks()))
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := New(logger.NewTest(false), WithHTTPClient(client))
+
+			got, err := f.Fetch(context.TODO(), tt.addr)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Fetcher.Fetch() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			if got.Title != "Storm warning for the weekend" {
+				t.Errorf("title = %v", got.Title)
+			}
+			if got.Addr.String() != "https://weather.example.com/storm-warning" {
+				t.Errorf("addr = %v, want canonical addr", got.Addr.String())
+			}
+			if !got.Published.Equal(time.Date(2023, 6, 2, 8, 30, 0, 0, time.UTC)) {
+				t.Errorf("published = %v", got.Published)
+			}
+			if got.Body == "" {
+				t.Error("body is empty")
+			}
+		})
+	}
+}
+
+func TestFetcher_Fetch_private(t *testing.T) {
+	t.Parallel()
+
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("private address requested")
+	}))
+	defer svr.Close()
+
+	f := New(logger.NewTest(false))
+	for _, addr := range []string{svr.URL + "/article", "http://169.254.169.254/latest/meta-data/"} {
+		if _, err := f.Fetch(context.TODO(), addr); !errors.Is(err, crawl.ErrPrivate) {
+			t.Errorf("Fetcher.Fetch() error = %v, want %v", err, crawl.ErrPrivate)
+		}
+	}
+}
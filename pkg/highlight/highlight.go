@@ -0,0 +1,209 @@
+// Package highlight builds fragments of texts, that show where the terms of a
+// query matched. The matches are given as byte offsets, e.g. captured by an index
+// while tokenizing the text.
+package highlight
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Span is a matched part of a text. Start and End are byte offsets, End is
+// exclusive.
+type Span struct {
+	Start int
+	End   int
+}
+
+const (
+	DefaultFragmentSize = 150
+	DefaultFragments    = 3
+	DefaultPreTag       = "<em>"
+	DefaultPostTag      = "</em>"
+)
+
+// Highlighter wraps the matched spans of texts in tags.
+type Highlighter struct {
+	size      int
+	fragments int
+	pre       string
+	post      string
+	escape    func(string) string
+}
+
+type Option func(h *Highlighter)
+
+// WithFragmentSize sets the size of a fragment in bytes. Fragments are cut at word
+// boundaries, so they can be a bit shorter. Without it, DefaultFragmentSize is used.
+func WithFragmentSize(size int) Option {
+	return func(h *Highlighter) {
+		h.size = size
+	}
+}
+
+// WithFragments sets the maximal number of fragments of a text. Without it,
+// DefaultFragments is used.
+func WithFragments(n int) Option {
+	return func(h *Highlighter) {
+		h.fragments = n
+	}
+}
+
+// WithTags sets the tags written before and after a match. Without it, the matches
+// are wrapped in DefaultPreTag and DefaultPostTag.
+func WithTags(pre, post string) Option {
+	return func(h *Highlighter) {
+		h.pre = pre
+		h.post = post
+	}
+}
+
+// WithEscaper escapes the text around the tags, e.g. with html.EscapeString, if
+// the fragments are rendered as HTML. Without it, the text is written as is.
+func WithEscaper(escape func(string) string) Option {
+	return func(h *Highlighter) {
+		h.escape = escape
+	}
+}
+
+func New(opts ...Option) *Highlighter {
+	h := &Highlighter{
+		size:      DefaultFragmentSize,
+		fragments: DefaultFragments,
+		pre:       DefaultPreTag,
+		post:      DefaultPostTag,
+		escape:    func(s string) string { return s },
+	}
+
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	return h
+}
+
+// Highlight returns the whole text with the spans wrapped in tags, e.g. for a title.
+// It is empty, if no span is in the text.
+func (h *Highlighter) Highlight(text string, spans []Span) string {
+	spans = normalize(text, spans)
+	if len(spans) == 0 {
+		return ""
+	}
+	return h.mark(text, 0, len(text), spans)
+}
+
+// Fragments returns fragments of the text around the spans with the spans wrapped
+// in tags. The fragments with the most spans come first, on a tie the earlier one.
+// Every span is shown in one fragment at most.
+func (h *Highlighter) Fragments(text string, spans []Span) []string {
+	spans = normalize(text, spans)
+	if len(spans) == 0 || h.fragments < 1 {
+		return nil
+	}
+
+	type window struct {
+		start, end int
+		spans      []Span
+	}
+	var windows []window
+	for i := 0; i < len(spans); {
+		start, end := h.window(text, spans[i])
+		j := i
+		for j < len(spans) && spans[j].End <= end {
+			j++
+		}
+		windows = append(windows, window{start: start, end: end, spans: spans[i:j]})
+		i = j
+	}
+
+	sort.SliceStable(windows, func(i, j int) bool {
+		return len(windows[i].spans) > len(windows[j].spans)
+	})
+	if len(windows) > h.fragments {
+		windows = windows[:h.fragments]
+	}
+
+	fragments := make([]string, 0, len(windows))
+	for _, w := range windows {
+		fragments = append(fragments, h.mark(text, w.start, w.end, w.spans))
+	}
+	return fragments
+}
+
+// window returns the fragment of the text starting near the span, so the span and
+// the following text fit into the fragment size. A span larger than the fragment
+// size is returned as a whole.
+func (h *Highlighter) window(text string, span Span) (int, int) {
+	lead := max(h.size-(span.End-span.Start), 0) / 4
+	start := max(span.Start-lead, 0)
+	end := min(max(start+h.size, span.End), len(text))
+	if end == len(text) {
+		start = max(min(end-h.size, start), 0)
+	}
+
+	// The fragment is cut at the boundaries of words, but never inside the span.
+	if start > 0 && inWord(text, start) {
+		if i := strings.IndexFunc(text[start:span.Start], unicode.IsSpace); i >= 0 {
+			start += i
+		}
+		for start < span.Start && !utf8.RuneStart(text[start]) {
+			start++
+		}
+	}
+	if end < len(text) && inWord(text, end) {
+		if i := strings.LastIndexFunc(text[span.End:end], unicode.IsSpace); i >= 0 {
+			end = span.End + i
+		}
+		for end > span.End && !utf8.RuneStart(text[end]) {
+			end--
+		}
+	}
+
+	return start, end
+}
+
+// inWord reports whether the offset i is between two runes, that are no spaces.
+func inWord(text string, i int) bool {
+	before, _ := utf8.DecodeLastRuneInString(text[:i])
+	after, _ := utf8.DecodeRuneInString(text[i:])
+	return !unicode.IsSpace(before) && !unicode.IsSpace(after)
+}
+
+// mark wraps the spans in text[start:end] in tags and escapes the text.
+func (h *Highlighter) mark(text string, start, end int, spans []Span) string {
+	var b strings.Builder
+	at := start
+	for _, s := range spans {
+		b.WriteString(h.escape(text[at:s.Start]))
+		b.WriteString(h.pre)
+		b.WriteString(h.escape(text[s.Start:s.End]))
+		b.WriteString(h.post)
+		at = s.End
+	}
+	b.WriteString(h.escape(text[at:end]))
+	return strings.TrimSpace(b.String())
+}
+
+// normalize drops the spans outside of the text, sorts the spans and merges
+// overlapping ones.
+func normalize(text string, spans []Span) []Span {
+	var valid []Span
+	for _, s := range spans {
+		if s.Start >= 0 && s.Start < s.End && s.End <= len(text) {
+			valid = append(valid, s)
+		}
+	}
+	sort.Slice(valid, func(i, j int) bool { return valid[i].Start < valid[j].Start })
+
+	var merged []Span
+	for _, s := range valid {
+		if n := len(merged); n > 0 && s.Start <= merged[n-1].End {
+			merged[n-1].End = max(merged[n-1].End, s.End)
+			continue
+		}
+		merged = append(merged, s)
+	}
+	return merged
+}
@@ -0,0 +1,138 @@
+package highlight
+
+import (
+	"html"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// spans returns the spans of the words in text.
+func spans(text string, words ...string) []Span {
+	var result []Span
+	for _, w := range words {
+		for offset := 0; ; {
+			i := strings.Index(text[offset:], w)
+			if i < 0 {
+				break
+			}
+			result = append(result, Span{Start: offset + i, End: offset + i + len(w)})
+			offset += i + len(w)
+		}
+	}
+	return result
+}
+
+func TestHighlighter_Highlight(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		h     *Highlighter
+		text  string
+		spans []Span
+		want  string
+	}{
+		{
+			name:  "default tags",
+			h:     New(),
+			text:  "Talks on the energy crisis",
+			spans: spans("Talks on the energy crisis", "energy", "crisis"),
+			want:  "Talks on the <em>energy</em> <em>crisis</em>",
+		},
+		{
+			name:  "custom tags and overlapping spans",
+			h:     New(WithTags("[", "]")),
+			text:  "energy crisis",
+			spans: []Span{{Start: 0, End: 6}, {Start: 3, End: 13}},
+			want:  "[energy crisis]",
+		},
+		{
+			name:  "escape",
+			h:     New(WithEscaper(html.EscapeString)),
+			text:  "<b>energy</b> & more",
+			spans: []Span{{Start: 3, End: 9}},
+			want:  "&lt;b&gt;<em>energy</em>&lt;/b&gt; &amp; more",
+		},
+		{
+			name:  "no span",
+			h:     New(),
+			text:  "energy",
+			spans: []Span{{Start: 4, End: 10}},
+			want:  "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.h.Highlight(tt.text, tt.spans); got != tt.want {
+				t.Errorf("Highlighter.Highlight() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHighlighter_Fragments(t *testing.T) {
+	t.Parallel()
+
+	text := "The government met on Monday. Prices for energy rose again, households " +
+		"struggle with the energy crisis. Experts expect a long winter. " +
+		"Later the minister said the crisis will end in spring."
+
+	tests := []struct {
+		name  string
+		h     *Highlighter
+		spans []Span
+		want  []string
+	}{
+		{
+			name:  "most matches first",
+			h:     New(WithFragmentSize(40), WithTags("*", "*")),
+			spans: spans(text, "energy", "crisis"),
+			want: []string{
+				"the *energy* *crisis*. Experts expect a",
+				"for *energy* rose again, households",
+				"said the *crisis* will end in spring.",
+			},
+		},
+		{
+			name:  "limit fragments",
+			h:     New(WithFragmentSize(40), WithFragments(1), WithTags("*", "*")),
+			spans: spans(text, "energy", "crisis"),
+			want:  []string{"the *energy* *crisis*. Experts expect a"},
+		},
+		{
+			name:  "fragment at the start",
+			h:     New(WithFragmentSize(30), WithTags("*", "*")),
+			spans: spans(text, "government"),
+			want:  []string{"The *government* met on Monday."},
+		},
+		{
+			name:  "fragment at the end",
+			h:     New(WithFragmentSize(30), WithTags("*", "*")),
+			spans: spans(text, "spring"),
+			want:  []string{"the crisis will end in *spring*."},
+		},
+		{
+			name:  "large fragment holds all",
+			h:     New(WithFragmentSize(1000), WithTags("*", "*")),
+			spans: spans(text, "winter"),
+			want:  []string{strings.Replace(text, "winter", "*winter*", 1)},
+		},
+		{
+			name:  "no spans",
+			h:     New(),
+			spans: nil,
+			want:  nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.h.Fragments(text, tt.spans)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Highlighter.Fragments() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package ids
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Generator generates unique IDs.
+type Generator interface {
+	NewID() string
+}
+
+// GeneratorFunc adapts a function to a Generator.
+type GeneratorFunc func() string
+
+func (f GeneratorFunc) NewID() string {
+	return f()
+}
+
+// Random generates random UUIDv4s. It is the default generator of the stores.
+var Random Generator = GeneratorFunc(UniqueID)
+
+// TimeOrdered generates UUIDv7s. Their string representations sort in the order of
+// generation, so they can be used as cursor for stable pagination. The zero value
+// uses the system clock.
+type TimeOrdered struct {
+	now func() time.Time
+
+	mu   sync.Mutex
+	last int64
+	seq  uint16
+}
+
+// NewTimeOrdered returns a TimeOrdered generator using the system clock.
+func NewTimeOrdered() *TimeOrdered {
+	return &TimeOrdered{now: time.Now}
+}
+
+// NewID returns a UUIDv7 as defined in RFC 9562. The first 48 bits hold the unix
+// time in milliseconds, the following 12 bits a counter, that keeps IDs generated
+// within the same millisecond ordered, the remaining bits are random. If the clock
+// goes backwards or the counter overflows, the time of the last ID is continued.
+func (g *TimeOrdered) NewID() string {
+	now := g.now
+	if now == nil {
+		now = time.Now
+	}
+
+	g.mu.Lock()
+	ms := now().UnixMilli()
+	if ms > g.last {
+		g.last = ms
+		g.seq = 0
+	} else {
+		g.seq++
+		if g.seq > 0x0fff {
+			g.last++
+			g.seq = 0
+		}
+	}
+	ms, seq := g.last, g.seq
+	g.mu.Unlock()
+
+	var id uuid.UUID
+	_, err := rand.Read(id[8:])
+	if err != nil {
+		// crypto/rand does not fail on supported platforms, a random v4 ID is still
+		// unique.
+		return UniqueID()
+	}
+
+	var ts [8]byte
+	binary.BigEndian.PutUint64(ts[:], uint64(ms))
+	copy(id[0:6], ts[2:8])
+	binary.BigEndian.PutUint16(id[6:8], 0x7000|seq)
+	id[8] = id[8]&0x3f | 0x80
+
+	return id.String()
+}
+
+// Time returns the time encoded in a UUIDv7. It reports false for other IDs.
+func Time(id string) (time.Time, bool) {
+	u, err := uuid.Parse(id)
+	if err != nil || u.Version() != 7 {
+		return time.Time{}, false
+	}
+
+	var ts [8]byte
+	copy(ts[2:8], u[0:6])
+	return time.UnixMilli(int64(binary.BigEndian.Uint64(ts[:]))).UTC(), true
+}
@@ -0,0 +1,113 @@
+package ids
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestTimeOrdered_NewID(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		clock []time.Time
+	}{
+		{
+			name:  "advancing clock",
+			clock: []time.Time{start, start.Add(time.Millisecond), start.Add(time.Second)},
+		},
+		{
+			name:  "same millisecond",
+			clock: []time.Time{start, start, start.Add(time.Microsecond)},
+		},
+		{
+			name:  "clock goes backwards",
+			clock: []time.Time{start, start.Add(-time.Second), start.Add(-time.Minute)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			i := 0
+			g := &TimeOrdered{now: func() time.Time {
+				now := tt.clock[i%len(tt.clock)]
+				i++
+				return now
+			}}
+
+			var got []string
+			for j := 0; j < len(tt.clock); j++ {
+				id := g.NewID()
+				u, err := uuid.Parse(id)
+				if err != nil {
+					t.Fatalf("invalid ID %v, %s", id, err.Error())
+				}
+				if u.Version() != 7 || u.Variant() != uuid.RFC4122 {
+					t.Errorf("ID %v has version %v and variant %v", id, u.Version(), u.Variant())
+				}
+				if !ValidID(id) {
+					t.Errorf("ValidID(%v) = false", id)
+				}
+				got = append(got, id)
+			}
+
+			if !sort.StringsAreSorted(got) {
+				t.Errorf("IDs are not ordered, %v", got)
+			}
+			ts, ok := Time(got[0])
+			if !ok || !ts.Equal(start) {
+				t.Errorf("Time() = %v, %v, want %v", ts, ok, start)
+			}
+		})
+	}
+}
+
+func TestTimeOrdered_NewID_counterOverflow(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+	g := &TimeOrdered{now: func() time.Time { return start }}
+
+	prev := g.NewID()
+	for i := 0; i < 5000; i++ {
+		id := g.NewID()
+		if id <= prev {
+			t.Fatalf("ID %v is not after %v", id, prev)
+		}
+		prev = id
+	}
+
+	ts, ok := Time(prev)
+	if !ok || !ts.After(start) {
+		t.Errorf("Time() = %v, want after %v", ts, start)
+	}
+}
+
+func TestTimeOrdered_NewID_zeroValue(t *testing.T) {
+	t.Parallel()
+
+	var g TimeOrdered
+	before := time.Now().Truncate(time.Millisecond)
+	id := g.NewID()
+
+	ts, ok := Time(id)
+	if !ok || ts.Before(before) || ts.After(time.Now()) {
+		t.Errorf("Time() = %v, %v, want around %v", ts, ok, before)
+	}
+}
+
+func TestTime(t *testing.T) {
+	t.Parallel()
+
+	if _, ok := Time(UniqueID()); ok {
+		t.Error("Time() of a UUIDv4 reports true")
+	}
+	if _, ok := Time("1234"); ok {
+		t.Error("Time() of an invalid ID reports true")
+	}
+}
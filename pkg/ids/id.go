@@ -10,10 +10,13 @@ var (
 	ErrInvalidID = errors.New("invalid id")
 )
 
+// UniqueID returns a random UUIDv4.
 func UniqueID() string {
 	return uuid.NewString()
 }
 
+// ValidID reports whether id is a UUID, e.g. a random UUIDv4 or a time-ordered
+// UUIDv7.
 func ValidID(id string) bool {
 	if id == "" {
 		return false
@@ -25,3 +28,12 @@ func ValidID(id string) bool {
 
 	return true
 }
+
+// Namespace is the namespace of the IDs derived from names.
+var Namespace = uuid.NewSHA1(uuid.NameSpaceURL, []byte("https://github.com/Br0ce/articleDB"))
+
+// NameID returns the UUIDv5 of name in the Namespace. The same name always gives the
+// same ID.
+func NameID(name string) string {
+	return uuid.NewSHA1(Namespace, []byte(name)).String()
+}
@@ -36,6 +36,11 @@ func TestValidID(t *testing.T) {
 			id:   uuid.NewString(),
 			want: true,
 		},
+		{
+			name: "time ordered id",
+			id:   NewTimeOrdered().NewID(),
+			want: true,
+		},
 		{
 			name: "empty id",
 			id:   "",
@@ -62,3 +67,22 @@ func TestValidID(t *testing.T) {
 		})
 	}
 }
+
+func TestNameID(t *testing.T) {
+	t.Parallel()
+
+	got := NameID("https://news.example.com/story")
+	u, err := uuid.Parse(got)
+	if err != nil {
+		t.Fatalf("invalid ID, got %v, err %v", got, err.Error())
+	}
+	if u.Version() != 5 {
+		t.Errorf("NameID() version = %v, want 5", u.Version())
+	}
+	if again := NameID("https://news.example.com/story"); again != got {
+		t.Errorf("NameID() is not deterministic, got %v, again %v", got, again)
+	}
+	if other := NameID("https://news.example.com/other"); other == got {
+		t.Errorf("NameID() of different names is equal, %v", got)
+	}
+}
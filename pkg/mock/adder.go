@@ -0,0 +1,17 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/Br0ce/articleDB/pkg/article"
+)
+
+type Adder struct {
+	AddFn      func(ctx context.Context, ar article.Article) (string, error)
+	AddInvoked bool
+}
+
+func (a *Adder) Add(ctx context.Context, ar article.Article) (string, error) {
+	a.AddInvoked = true
+	return a.AddFn(ctx, ar)
+}
@@ -12,6 +12,12 @@ type DB struct {
 
 	GetFn      func(ctx context.Context, id string) (article.Article, error)
 	GetInvoked bool
+
+	ListFn      func(ctx context.Context) ([]article.Article, error)
+	ListInvoked bool
+
+	UpdateFn      func(ctx context.Context, ar article.Article) error
+	UpdateInvoked bool
 }
 
 func (db *DB) Add(ctx context.Context, ar article.Article) (string, error) {
@@ -23,3 +29,13 @@ func (db *DB) Get(ctx context.Context, id string) (article.Article, error) {
 	db.GetInvoked = true
 	return db.GetFn(ctx, id)
 }
+
+func (db *DB) List(ctx context.Context) ([]article.Article, error) {
+	db.ListInvoked = true
+	return db.ListFn(ctx)
+}
+
+func (db *DB) Update(ctx context.Context, ar article.Article) error {
+	db.UpdateInvoked = true
+	return db.UpdateFn(ctx, ar)
+}
@@ -0,0 +1,17 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/Br0ce/articleDB/pkg/article"
+)
+
+type NearDuplicateFinder struct {
+	CanonicalFn      func(ctx context.Context, fingerprint uint64, threshold float64) (article.Article, bool, error)
+	CanonicalInvoked bool
+}
+
+func (f *NearDuplicateFinder) Canonical(ctx context.Context, fingerprint uint64, threshold float64) (article.Article, bool, error) {
+	f.CanonicalInvoked = true
+	return f.CanonicalFn(ctx, fingerprint, threshold)
+}
@@ -7,21 +7,31 @@ import (
 )
 
 type Summarizer struct {
-	SummarizeFn       func(ctx context.Context, text string) (string, error)
+	SummarizeFn       func(ctx context.Context, text string, lang article.Language) (string, error)
 	SummarizerInvoked bool
 }
 
-func (s *Summarizer) Summarize(ctx context.Context, text string) (string, error) {
+func (s *Summarizer) Summarize(ctx context.Context, text string, lang article.Language) (string, error) {
 	s.SummarizerInvoked = true
-	return s.SummarizeFn(ctx, text)
+	return s.SummarizeFn(ctx, text, lang)
 }
 
 type NER struct {
-	NERFn      func(ctx context.Context, text string) (article.NER, error)
+	NERFn      func(ctx context.Context, text string, lang article.Language) (article.NER, error)
 	NERInvoked bool
 }
 
-func (n *NER) NER(ctx context.Context, text string) (article.NER, error) {
+func (n *NER) NER(ctx context.Context, text string, lang article.Language) (article.NER, error) {
 	n.NERInvoked = true
-	return n.NERFn(ctx, text)
+	return n.NERFn(ctx, text, lang)
+}
+
+type Embedder struct {
+	EmbedFn      func(ctx context.Context, text string) ([]float32, error)
+	EmbedInvoked bool
+}
+
+func (e *Embedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	e.EmbedInvoked = true
+	return e.EmbedFn(ctx, text)
 }
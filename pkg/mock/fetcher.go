@@ -0,0 +1,17 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/Br0ce/articleDB/pkg/article"
+)
+
+type Fetcher struct {
+	FetchFn      func(ctx context.Context, addr string) (article.Article, error)
+	FetchInvoked bool
+}
+
+func (f *Fetcher) Fetch(ctx context.Context, addr string) (article.Article, error) {
+	f.FetchInvoked = true
+	return f.FetchFn(ctx, addr)
+}
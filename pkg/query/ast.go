@@ -0,0 +1,157 @@
+// Package query parses the query language of the article search and plans its
+// execution against the indexes of a store.
+//
+// A query combines terms with AND, OR, NOT and parentheses. Terms without an
+// operator in between are combined with AND. A term is a word or a quoted phrase,
+// optionally restricted to a field:
+//
+//	person:"Olaf Scholz" AND org:EU AND published:[2023-01-01 TO 2023-06-30] AND "energy crisis"
+//
+// The fields title, body, summary and author match text, the fields of the entity
+// types, e.g. person or org, match named entities and the fields published and
+// created match time ranges. Ranges are inclusive, either bound can be * for an open
+// range.
+//
+// A word, a phrase or a name followed by ~ matches with typos, e.g. scholtz~ or
+// person:"Olaf Scholtz"~1. The number after ~ is the maximal edit distance of every
+// word, the default is 2.
+package query
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Br0ce/articleDB/pkg/article"
+)
+
+// Node is a node of the syntax tree of a query.
+type Node interface {
+	// Pos returns the byte offset of the node in the query.
+	Pos() int
+	String() string
+}
+
+// TextField is a text field of an article. TextAny matches the title and the body.
+type TextField string
+
+const (
+	TextAny     TextField = ""
+	TextTitle   TextField = "title"
+	TextBody    TextField = "body"
+	TextSummary TextField = "summary"
+	TextAuthor  TextField = "author"
+)
+
+// And matches the articles matched by all nodes.
+type And struct {
+	Nodes    []Node
+	Position int
+}
+
+// Or matches the articles matched by any node.
+type Or struct {
+	Nodes    []Node
+	Position int
+}
+
+// Not matches the articles not matched by the node.
+type Not struct {
+	Node     Node
+	Position int
+}
+
+// Text matches the articles containing the words of Value in the given order in
+// the field.
+type Text struct {
+	Field TextField
+	Value string
+	// Fuzziness is the maximal edit distance of a matching word to a word of Value.
+	Fuzziness int
+	Position  int
+}
+
+// Entity matches the articles mentioning the named entity. An empty type matches
+// entities of any type.
+type Entity struct {
+	Type article.EntityType
+	Name string
+	// Fuzziness is the maximal edit distance of a matching name to Name.
+	Fuzziness int
+	Position  int
+}
+
+// Range matches the articles with a time in [From, To). A zero From or To leaves
+// the range open on that side.
+type Range struct {
+	Field    article.TimeField
+	From     time.Time
+	To       time.Time
+	Position int
+}
+
+func (n *And) Pos() int    { return n.Position }
+func (n *Or) Pos() int     { return n.Position }
+func (n *Not) Pos() int    { return n.Position }
+func (n *Text) Pos() int   { return n.Position }
+func (n *Entity) Pos() int { return n.Position }
+func (n *Range) Pos() int  { return n.Position }
+
+func (n *And) String() string {
+	return join(n.Nodes, " AND ")
+}
+
+func (n *Or) String() string {
+	return join(n.Nodes, " OR ")
+}
+
+func (n *Not) String() string {
+	return "NOT " + n.Node.String()
+}
+
+func (n *Text) String() string {
+	if n.Field == TextAny {
+		return fmt.Sprintf("%q%s", n.Value, fuzziness(n.Fuzziness))
+	}
+	return fmt.Sprintf("%s:%q%s", n.Field, n.Value, fuzziness(n.Fuzziness))
+}
+
+func (n *Entity) String() string {
+	if n.Type == "" {
+		return fmt.Sprintf("entity:%q%s", n.Name, fuzziness(n.Fuzziness))
+	}
+	return fmt.Sprintf("%s:%q%s", n.Type, n.Name, fuzziness(n.Fuzziness))
+}
+
+func fuzziness(distance int) string {
+	if distance == 0 {
+		return ""
+	}
+	return fmt.Sprintf("~%d", distance)
+}
+
+func (n *Range) String() string {
+	return fmt.Sprintf("%s:[%s TO %s)", timeFieldName(n.Field), bound(n.From), bound(n.To))
+}
+
+func join(nodes []Node, op string) string {
+	s := make([]string, 0, len(nodes))
+	for _, n := range nodes {
+		s = append(s, n.String())
+	}
+	return "(" + strings.Join(s, op) + ")"
+}
+
+func bound(t time.Time) string {
+	if t.IsZero() {
+		return "*"
+	}
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+func timeFieldName(f article.TimeField) string {
+	if f == article.TimeCreated {
+		return "created"
+	}
+	return "published"
+}
@@ -0,0 +1,456 @@
+package query
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Br0ce/articleDB/pkg/analysis"
+	"github.com/Br0ce/articleDB/pkg/article"
+)
+
+var ErrSyntax = errors.New("syntax error")
+
+// SyntaxError describes an invalid query. It wraps ErrSyntax.
+type SyntaxError struct {
+	// Pos is the byte offset of the error in the query.
+	Pos int
+	Msg string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("%s at offset %d, %s", e.Msg, e.Pos, ErrSyntax.Error())
+}
+
+func (e *SyntaxError) Unwrap() error {
+	return ErrSyntax
+}
+
+func syntaxError(pos int, format string, args ...any) *SyntaxError {
+	return &SyntaxError{Pos: pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokWord
+	tokQuoted
+	tokColon
+	tokLParen
+	tokRParen
+	tokLBracket
+	tokRBracket
+	tokAnd
+	tokOr
+	tokNot
+	tokTo
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+	// end is the byte offset after the token.
+	end int
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokEOF:
+		return "end of query"
+	case tokQuoted:
+		return fmt.Sprintf("%q", t.text)
+	default:
+		return t.text
+	}
+}
+
+var punctuation = map[byte]tokenKind{
+	'(': tokLParen,
+	')': tokRParen,
+	'[': tokLBracket,
+	']': tokRBracket,
+	':': tokColon,
+}
+
+var keywords = map[string]tokenKind{
+	"AND": tokAnd,
+	"OR":  tokOr,
+	"NOT": tokNot,
+	"TO":  tokTo,
+}
+
+// lex splits the query into tokens. Within a range, colons are part of the words, so
+// times can be written without quotes.
+func lex(q string) ([]token, error) {
+	var tokens []token
+	inRange := false
+
+	for i := 0; i < len(q); {
+		c := q[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case punctuation[c] != tokEOF && (c != ':' || !inRange):
+			tokens = append(tokens, token{kind: punctuation[c], text: string(c), pos: i, end: i + 1})
+			switch c {
+			case '[':
+				inRange = true
+			case ']':
+				inRange = false
+			}
+			i++
+		case c == '"':
+			text, end, ok := quoted(q, i)
+			if !ok {
+				return nil, syntaxError(i, "unterminated quote")
+			}
+			tokens = append(tokens, token{kind: tokQuoted, text: text, pos: i, end: end})
+			i = end
+		default:
+			end := i
+			for end < len(q) && !strings.ContainsRune(" \t\n\r()[]\"", rune(q[end])) && (inRange || q[end] != ':') {
+				end++
+			}
+			word := q[i:end]
+			kind, ok := keywords[word]
+			if !ok {
+				kind = tokWord
+			}
+			tokens = append(tokens, token{kind: kind, text: word, pos: i, end: end})
+			i = end
+		}
+	}
+
+	return append(tokens, token{kind: tokEOF, pos: len(q), end: len(q)}), nil
+}
+
+// quoted returns the text of the quoted string starting at q[start] and the offset
+// after its closing quote. A backslash escapes the following byte.
+func quoted(q string, start int) (string, int, bool) {
+	var b strings.Builder
+	for i := start + 1; i < len(q); i++ {
+		switch q[i] {
+		case '\\':
+			if i+1 < len(q) {
+				i++
+				b.WriteByte(q[i])
+			}
+		case '"':
+			return b.String(), i + 1, true
+		default:
+			b.WriteByte(q[i])
+		}
+	}
+	return "", 0, false
+}
+
+// entityFields maps the field names of the entities to their types. The empty type
+// matches any type.
+var entityFields = func() map[string]article.EntityType {
+	fields := map[string]article.EntityType{
+		"entity":       "",
+		"per":          article.Person,
+		"loc":          article.Location,
+		"org":          article.Organisation,
+		"organization": article.Organisation,
+	}
+	for _, spec := range article.BuiltinTypes {
+		fields[string(spec.Type)] = spec.Type
+	}
+	return fields
+}()
+
+var textFields = map[string]TextField{
+	"title":   TextTitle,
+	"body":    TextBody,
+	"summary": TextSummary,
+	"author":  TextAuthor,
+}
+
+var timeFields = map[string]article.TimeField{
+	"published": article.TimePublished,
+	"created":   article.TimeCreated,
+}
+
+// Parse parses the query into its syntax tree. An invalid query is rejected with a
+// *SyntaxError.
+func Parse(q string) (Node, error) {
+	tokens, err := lex(q)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	if p.peek().kind == tokEOF {
+		return nil, syntaxError(0, "empty query")
+	}
+
+	n, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		if t.kind == tokRParen {
+			return nil, syntaxError(t.pos, "unmatched closing parenthesis")
+		}
+		return nil, syntaxError(t.pos, "unexpected %s", t)
+	}
+
+	return n, nil
+}
+
+type parser struct {
+	tokens []token
+	i      int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.i]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.i]
+	if t.kind != tokEOF {
+		p.i++
+	}
+	return t
+}
+
+// parseOr parses: and {"OR" and}.
+func (p *parser) parseOr() (Node, error) {
+	first, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := []Node{first}
+	for p.peek().kind == tokOr {
+		p.next()
+		n, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, n)
+	}
+
+	if len(nodes) == 1 {
+		return first, nil
+	}
+	return &Or{Nodes: nodes, Position: first.Pos()}, nil
+}
+
+// parseAnd parses: unary {["AND"] unary}.
+func (p *parser) parseAnd() (Node, error) {
+	first, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := []Node{first}
+	for {
+		switch p.peek().kind {
+		case tokAnd:
+			p.next()
+		case tokWord, tokQuoted, tokTo, tokNot, tokLParen:
+		default:
+			if len(nodes) == 1 {
+				return first, nil
+			}
+			return &And{Nodes: nodes, Position: first.Pos()}, nil
+		}
+
+		n, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, n)
+	}
+}
+
+// parseUnary parses: "NOT" unary | primary.
+func (p *parser) parseUnary() (Node, error) {
+	if t := p.peek(); t.kind == tokNot {
+		p.next()
+		n, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &Not{Node: n, Position: t.pos}, nil
+	}
+	return p.parsePrimary()
+}
+
+// parsePrimary parses: "(" or ")" | field ":" value | word | phrase, where a value,
+// a word or a phrase can be followed by a fuzziness.
+func (p *parser) parsePrimary() (Node, error) {
+	t := p.next()
+	switch t.kind {
+	case tokLParen:
+		n, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek().kind != tokRParen {
+			return nil, syntaxError(t.pos, "unclosed parenthesis")
+		}
+		p.next()
+		return n, nil
+	case tokWord:
+		if colon := p.peek(); colon.kind == tokColon && colon.pos == t.end {
+			p.next()
+			return p.parseField(t)
+		}
+		fallthrough
+	case tokQuoted, tokTo:
+		value, distance, err := p.fuzzy(t)
+		if err != nil {
+			return nil, err
+		}
+		return &Text{Value: value, Fuzziness: distance, Position: t.pos}, nil
+	case tokEOF:
+		return nil, syntaxError(t.pos, "unexpected end of query, want a term")
+	case tokRParen:
+		return nil, syntaxError(t.pos, "unmatched closing parenthesis")
+	default:
+		return nil, syntaxError(t.pos, "unexpected %s, want a term", t)
+	}
+}
+
+// parseField parses the value of the field.
+func (p *parser) parseField(field token) (Node, error) {
+	name := strings.ToLower(field.text)
+
+	if f, ok := timeFields[name]; ok {
+		return p.parseTime(field, f)
+	}
+
+	value := p.peek()
+	if value.kind != tokWord && value.kind != tokQuoted && value.kind != tokTo {
+		return nil, syntaxError(value.pos, "missing value of field %q", field.text)
+	}
+
+	f, isText := textFields[name]
+	typ, isEntity := entityFields[name]
+	if !isText && !isEntity {
+		return nil, syntaxError(field.pos, "unknown field %q", field.text)
+	}
+
+	p.next()
+	text, distance, err := p.fuzzy(value)
+	if err != nil {
+		return nil, err
+	}
+	if isText {
+		return &Text{Field: f, Value: text, Fuzziness: distance, Position: field.pos}, nil
+	}
+	return &Entity{Type: typ, Name: text, Fuzziness: distance, Position: field.pos}, nil
+}
+
+// fuzzy splits the fuzziness off the value t, e.g. scholz~1. The fuzziness of a
+// quoted value follows its closing quote. A ~ without a number allows the
+// analysis.MaxDistance.
+func (p *parser) fuzzy(t token) (string, int, error) {
+	value, suffix, at := t.text, "", -1
+	switch t.kind {
+	case tokWord:
+		if i := strings.LastIndexByte(t.text, '~'); i >= 0 {
+			value, suffix, at = t.text[:i], t.text[i:], t.pos+i
+		}
+	case tokQuoted:
+		if next := p.peek(); next.kind == tokWord && next.pos == t.end && strings.HasPrefix(next.text, "~") {
+			p.next()
+			suffix, at = next.text, next.pos
+		}
+	}
+	if at < 0 {
+		return value, 0, nil
+	}
+
+	if suffix == "~" {
+		return value, analysis.MaxDistance, nil
+	}
+	if len(suffix) != 2 || suffix[1] < '0' || suffix[1] > '0'+analysis.MaxDistance {
+		return "", 0, syntaxError(at, "invalid fuzziness %q, want ~ or ~0 to ~%d", suffix, analysis.MaxDistance)
+	}
+	return value, int(suffix[1] - '0'), nil
+}
+
+// parseTime parses a range "[" bound "TO" bound "]" or a single time. A single date
+// matches the whole day.
+func (p *parser) parseTime(field token, f article.TimeField) (Node, error) {
+	t := p.next()
+	switch t.kind {
+	case tokWord, tokQuoted:
+		from, err := parseBound(t, false)
+		if err != nil {
+			return nil, err
+		}
+		to, err := parseBound(t, true)
+		if err != nil {
+			return nil, err
+		}
+		if from.IsZero() {
+			return nil, syntaxError(t.pos, "open bound outside of a range")
+		}
+		return &Range{Field: f, From: from, To: to, Position: field.pos}, nil
+	case tokLBracket:
+	default:
+		return nil, syntaxError(t.pos, "missing value of field %q", field.text)
+	}
+
+	lower := p.next()
+	if lower.kind != tokWord && lower.kind != tokQuoted {
+		return nil, syntaxError(lower.pos, "unexpected %s, want the lower bound", lower)
+	}
+	if to := p.next(); to.kind != tokTo {
+		return nil, syntaxError(to.pos, "unexpected %s, want TO", to)
+	}
+	upper := p.next()
+	if upper.kind != tokWord && upper.kind != tokQuoted {
+		return nil, syntaxError(upper.pos, "unexpected %s, want the upper bound", upper)
+	}
+	if end := p.next(); end.kind != tokRBracket {
+		return nil, syntaxError(end.pos, "unexpected %s, want ]", end)
+	}
+
+	from, err := parseBound(lower, false)
+	if err != nil {
+		return nil, err
+	}
+	to, err := parseBound(upper, true)
+	if err != nil {
+		return nil, err
+	}
+	if !from.IsZero() && !to.IsZero() && !from.Before(to) {
+		return nil, syntaxError(t.pos, "empty range")
+	}
+
+	return &Range{Field: f, From: from, To: to, Position: field.pos}, nil
+}
+
+// parseBound parses a bound of an inclusive range as date or RFC3339 time. As the
+// Range is half-open, an upper bound is moved after the day or the instant. The
+// bound * is open and results in the zero time.
+func parseBound(t token, upper bool) (time.Time, error) {
+	if t.text == "*" && t.kind == tokWord {
+		return time.Time{}, nil
+	}
+
+	if d, err := time.Parse(time.DateOnly, t.text); err == nil {
+		if upper {
+			return d.AddDate(0, 0, 1), nil
+		}
+		return d, nil
+	}
+	if ts, err := time.Parse(time.RFC3339Nano, t.text); err == nil {
+		if upper {
+			return ts.UTC().Add(time.Nanosecond), nil
+		}
+		return ts.UTC(), nil
+	}
+
+	return time.Time{}, syntaxError(t.pos, "invalid time %q, want a date or RFC3339 time", t.text)
+}
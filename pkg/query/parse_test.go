@@ -0,0 +1,190 @@
+package query
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		q    string
+		want string
+	}{
+		{
+			name: "word",
+			q:    "energy",
+			want: `"energy"`,
+		},
+		{
+			name: "compound query",
+			q:    `person:"Olaf Scholz" AND org:EU AND published:[2023-01-01 TO 2023-06-30] AND "energy crisis"`,
+			want: `(person:"Olaf Scholz" AND organisation:"EU" AND published:[2023-01-01T00:00:00Z TO 2023-07-01T00:00:00Z) AND "energy crisis")`,
+		},
+		{
+			name: "implicit and",
+			q:    `energy crisis`,
+			want: `("energy" AND "crisis")`,
+		},
+		{
+			name: "or binds weaker than and",
+			q:    `a b OR c`,
+			want: `(("a" AND "b") OR "c")`,
+		},
+		{
+			name: "parentheses and not",
+			q:    `NOT (loc:Berlin OR location:Hamburg) title:storm`,
+			want: `(NOT (location:"Berlin" OR location:"Hamburg") AND title:"storm")`,
+		},
+		{
+			name: "open range with times",
+			q:    `created:[2023-01-01T10:00:00+02:00 TO *]`,
+			want: `created:[2023-01-01T08:00:00Z TO *)`,
+		},
+		{
+			name: "single day",
+			q:    `published:2023-03-01`,
+			want: `published:[2023-03-01T00:00:00Z TO 2023-03-02T00:00:00Z)`,
+		},
+		{
+			name: "any entity and field case",
+			q:    `Entity:"NATO" Author:doe`,
+			want: `(entity:"NATO" AND author:"doe")`,
+		},
+		{
+			name: "fuzzy words",
+			q:    `scholtz~ title:enrgy~1`,
+			want: `("scholtz"~2 AND title:"enrgy"~1)`,
+		},
+		{
+			name: "fuzzy phrase and name",
+			q:    `"energy crsis"~1 person:"Olaf Scholtz"~`,
+			want: `("energy crsis"~1 AND person:"Olaf Scholtz"~2)`,
+		},
+		{
+			name: "escaped quote and lowercase keywords",
+			q:    `"say \"no\"" and to`,
+			want: `("say \"no\"" AND "and" AND "to")`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.q)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if got.String() != tt.want {
+				t.Errorf("Parse() = %v, want %v", got.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestParse_errors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		q       string
+		wantPos int
+		wantMsg string
+	}{
+		{
+			name:    "empty",
+			q:       "  ",
+			wantPos: 0,
+			wantMsg: "empty query",
+		},
+		{
+			name:    "unterminated quote",
+			q:       `energy "crisis`,
+			wantPos: 7,
+			wantMsg: "unterminated quote",
+		},
+		{
+			name:    "unknown field",
+			q:       `energy weapon:tank`,
+			wantPos: 7,
+			wantMsg: `unknown field "weapon"`,
+		},
+		{
+			name:    "missing value",
+			q:       `person: AND x`,
+			wantPos: 8,
+			wantMsg: `missing value of field "person"`,
+		},
+		{
+			name:    "dangling operator",
+			q:       `energy AND`,
+			wantPos: 10,
+			wantMsg: "unexpected end of query, want a term",
+		},
+		{
+			name:    "unclosed parenthesis",
+			q:       `a (b OR c`,
+			wantPos: 2,
+			wantMsg: "unclosed parenthesis",
+		},
+		{
+			name:    "unmatched parenthesis",
+			q:       `a b)`,
+			wantPos: 3,
+			wantMsg: "unmatched closing parenthesis",
+		},
+		{
+			name:    "invalid date",
+			q:       `published:[2023-13-01 TO *]`,
+			wantPos: 11,
+			wantMsg: `invalid time "2023-13-01", want a date or RFC3339 time`,
+		},
+		{
+			name:    "missing to",
+			q:       `published:[2023-01-01 2023-02-01]`,
+			wantPos: 22,
+			wantMsg: "unexpected 2023-02-01, want TO",
+		},
+		{
+			name:    "empty range",
+			q:       `published:[2023-02-01 TO 2023-01-01]`,
+			wantPos: 10,
+			wantMsg: "empty range",
+		},
+		{
+			name:    "open single time",
+			q:       `created:*`,
+			wantPos: 8,
+			wantMsg: "open bound outside of a range",
+		},
+		{
+			name:    "invalid fuzziness",
+			q:       `energy scholz~3`,
+			wantPos: 13,
+			wantMsg: `invalid fuzziness "~3", want ~ or ~0 to ~2`,
+		},
+		{
+			name:    "invalid fuzziness of phrase",
+			q:       `"olaf scholz"~x`,
+			wantPos: 13,
+			wantMsg: `invalid fuzziness "~x", want ~ or ~0 to ~2`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.q)
+			if !errors.Is(err, ErrSyntax) {
+				t.Fatalf("Parse() error = %v, want %v", err, ErrSyntax)
+			}
+			var serr *SyntaxError
+			if !errors.As(err, &serr) {
+				t.Fatalf("Parse() error = %T, want *SyntaxError", err)
+			}
+			if serr.Pos != tt.wantPos || serr.Msg != tt.wantMsg {
+				t.Errorf("Parse() error = %q at %d, want %q at %d", serr.Msg, serr.Pos, tt.wantMsg, tt.wantPos)
+			}
+		})
+	}
+}
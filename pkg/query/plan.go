@@ -0,0 +1,427 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Br0ce/articleDB/pkg/analysis"
+	"github.com/Br0ce/articleDB/pkg/article"
+)
+
+// Set is a set of article IDs.
+type Set map[string]struct{}
+
+// Index is the index of a store, a Plan runs against, e.g. the inmem.Article. The
+// returned sets are owned by the caller.
+type Index interface {
+	// Phrase returns the articles containing the terms in the given order in the
+	// field. TextAny matches the title and the body. The terms are the lowercased
+	// words of the query, the index analyzes them like the text of the articles,
+	// e.g. stems them in the language of each article. A positive distance matches
+	// words within that edit distance of the terms, see analysis.Fuzziness.
+	Phrase(field TextField, terms []string, distance int) Set
+	// Entity returns the articles mentioning the named entity, ignoring case. An
+	// empty type matches entities of any type. A positive distance matches names
+	// within that edit distance of the name.
+	Entity(t article.EntityType, name string, distance int) Set
+	// Range returns the articles with a time in [from, to).
+	Range(field article.TimeField, from, to time.Time) Set
+	// All returns all articles.
+	All() Set
+}
+
+// Plan is the execution plan of a query.
+type Plan struct {
+	root step
+}
+
+type Option func(c *compiler)
+
+// WithSynonyms expands the texts and the names of the query by their synonyms.
+func WithSynonyms(s *Synonyms) Option {
+	return func(c *compiler) {
+		c.synonyms = s
+	}
+}
+
+// NewPlan returns the plan of the syntax tree. The conditions of an AND are run
+// ordered by their expected cost, so the cheap and selective lookups of entities
+// and time ranges narrow the result before phrases are matched. Negated conditions
+// are subtracted last. A text without any word is rejected with a *SyntaxError.
+func NewPlan(n Node, opts ...Option) (*Plan, error) {
+	c := &compiler{}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	root, err := c.compile(n)
+	if err != nil {
+		return nil, err
+	}
+	return &Plan{root: root}, nil
+}
+
+// Compile parses the query and returns its plan.
+func Compile(q string, opts ...Option) (*Plan, error) {
+	n, err := Parse(q)
+	if err != nil {
+		return nil, err
+	}
+	return NewPlan(n, opts...)
+}
+
+// Run runs the plan against the index and returns the IDs of the matched articles.
+func (p *Plan) Run(idx Index) Set {
+	return p.root.run(idx)
+}
+
+// Term is a word of a text of a plan.
+type Term struct {
+	// Field is the text field searched for the word.
+	Field TextField
+	Value string
+	// Distance is the maximal edit distance of a matching word.
+	Distance int
+}
+
+// In reports whether the term is searched in field. A term of TextAny is searched
+// in the title and the body.
+func (t Term) In(field TextField) bool {
+	if t.Field == TextAny {
+		return field == TextTitle || field == TextBody
+	}
+	return t.Field == field
+}
+
+// Terms returns the distinct terms of the texts, that are not negated, e.g. to
+// highlight them in the matched articles.
+func (p *Plan) Terms() []Term {
+	var terms []Term
+	seen := make(map[Term]bool)
+	p.root.eachTerm(func(term Term) {
+		if !seen[term] {
+			seen[term] = true
+			terms = append(terms, term)
+		}
+	})
+	return terms
+}
+
+// String describes the steps of the plan in the order they are run.
+func (p *Plan) String() string {
+	return p.root.String()
+}
+
+type step interface {
+	run(idx Index) Set
+	// cost is the expected cost of the step. Lower costs are run first.
+	cost() int
+	// eachTerm calls add with the terms of the texts, that are not negated.
+	eachTerm(add func(term Term))
+	String() string
+}
+
+const (
+	costEntity = 1
+	costRange  = 2
+	costTerm   = 3
+	costPhrase = 4
+	costFuzzy  = 6
+	costAll    = 8
+)
+
+// compiler compiles syntax trees into steps.
+type compiler struct {
+	synonyms *Synonyms
+}
+
+func (c *compiler) compile(n Node) (step, error) {
+	switch n := n.(type) {
+	case *And:
+		s := &andStep{}
+		for _, child := range n.Nodes {
+			if not, ok := child.(*Not); ok {
+				neg, err := c.compile(not.Node)
+				if err != nil {
+					return nil, err
+				}
+				s.neg = append(s.neg, neg)
+				continue
+			}
+			pos, err := c.compile(child)
+			if err != nil {
+				return nil, err
+			}
+			s.pos = append(s.pos, pos)
+		}
+		sort.SliceStable(s.pos, func(i, j int) bool { return s.pos[i].cost() < s.pos[j].cost() })
+		return s, nil
+	case *Or:
+		s := &orStep{}
+		for _, child := range n.Nodes {
+			child, err := c.compile(child)
+			if err != nil {
+				return nil, err
+			}
+			s.steps = append(s.steps, child)
+		}
+		return s, nil
+	case *Not:
+		neg, err := c.compile(n.Node)
+		if err != nil {
+			return nil, err
+		}
+		return &andStep{neg: []step{neg}}, nil
+	case *Text:
+		terms := analysis.Terms(n.Value)
+		if len(terms) == 0 {
+			return nil, syntaxError(n.Position, "%q contains no word", n.Value)
+		}
+		steps := []step{&phraseStep{field: n.Field, terms: terms, distance: n.Fuzziness}}
+		for _, synonym := range c.synonyms.Expand(n.Value) {
+			steps = append(steps, &phraseStep{field: n.Field, terms: analysis.Terms(synonym)})
+		}
+		return either(steps), nil
+	case *Entity:
+		name := strings.TrimSpace(n.Name)
+		if name == "" {
+			return nil, syntaxError(n.Position, "empty entity name")
+		}
+		steps := []step{&entityStep{typ: n.Type, name: name, distance: n.Fuzziness}}
+		for _, synonym := range c.synonyms.Expand(name) {
+			steps = append(steps, &entityStep{typ: n.Type, name: synonym})
+		}
+		return either(steps), nil
+	case *Range:
+		return &rangeStep{field: n.Field, from: n.From, to: n.To}, nil
+	default:
+		return nil, fmt.Errorf("unknown node %T", n)
+	}
+}
+
+// either returns the step matching any of the steps.
+func either(steps []step) step {
+	if len(steps) == 1 {
+		return steps[0]
+	}
+	return &orStep{steps: steps}
+}
+
+type andStep struct {
+	pos []step
+	neg []step
+}
+
+func (s *andStep) run(idx Index) Set {
+	if len(s.pos) == 0 {
+		return subtract(idx.All(), s.neg, idx)
+	}
+
+	result := s.pos[0].run(idx)
+	for _, p := range s.pos[1:] {
+		if len(result) == 0 {
+			return result
+		}
+		result = intersect(result, p.run(idx))
+	}
+	return subtract(result, s.neg, idx)
+}
+
+// subtract removes the IDs matched by the steps from result.
+func subtract(result Set, steps []step, idx Index) Set {
+	for _, s := range steps {
+		if len(result) == 0 {
+			return result
+		}
+		for id := range s.run(idx) {
+			delete(result, id)
+		}
+	}
+	return result
+}
+
+func (s *andStep) cost() int {
+	if len(s.pos) == 0 {
+		return costAll
+	}
+	return s.pos[0].cost()
+}
+
+func (s *andStep) eachTerm(add func(term Term)) {
+	for _, p := range s.pos {
+		p.eachTerm(add)
+	}
+}
+
+func (s *andStep) String() string {
+	parts := make([]string, 0, len(s.pos)+len(s.neg))
+	if len(s.pos) == 0 {
+		parts = append(parts, "all")
+	}
+	for _, p := range s.pos {
+		parts = append(parts, p.String())
+	}
+	for _, n := range s.neg {
+		parts = append(parts, "not "+n.String())
+	}
+	if len(parts) == 1 {
+		return parts[0]
+	}
+	return "and(" + strings.Join(parts, ", ") + ")"
+}
+
+type orStep struct {
+	steps []step
+}
+
+func (s *orStep) run(idx Index) Set {
+	result := make(Set)
+	for _, step := range s.steps {
+		for id := range step.run(idx) {
+			result[id] = struct{}{}
+		}
+	}
+	return result
+}
+
+func (s *orStep) cost() int {
+	c := 0
+	for _, step := range s.steps {
+		c += step.cost()
+	}
+	return min(c, costAll)
+}
+
+func (s *orStep) eachTerm(add func(term Term)) {
+	for _, step := range s.steps {
+		step.eachTerm(add)
+	}
+}
+
+func (s *orStep) String() string {
+	parts := make([]string, 0, len(s.steps))
+	for _, step := range s.steps {
+		parts = append(parts, step.String())
+	}
+	return "or(" + strings.Join(parts, ", ") + ")"
+}
+
+type phraseStep struct {
+	field    TextField
+	terms    []string
+	distance int
+}
+
+func (s *phraseStep) run(idx Index) Set {
+	return idx.Phrase(s.field, s.terms, s.distance)
+}
+
+// cost of a fuzzy step is higher, as every term of the index within the distance
+// is matched.
+func (s *phraseStep) cost() int {
+	switch {
+	case s.distance > 0:
+		return costFuzzy
+	case len(s.terms) == 1:
+		return costTerm
+	default:
+		return costPhrase
+	}
+}
+
+func (s *phraseStep) eachTerm(add func(term Term)) {
+	for _, term := range s.terms {
+		add(Term{Field: s.field, Value: term, Distance: s.distance})
+	}
+}
+
+func (s *phraseStep) String() string {
+	field := string(s.field)
+	if s.field == TextAny {
+		field = "text"
+	}
+	return fmt.Sprintf("%s(%s)%s", field, strings.Join(s.terms, " "), fuzziness(s.distance))
+}
+
+type entityStep struct {
+	typ      article.EntityType
+	name     string
+	distance int
+}
+
+func (s *entityStep) run(idx Index) Set {
+	return idx.Entity(s.typ, s.name, s.distance)
+}
+
+func (s *entityStep) cost() int {
+	if s.distance > 0 {
+		return costFuzzy
+	}
+	return costEntity
+}
+
+func (s *entityStep) eachTerm(add func(term Term)) {}
+
+func (s *entityStep) String() string {
+	typ := string(s.typ)
+	if s.typ == "" {
+		typ = "entity"
+	}
+	return fmt.Sprintf("%s(%s)%s", typ, s.name, fuzziness(s.distance))
+}
+
+type rangeStep struct {
+	field    article.TimeField
+	from, to time.Time
+}
+
+func (s *rangeStep) run(idx Index) Set {
+	return idx.Range(s.field, s.from, s.to)
+}
+
+func (s *rangeStep) cost() int {
+	return costRange
+}
+
+func (s *rangeStep) eachTerm(add func(term Term)) {}
+
+func (s *rangeStep) String() string {
+	return fmt.Sprintf("%s[%s, %s)", timeFieldName(s.field), bound(s.from), bound(s.to))
+}
+
+// intersect returns the IDs in a and b. It iterates the smaller set.
+func intersect(a, b Set) Set {
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	result := make(Set, len(a))
+	for id := range a {
+		if _, ok := b[id]; ok {
+			result[id] = struct{}{}
+		}
+	}
+	return result
+}
+
+// Match is a matched term in a text field. Start and End are the byte offsets of the
+// term, End is exclusive.
+type Match struct {
+	Start int
+	End   int
+}
+
+// Hit is an article matched by a search.
+type Hit struct {
+	Article article.Article
+	// Matches are the offsets of the terms of the plan in the title, the body and
+	// the summary of the article ordered by offset.
+	Matches map[TextField][]Match
+}
+
+// Searcher runs plans against the indexes of a store, e.g. the inmem.Article.
+type Searcher interface {
+	Search(ctx context.Context, p *Plan, limit int) ([]Hit, error)
+}
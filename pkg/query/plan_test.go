@@ -0,0 +1,265 @@
+package query
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Br0ce/articleDB/pkg/analysis"
+	"github.com/Br0ce/articleDB/pkg/article"
+)
+
+// testIndex is an Index over a few articles, that records the lookups.
+type testIndex struct {
+	articles map[string]article.Article
+	calls    []string
+}
+
+func (x *testIndex) match(call string, fn func(ar article.Article) bool) Set {
+	x.calls = append(x.calls, call)
+	result := make(Set)
+	for id, ar := range x.articles {
+		if fn(ar) {
+			result[id] = struct{}{}
+		}
+	}
+	return result
+}
+
+// Phrase matches a phrase as a substring. With a distance, it matches a single
+// term within the distance of any word.
+func (x *testIndex) Phrase(field TextField, terms []string, distance int) Set {
+	phrase := strings.Join(terms, " ")
+	return x.match("phrase", func(ar article.Article) bool {
+		text := strings.ToLower(ar.Title + " " + ar.Body)
+		if field == TextAuthor {
+			text = strings.ToLower(ar.Author)
+		}
+		if distance == 0 {
+			return strings.Contains(text, phrase)
+		}
+		for _, word := range analysis.Terms(text) {
+			if analysis.EditDistance(word, phrase, distance) <= distance {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+func (x *testIndex) Entity(t article.EntityType, name string, distance int) Set {
+	return x.match("entity", func(ar article.Article) bool {
+		for _, e := range ar.NER.Entities {
+			d := analysis.EditDistance(strings.ToLower(e.Name), strings.ToLower(name), distance)
+			if (t == "" || e.Type == t) && d <= distance {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+func (x *testIndex) Range(field article.TimeField, from, to time.Time) Set {
+	return x.match("range", func(ar article.Article) bool {
+		t := field.Time(ar)
+		return !t.Before(from) && (to.IsZero() || t.Before(to))
+	})
+}
+
+func (x *testIndex) All() Set {
+	return x.match("all", func(ar article.Article) bool { return true })
+}
+
+func TestPlan_Run(t *testing.T) {
+	t.Parallel()
+
+	day := time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)
+	articles := map[string]article.Article{
+		"1": {
+			Title:     "Energy crisis talks",
+			Body:      "Scholz met the EU.",
+			Published: day,
+			NER: article.NER{Entities: append(article.EntitiesOf(article.Person, "Olaf Scholz"),
+				article.EntitiesOf(article.Organisation, "EU")...)},
+		},
+		"2": {
+			Title:     "Energy crisis deepens",
+			Body:      "Prices rise.",
+			Published: day.AddDate(1, 0, 0),
+			NER:       article.NER{Entities: article.EntitiesOf(article.Organisation, "EU")},
+		},
+		"3": {
+			Title:     "Storm",
+			Body:      "Heavy rain in Berlin.",
+			Published: day,
+			NER:       article.NER{Entities: article.EntitiesOf(article.Location, "Berlin")},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		q         string
+		want      []string
+		wantPlan  string
+		wantCalls []string
+	}{
+		{
+			name:      "compound query",
+			q:         `"energy crisis" AND published:[2023-01-01 TO 2023-06-30] AND person:"Olaf Scholz" AND org:EU`,
+			want:      []string{"1"},
+			wantPlan:  "and(person(Olaf Scholz), organisation(EU), published[2023-01-01T00:00:00Z, 2023-07-01T00:00:00Z), text(energy crisis))",
+			wantCalls: []string{"entity", "entity", "range", "phrase"},
+		},
+		{
+			name:      "empty intersection stops",
+			q:         `berlin AND org:EU AND location:Berlin`,
+			want:      nil,
+			wantPlan:  "and(organisation(EU), location(Berlin), text(berlin))",
+			wantCalls: []string{"entity", "entity"},
+		},
+		{
+			name:      "or",
+			q:         `storm OR person:"olaf scholz"`,
+			want:      []string{"1", "3"},
+			wantPlan:  "or(text(storm), person(olaf scholz))",
+			wantCalls: []string{"phrase", "entity"},
+		},
+		{
+			name:      "not",
+			q:         `energy NOT person:"Olaf Scholz"`,
+			want:      []string{"2"},
+			wantPlan:  "and(text(energy), not person(Olaf Scholz))",
+			wantCalls: []string{"phrase", "entity"},
+		},
+		{
+			name:      "only not",
+			q:         `NOT energy`,
+			want:      []string{"3"},
+			wantPlan:  "and(all, not text(energy))",
+			wantCalls: []string{"all", "phrase"},
+		},
+		{
+			name:      "fuzzy",
+			q:         `person:"Olaf Scholtz"~1 OR storn~1`,
+			want:      []string{"1", "3"},
+			wantPlan:  "or(person(Olaf Scholtz)~1, text(storn)~1)",
+			wantCalls: []string{"entity", "phrase"},
+		},
+		{
+			name:      "fuzzy runs after exact lookups",
+			q:         `crsis~ AND energy AND org:EU`,
+			want:      []string{"1", "2"},
+			wantPlan:  "and(organisation(EU), text(energy), text(crsis)~2)",
+			wantCalls: []string{"entity", "phrase", "phrase"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := Compile(tt.q)
+			if err != nil {
+				t.Fatalf("Compile() error = %v", err)
+			}
+			if p.String() != tt.wantPlan {
+				t.Errorf("Plan = %v, want %v", p.String(), tt.wantPlan)
+			}
+
+			idx := &testIndex{articles: articles}
+			var got []string
+			for id := range p.Run(idx) {
+				got = append(got, id)
+			}
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Plan.Run() = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(idx.calls, tt.wantCalls) {
+				t.Errorf("index calls = %v, want %v", idx.calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestCompile_synonyms(t *testing.T) {
+	t.Parallel()
+
+	articles := map[string]article.Article{
+		"1": {Title: "Storm", NER: article.NER{Entities: article.EntitiesOf(article.Organisation, "EU")}},
+		"2": {Title: "Tempest", NER: article.NER{Entities: article.EntitiesOf(article.Organisation, "European Union")}},
+		"3": {Title: "Rain"},
+	}
+	synonyms := NewSynonyms([]string{"storm", "tempest"}, []string{"EU", "European Union"})
+
+	tests := []struct {
+		name      string
+		q         string
+		want      []string
+		wantPlan  string
+		wantTerms []Term
+	}{
+		{
+			name:      "word",
+			q:         `tempest~1`,
+			want:      []string{"1", "2"},
+			wantPlan:  "or(text(tempest)~1, text(storm))",
+			wantTerms: []Term{{Value: "tempest", Distance: 1}, {Value: "storm"}},
+		},
+		{
+			name:     "entity name",
+			q:        `org:"european union"`,
+			want:     []string{"1", "2"},
+			wantPlan: "or(organisation(european union), organisation(eu))",
+		},
+		{
+			name:      "field",
+			q:         `title:storm`,
+			want:      []string{"1", "2"},
+			wantPlan:  "or(title(storm), title(tempest))",
+			wantTerms: []Term{{Field: TextTitle, Value: "storm"}, {Field: TextTitle, Value: "tempest"}},
+		},
+		{
+			name:      "no synonyms",
+			q:         `rain`,
+			want:      []string{"3"},
+			wantPlan:  "text(rain)",
+			wantTerms: []Term{{Value: "rain"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := Compile(tt.q, WithSynonyms(synonyms))
+			if err != nil {
+				t.Fatalf("Compile() error = %v", err)
+			}
+			if p.String() != tt.wantPlan {
+				t.Errorf("Plan = %v, want %v", p.String(), tt.wantPlan)
+			}
+			if !reflect.DeepEqual(p.Terms(), tt.wantTerms) {
+				t.Errorf("Plan.Terms() = %v, want %v", p.Terms(), tt.wantTerms)
+			}
+
+			var got []string
+			for id := range p.Run(&testIndex{articles: articles}) {
+				got = append(got, id)
+			}
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Plan.Run() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompile_errors(t *testing.T) {
+	t.Parallel()
+
+	_, err := Compile(`energy AND "--"`)
+	var serr *SyntaxError
+	if !errors.As(err, &serr) || serr.Pos != 11 {
+		t.Errorf("Compile() error = %v, want a syntax error at offset 11", err)
+	}
+}
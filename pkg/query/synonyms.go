@@ -0,0 +1,89 @@
+package query
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/Br0ce/articleDB/pkg/analysis"
+)
+
+var ErrInvalidSynonyms = errors.New("invalid synonyms")
+
+// Synonyms are groups of equivalent words or phrases, e.g. "EU" and "European
+// Union". A text or a name of a query matches the articles matching any of its
+// synonyms.
+type Synonyms struct {
+	// groups maps the normalized words or phrases to their synonyms.
+	groups map[string][]string
+}
+
+// NewSynonyms returns the synonyms of the groups. A word or a phrase in several
+// groups is equivalent to the members of all of them.
+func NewSynonyms(groups ...[]string) *Synonyms {
+	s := &Synonyms{groups: make(map[string][]string)}
+	for _, group := range groups {
+		var keys []string
+		for _, member := range group {
+			if key := normalizeSynonym(member); key != "" {
+				keys = append(keys, key)
+			}
+		}
+		for _, key := range keys {
+			for _, other := range keys {
+				if other != key && !slices.Contains(s.groups[key], other) {
+					s.groups[key] = append(s.groups[key], other)
+				}
+			}
+		}
+	}
+	return s
+}
+
+// ParseSynonyms reads one group of synonyms per line, the members separated by
+// commas, e.g. "EU, European Union". Empty lines and lines starting with # are
+// skipped. A group with less than two members is rejected with
+// ErrInvalidSynonyms.
+func ParseSynonyms(r io.Reader) (*Synonyms, error) {
+	var groups [][]string
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		var group []string
+		for _, member := range strings.Split(text, ",") {
+			if normalizeSynonym(member) != "" {
+				group = append(group, strings.TrimSpace(member))
+			}
+		}
+		if len(group) < 2 {
+			return nil, fmt.Errorf("line %d has less than two synonyms, %w", line, ErrInvalidSynonyms)
+		}
+		groups = append(groups, group)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return NewSynonyms(groups...), nil
+}
+
+// Expand returns the synonyms of the word or the phrase without itself. The
+// synonyms are lowercased.
+func (s *Synonyms) Expand(text string) []string {
+	if s == nil {
+		return nil
+	}
+	return s.groups[normalizeSynonym(text)]
+}
+
+// normalizeSynonym returns the lowercased words of the text separated by spaces.
+func normalizeSynonym(text string) string {
+	return strings.Join(analysis.Terms(text), " ")
+}
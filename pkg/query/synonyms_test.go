@@ -0,0 +1,67 @@
+package query
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseSynonyms(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		input   string
+		want    map[string][]string
+		wantErr error
+	}{
+		{
+			name: "groups",
+			input: "# organisations\n" +
+				"EU, European Union\n" +
+				"\n" +
+				"storm, tempest,gale\n" +
+				"EU, Europäische Union\n",
+			want: map[string][]string{
+				"EU":                 {"european union", "europäische union"},
+				"european  union":    {"eu"},
+				"Europäische Union":  {"eu"},
+				"gale":               {"storm", "tempest"},
+				"unknown":            nil,
+				"European Union Law": nil,
+			},
+		},
+		{
+			name:    "single member",
+			input:   "EU, European Union\nstorm, --\n",
+			wantErr: ErrInvalidSynonyms,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := ParseSynonyms(strings.NewReader(tt.input))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParseSynonyms() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			for text, want := range tt.want {
+				if got := s.Expand(text); !reflect.DeepEqual(got, want) {
+					t.Errorf("Synonyms.Expand(%q) = %q, want %q", text, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestSynonyms_Expand_nil(t *testing.T) {
+	t.Parallel()
+
+	var s *Synonyms
+	if got := s.Expand("EU"); got != nil {
+		t.Errorf("Synonyms.Expand() = %q, want nil", got)
+	}
+}
@@ -0,0 +1,207 @@
+package readability
+
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+
+	"github.com/Br0ce/articleDB/pkg/article"
+)
+
+// articleTypes are the schema.org types of JSON-LD objects describing an article.
+var articleTypes = map[string]bool{
+	"Article":              true,
+	"NewsArticle":          true,
+	"ReportageNewsArticle": true,
+	"AnalysisNewsArticle":  true,
+	"OpinionNewsArticle":   true,
+	"BlogPosting":          true,
+}
+
+// metadata reads the metadata of the page. JSON-LD takes precedence over OpenGraph,
+// which takes precedence over other meta tags.
+func metadata(root *html.Node) Document {
+	var ld Document
+	meta := make(map[string]string)
+	var title, canonical, timeTag string
+
+	walk(root, func(n *html.Node) {
+		switch n.DataAtom {
+		case atom.Script:
+			if strings.Contains(attr(n, "type"), "ld+json") && n.FirstChild != nil {
+				merge(&ld, jsonLD(n.FirstChild.Data))
+			}
+		case atom.Meta:
+			key := strings.ToLower(attr(n, "property"))
+			if key == "" {
+				key = strings.ToLower(attr(n, "name"))
+			}
+			if _, ok := meta[key]; !ok && key != "" {
+				meta[key] = strings.TrimSpace(attr(n, "content"))
+			}
+		case atom.Title:
+			if title == "" {
+				title = text(n)
+			}
+		case atom.Link:
+			if strings.EqualFold(attr(n, "rel"), "canonical") && canonical == "" {
+				canonical = attr(n, "href")
+			}
+		case atom.Time:
+			if timeTag == "" {
+				timeTag = attr(n, "datetime")
+			}
+		}
+	})
+
+	doc := ld
+	merge(&doc, Document{
+		Title:     first(meta["og:title"], meta["twitter:title"], title),
+		Author:    first(meta["article:author"], meta["author"], meta["byl"], meta["dc.creator"]),
+		Published: first(meta["article:published_time"], meta["og:article:published_time"], meta["pubdate"], meta["publishdate"], meta["date"], meta["dc.date"], timeTag),
+		Canonical: first(canonical, meta["og:url"]),
+	})
+
+	return doc
+}
+
+// merge sets all empty fields of dst to the values of src.
+func merge(dst *Document, src Document) {
+	dst.Title = first(dst.Title, src.Title)
+	dst.Author = first(dst.Author, src.Author)
+	dst.Published = first(dst.Published, src.Published)
+	dst.Canonical = first(dst.Canonical, src.Canonical)
+	dst.Body = first(dst.Body, src.Body)
+}
+
+func first(values ...string) string {
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// jsonLD reads the article metadata from a JSON-LD script. The script may contain
+// a single object, a list of objects or a graph.
+func jsonLD(data string) Document {
+	var raw interface{}
+	if err := json.Unmarshal([]byte(data), &raw); err != nil {
+		return Document{}
+	}
+
+	var doc Document
+	var visit func(v interface{})
+	visit = func(v interface{}) {
+		switch v := v.(type) {
+		case []interface{}:
+			for _, item := range v {
+				visit(item)
+			}
+		case map[string]interface{}:
+			if graph, ok := v["@graph"]; ok {
+				visit(graph)
+			}
+			if !isArticle(v["@type"]) {
+				return
+			}
+			merge(&doc, Document{
+				Title:     str(v["headline"]),
+				Author:    names(v["author"]),
+				Published: str(v["datePublished"]),
+				Canonical: first(str(v["url"]), str(v["mainEntityOfPage"])),
+				Body:      str(v["articleBody"]),
+			})
+		}
+	}
+	visit(raw)
+
+	return doc
+}
+
+func isArticle(t interface{}) bool {
+	switch t := t.(type) {
+	case string:
+		return articleTypes[t]
+	case []interface{}:
+		for _, item := range t {
+			if isArticle(item) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// names returns the names of a JSON-LD person, a list of persons or a plain string.
+func names(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case map[string]interface{}:
+		return str(v["name"])
+	case []interface{}:
+		var all []string
+		for _, item := range v {
+			if name := names(item); name != "" {
+				all = append(all, name)
+			}
+		}
+		return strings.Join(all, ", ")
+	}
+	return ""
+}
+
+func str(v interface{}) string {
+	s, _ := v.(string)
+	return strings.TrimSpace(s)
+}
+
+// dateLayouts are the date formats found in the metadata of pages in the wild.
+var dateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05Z0700",
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+	time.RFC1123Z,
+	time.RFC1123,
+}
+
+// ParseDate parses a publication date as found in the metadata of a page. The bool
+// is false, if the date has an unknown format.
+func ParseDate(s string) (time.Time, bool) {
+	s = strings.TrimSpace(s)
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.UTC(), true
+		}
+	}
+	return time.Time{}, false
+}
+
+// Article maps the document to an article.Article. The addr is the address, the page
+// has been fetched from. It is replaced by the canonical url of the page, if stated.
+func (d Document) Article(addr url.URL) article.Article {
+	if d.Canonical != "" {
+		if canonical, err := addr.Parse(d.Canonical); err == nil && canonical.Host != "" {
+			addr = *canonical
+		}
+	}
+
+	published, _ := ParseDate(d.Published)
+
+	return article.Article{
+		Title:     d.Title,
+		Addr:      addr,
+		Author:    d.Author,
+		Published: published,
+		Body:      d.Body,
+	}
+}
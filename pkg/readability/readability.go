@@ -0,0 +1,294 @@
+// Package readability extracts the main article text and its metadata from an html
+// page. Navigation, ads and other boilerplate are stripped. Metadata is read from
+// JSON-LD, OpenGraph and html meta tags.
+package readability
+
+import (
+	"errors"
+	"io"
+	"math"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+var ErrNoContent = errors.New("no article content found")
+
+// minParagraphLen is the minimal length of a paragraph to be scored as content.
+const minParagraphLen = 25
+
+var (
+	// unlikely matches class names and ids of boilerplate elements.
+	unlikely = regexp.MustCompile(`(?i)comment|sidebar|footer|masthead|nav|menu|share|social|advert|\bads?\b|ad-|sponsor|promo|related|recommend|subscribe|newsletter|cookie|consent|banner|popup|modal|breadcrumb|pagination`)
+	// likely matches class names and ids of content elements.
+	likely = regexp.MustCompile(`(?i)article|body|content|entry|main|post|story|text`)
+
+	spaces = regexp.MustCompile(`\s+`)
+)
+
+// removedTags are never part of the content.
+var removedTags = map[atom.Atom]bool{
+	atom.Script:   true,
+	atom.Style:    true,
+	atom.Noscript: true,
+	atom.Nav:      true,
+	atom.Header:   true,
+	atom.Footer:   true,
+	atom.Aside:    true,
+	atom.Form:     true,
+	atom.Iframe:   true,
+	atom.Svg:      true,
+	atom.Button:   true,
+	atom.Figure:   true,
+	atom.Template: true,
+}
+
+// blockTags are the elements whose text forms a block of the extracted body.
+var blockTags = map[atom.Atom]bool{
+	atom.P:          true,
+	atom.H2:         true,
+	atom.H3:         true,
+	atom.H4:         true,
+	atom.H5:         true,
+	atom.H6:         true,
+	atom.Li:         true,
+	atom.Blockquote: true,
+	atom.Pre:        true,
+}
+
+// Document is the extracted article.
+type Document struct {
+	Title  string
+	Author string
+	// Published is the raw publication date as found in the page. Use ParseDate to
+	// convert it.
+	Published string
+	// Canonical is the canonical url of the page, if stated.
+	Canonical string
+	Body      string
+}
+
+// Extract parses the html page from r and returns the main article.
+// If no main content can be found, an ErrNoContent is returned.
+func Extract(r io.Reader) (Document, error) {
+	root, err := html.Parse(r)
+	if err != nil {
+		return Document{}, err
+	}
+
+	doc := metadata(root)
+
+	body := content(root)
+	if body == "" {
+		body = doc.Body
+	}
+	if body == "" {
+		return Document{}, ErrNoContent
+	}
+	doc.Body = body
+
+	return doc, nil
+}
+
+// content strips the boilerplate from the page and returns the text of the best
+// scored content element.
+func content(root *html.Node) string {
+	body := find(root, atom.Body)
+	if body == nil {
+		return ""
+	}
+
+	prune(body)
+
+	scores := make(map[*html.Node]float64)
+	walk(body, func(n *html.Node) {
+		if n.DataAtom != atom.P && n.DataAtom != atom.Pre {
+			return
+		}
+
+		txt := text(n)
+		if len(txt) < minParagraphLen {
+			return
+		}
+
+		score := 1 + float64(strings.Count(txt, ",")) + math.Min(float64(len(txt))/100, 3)
+
+		if parent := n.Parent; parent != nil {
+			if _, ok := scores[parent]; !ok {
+				scores[parent] = initialScore(parent)
+			}
+			scores[parent] += score
+
+			if grand := parent.Parent; grand != nil {
+				if _, ok := scores[grand]; !ok {
+					scores[grand] = initialScore(grand)
+				}
+				scores[grand] += score / 2
+			}
+		}
+	})
+
+	var best *html.Node
+	var bestScore float64
+	for n, score := range scores {
+		score *= 1 - linkDensity(n)
+		if best == nil || score > bestScore {
+			best, bestScore = n, score
+		}
+	}
+	if best == nil {
+		return ""
+	}
+
+	var blocks []string
+	walk(best, func(n *html.Node) {
+		if !blockTags[n.DataAtom] || hasBlockAncestor(n, best) {
+			return
+		}
+		if txt := text(n); txt != "" {
+			blocks = append(blocks, txt)
+		}
+	})
+
+	return strings.Join(blocks, "\n\n")
+}
+
+// prune removes boilerplate elements from the tree below n.
+func prune(n *html.Node) {
+	for c := n.FirstChild; c != nil; {
+		next := c.NextSibling
+
+		switch {
+		case c.Type == html.CommentNode:
+			n.RemoveChild(c)
+		case c.Type == html.ElementNode && (removedTags[c.DataAtom] || isUnlikely(c)):
+			n.RemoveChild(c)
+		default:
+			prune(c)
+		}
+
+		c = next
+	}
+}
+
+// isUnlikely reports whether class or id of n mark it as boilerplate.
+func isUnlikely(n *html.Node) bool {
+	if n.DataAtom == atom.Body || n.DataAtom == atom.Article || n.DataAtom == atom.Main {
+		return false
+	}
+	if attr(n, "role") == "navigation" || attr(n, "aria-hidden") == "true" {
+		return true
+	}
+
+	ident := attr(n, "class") + " " + attr(n, "id")
+	return unlikely.MatchString(ident) && !likely.MatchString(ident)
+}
+
+// initialScore scores the element n by its tag and its class and id.
+func initialScore(n *html.Node) float64 {
+	var score float64
+	switch n.DataAtom {
+	case atom.Article:
+		score = 10
+	case atom.Main, atom.Section, atom.Div:
+		score = 5
+	case atom.Pre, atom.Td, atom.Blockquote:
+		score = 3
+	case atom.Ol, atom.Ul, atom.Form:
+		score = -3
+	case atom.H1, atom.H2, atom.H3, atom.Th:
+		score = -5
+	}
+
+	ident := attr(n, "class") + " " + attr(n, "id")
+	if likely.MatchString(ident) {
+		score += 25
+	}
+	if unlikely.MatchString(ident) {
+		score -= 25
+	}
+
+	return score
+}
+
+// linkDensity returns the share of the text of n, that is link text.
+func linkDensity(n *html.Node) float64 {
+	total := len(text(n))
+	if total == 0 {
+		return 0
+	}
+
+	var links int
+	walk(n, func(c *html.Node) {
+		if c.DataAtom == atom.A {
+			links += len(text(c))
+		}
+	})
+
+	return float64(links) / float64(total)
+}
+
+func hasBlockAncestor(n, stop *html.Node) bool {
+	for p := n.Parent; p != nil && p != stop; p = p.Parent {
+		if blockTags[p.DataAtom] {
+			return true
+		}
+	}
+	return false
+}
+
+// walk calls fn for n and all its element descendants in document order.
+func walk(n *html.Node, fn func(n *html.Node)) {
+	if n.Type == html.ElementNode {
+		fn(n)
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		walk(c, fn)
+	}
+}
+
+// find returns the first element below n with the given tag.
+func find(n *html.Node, tag atom.Atom) *html.Node {
+	var found *html.Node
+	walk(n, func(c *html.Node) {
+		if found == nil && c.DataAtom == tag {
+			found = c
+		}
+	})
+	return found
+}
+
+// text returns the text of n with collapsed whitespace.
+func text(n *html.Node) string {
+	var sb strings.Builder
+	var collect func(n *html.Node)
+	collect = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			sb.WriteString(n.Data)
+			return
+		}
+		if n.Type == html.ElementNode && (n.DataAtom == atom.Script || n.DataAtom == atom.Style) {
+			return
+		}
+		if n.DataAtom == atom.Br {
+			sb.WriteString(" ")
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			collect(c)
+		}
+	}
+	collect(n)
+
+	return strings.TrimSpace(spaces.ReplaceAllString(sb.String(), " "))
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
@@ -0,0 +1,98 @@
+package readability
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExtract(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		file    string
+		want    Document
+		wantErr bool
+	}{
+		{
+			name: "json-ld",
+			file: "testdata/jsonld.html",
+			want: Document{
+				Title:     "Energy prices keep rising",
+				Author:    "Jane Doe, John Doe",
+				Published: "2023-06-01T12:00:00+02:00",
+				Canonical: "https://news.example.com/energy-prices",
+				Body: "Gas and electricity prices rose again in May, the statistics office said on Thursday.\n\n" +
+					"Households are worried about the coming winter, as heating costs could double, according to consumer groups.\n\n" +
+					"Government plans relief\n\n" +
+					"The government announced a relief package, which is expected to pass parliament next week.",
+			},
+		},
+		{
+			name: "opengraph",
+			file: "testdata/opengraph.html",
+			want: Document{
+				Title:     "Storm warning for the weekend",
+				Author:    "John Doe",
+				Published: "2023-06-02T08:30:00Z",
+				Canonical: "https://weather.example.com/storm-warning",
+				Body: "Heavy rain and storms are expected over the weekend in the north of the country.\n\n" +
+					"The weather service warned of floods, fallen trees and disruptions of the train service.",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := os.Open(tt.file)
+			if err != nil {
+				t.Fatalf("could not open fixture, %s", err.Error())
+			}
+			defer f.Close()
+
+			got, err := Extract(f)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Extract() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("Extract() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtract_noContent(t *testing.T) {
+	t.Parallel()
+
+	_, err := Extract(strings.NewReader("<html><body><nav><p>Only navigation links in here, nothing else.</p></nav></body></html>"))
+	if err != ErrNoContent {
+		t.Errorf("Extract() error = %v, want %v", err, ErrNoContent)
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		date   string
+		want   time.Time
+		wantOk bool
+	}{
+		{date: "2023-06-01T12:00:00+02:00", want: time.Date(2023, 6, 1, 10, 0, 0, 0, time.UTC), wantOk: true},
+		{date: "2023-06-01", want: time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC), wantOk: true},
+		{date: "Thu, 01 Jun 2023 12:00:00 +0000", want: time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC), wantOk: true},
+		{date: "yesterday", wantOk: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.date, func(t *testing.T) {
+			got, ok := ParseDate(tt.date)
+			if ok != tt.wantOk || !got.Equal(tt.want) {
+				t.Errorf("ParseDate() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
@@ -0,0 +1,271 @@
+// Package related ranks stored articles by their relatedness to a given article.
+// Relatedness combines shared named entities, shared keywords, text similarity and
+// the proximity of the publication dates.
+package related
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Br0ce/articleDB/pkg/article"
+	"github.com/Br0ce/articleDB/pkg/simhash"
+	"github.com/Br0ce/articleDB/pkg/vector"
+)
+
+var ErrInvalidLimit = errors.New("limit must be greater than zero")
+
+// Kinds of reasons for a match.
+const (
+	ReasonEntities = "entities"
+	ReasonKeywords = "keywords"
+	ReasonText     = "text"
+	ReasonTime     = "time"
+)
+
+// Weights define how much each signal contributes to the score of a match.
+type Weights struct {
+	Entities float64
+	Keywords float64
+	Text     float64
+	Time     float64
+}
+
+// DefaultWeights are used, if no other weights are configured.
+var DefaultWeights = Weights{
+	Entities: 0.4,
+	Keywords: 0.2,
+	Text:     0.3,
+	Time:     0.1,
+}
+
+// Reason explains why an article is related.
+type Reason struct {
+	// Kind is one of ReasonEntities, ReasonKeywords, ReasonText and ReasonTime.
+	Kind string
+	// Shared holds the shared entities or keywords.
+	Shared []string
+	// Score is the score of the signal in the range [0, 1].
+	Score float64
+}
+
+// Result is a related article.
+type Result struct {
+	Article article.Article
+	// Score is the weighted score of all signals in the range [0, 1].
+	Score   float64
+	Reasons []Reason
+}
+
+// Lister gets and lists stored articles.
+type Lister interface {
+	Get(ctx context.Context, id string) (article.Article, error)
+	List(ctx context.Context) ([]article.Article, error)
+}
+
+// Ranker finds related articles.
+type Ranker struct {
+	db        Lister
+	weights   Weights
+	timeScale time.Duration
+}
+
+type Option func(r *Ranker)
+
+// New returns a Ranker over the articles of db.
+func New(db Lister, opts ...Option) *Ranker {
+	r := &Ranker{
+		db:        db,
+		weights:   DefaultWeights,
+		timeScale: 72 * time.Hour,
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
+}
+
+// WithWeights sets the weights of the signals.
+func WithWeights(w Weights) Option {
+	return func(r *Ranker) {
+		r.weights = w
+	}
+}
+
+// WithTimeScale sets the time after which the time proximity of two articles has
+// decayed to 1/e.
+func WithTimeScale(d time.Duration) Option {
+	return func(r *Ranker) {
+		if d > 0 {
+			r.timeScale = d
+		}
+	}
+}
+
+// Related returns up to n articles related to the article with the given ID, ordered
+// by decreasing score. Articles, that are only close in time, are not related.
+func (r *Ranker) Related(ctx context.Context, id string, n int) ([]Result, error) {
+	if n <= 0 {
+		return nil, ErrInvalidLimit
+	}
+
+	base, err := r.db.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := r.db.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	baseEntities := entities(base.NER)
+	baseKeywords := keywords(base.Keywords)
+
+	var results []Result
+	for _, item := range items {
+		if item.ID == base.ID {
+			continue
+		}
+
+		var reasons []Reason
+
+		shared, score := overlap(baseEntities, entities(item.NER))
+		if score > 0 {
+			reasons = append(reasons, Reason{Kind: ReasonEntities, Shared: shared, Score: score})
+		}
+
+		shared, score = overlap(baseKeywords, keywords(item.Keywords))
+		if score > 0 {
+			reasons = append(reasons, Reason{Kind: ReasonKeywords, Shared: shared, Score: score})
+		}
+
+		if score := textSimilarity(base, item); score > 0 {
+			reasons = append(reasons, Reason{Kind: ReasonText, Score: score})
+		}
+
+		if len(reasons) == 0 {
+			continue
+		}
+
+		if score := r.timeProximity(base.Published, item.Published); score > 0 {
+			reasons = append(reasons, Reason{Kind: ReasonTime, Score: score})
+		}
+
+		results = append(results, Result{
+			Article: item,
+			Score:   r.score(reasons),
+			Reasons: reasons,
+		})
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Article.ID < results[j].Article.ID
+	})
+
+	if n < len(results) {
+		results = results[:n]
+	}
+
+	return results, nil
+}
+
+// score returns the weighted mean of the scores of the reasons. Missing signals
+// count as zero.
+func (r *Ranker) score(reasons []Reason) float64 {
+	total := r.weights.Entities + r.weights.Keywords + r.weights.Text + r.weights.Time
+	if total <= 0 {
+		return 0
+	}
+
+	var sum float64
+	for _, reason := range reasons {
+		switch reason.Kind {
+		case ReasonEntities:
+			sum += r.weights.Entities * reason.Score
+		case ReasonKeywords:
+			sum += r.weights.Keywords * reason.Score
+		case ReasonText:
+			sum += r.weights.Text * reason.Score
+		case ReasonTime:
+			sum += r.weights.Time * reason.Score
+		}
+	}
+
+	return sum / total
+}
+
+// timeProximity decays exponentially with the time between a and b. It is zero if
+// one of the dates is unknown.
+func (r *Ranker) timeProximity(a, b time.Time) float64 {
+	if a.IsZero() || b.IsZero() {
+		return 0
+	}
+
+	d := a.Sub(b)
+	if d < 0 {
+		d = -d
+	}
+
+	return math.Exp(-float64(d) / float64(r.timeScale))
+}
+
+// textSimilarity compares the embeddings of a and b. If they have no comparable
+// embeddings, the fingerprints are compared. The similarity of unrelated texts is
+// mapped to zero.
+func textSimilarity(a, b article.Article) float64 {
+	if len(a.Embedding) > 0 && len(a.Embedding) == len(b.Embedding) {
+		return math.Max(0, vector.Cosine(a.Embedding, b.Embedding))
+	}
+
+	if a.Fingerprint != 0 && b.Fingerprint != 0 {
+		// Fingerprints of unrelated texts share about half of their bits.
+		return math.Max(0, 2*simhash.Similarity(a.Fingerprint, b.Fingerprint)-1)
+	}
+
+	return 0
+}
+
+// entities returns the set of entities qualified by their type, e.g. "person:olaf scholz".
+func entities(ner article.NER) map[string]string {
+	set := make(map[string]string, len(ner.Entities))
+	for _, e := range ner.Entities {
+		set[string(e.Type)+":"+strings.ToLower(strings.TrimSpace(e.Name))] = e.Name
+	}
+	return set
+}
+
+func keywords(kws []string) map[string]string {
+	set := make(map[string]string, len(kws))
+	for _, kw := range kws {
+		set[strings.ToLower(strings.TrimSpace(kw))] = kw
+	}
+	return set
+}
+
+// overlap returns the shared values of a and b ordered alphabetically and their
+// Jaccard index.
+func overlap(a, b map[string]string) ([]string, float64) {
+	if len(a) == 0 || len(b) == 0 {
+		return nil, 0
+	}
+
+	var shared []string
+	for key, value := range a {
+		if _, ok := b[key]; ok {
+			shared = append(shared, value)
+		}
+	}
+	sort.Strings(shared)
+
+	union := len(a) + len(b) - len(shared)
+	return shared, float64(len(shared)) / float64(union)
+}
@@ -0,0 +1,148 @@
+package related
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Br0ce/articleDB/pkg/article"
+	"github.com/Br0ce/articleDB/pkg/db"
+	"github.com/Br0ce/articleDB/pkg/mock"
+)
+
+func testDB() *mock.DB {
+	published := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+	items := []article.Article{
+		{
+			ID:        "base",
+			Published: published,
+			Keywords:  []string{"energy", "gas"},
+			NER:       article.NER{Entities: []article.Entity{{Name: "Olaf Scholz", Type: article.Person, Count: 1}, {Name: "EU", Type: article.Organisation, Count: 1}}},
+		},
+		{
+			ID:        "entities and keywords",
+			Published: published.Add(time.Hour),
+			Keywords:  []string{"Energy"},
+			NER:       article.NER{Entities: []article.Entity{{Name: "Olaf Scholz", Type: article.Person, Count: 1}, {Name: "EU", Type: article.Organisation, Count: 1}}},
+		},
+		{
+			ID:        "entity",
+			Published: published.Add(-30 * 24 * time.Hour),
+			NER:       article.NER{Entities: []article.Entity{{Name: "EU", Type: article.Organisation, Count: 1}, {Name: "NATO", Type: article.Organisation, Count: 1}}},
+		},
+		{
+			ID:        "only time",
+			Published: published,
+			NER:       article.NER{Entities: []article.Entity{{Name: "Someone Else", Type: article.Person, Count: 1}}},
+		},
+		{
+			ID:        "location not person",
+			Published: published,
+			NER:       article.NER{Entities: []article.Entity{{Name: "Olaf Scholz", Type: article.Location, Count: 1}}},
+		},
+	}
+
+	return &mock.DB{
+		GetFn: func(ctx context.Context, id string) (article.Article, error) {
+			for _, item := range items {
+				if item.ID == id {
+					return item, nil
+				}
+			}
+			return article.Article{}, db.ErrNotFound
+		},
+		ListFn: func(ctx context.Context) ([]article.Article, error) {
+			return items, nil
+		},
+	}
+}
+
+func TestRanker_Related(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		id      string
+		n       int
+		want    []string
+		wantErr error
+	}{
+		{
+			name: "pass",
+			id:   "base",
+			n:    10,
+			want: []string{"entities and keywords", "entity"},
+		},
+		{
+			name: "limit",
+			id:   "base",
+			n:    1,
+			want: []string{"entities and keywords"},
+		},
+		{
+			name:    "invalid limit",
+			id:      "base",
+			n:       0,
+			wantErr: ErrInvalidLimit,
+		},
+		{
+			name:    "not found",
+			id:      "unknown",
+			n:       10,
+			wantErr: db.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := New(testDB())
+
+			got, err := r.Related(context.TODO(), tt.id, tt.n)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Ranker.Related() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			var gotIDs []string
+			for _, res := range got {
+				gotIDs = append(gotIDs, res.Article.ID)
+			}
+			if !reflect.DeepEqual(gotIDs, tt.want) {
+				t.Errorf("Ranker.Related() = %v, want %v", gotIDs, tt.want)
+			}
+		})
+	}
+}
+
+func TestRanker_Related_reasons(t *testing.T) {
+	t.Parallel()
+
+	r := New(testDB())
+
+	got, err := r.Related(context.TODO(), "base", 1)
+	if err != nil {
+		t.Fatalf("Ranker.Related() error = %v", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("Ranker.Related() len = %v, want 1", len(got))
+	}
+
+	reasons := make(map[string]Reason)
+	for _, reason := range got[0].Reasons {
+		reasons[reason.Kind] = reason
+	}
+
+	if want := []string{"EU", "Olaf Scholz"}; !reflect.DeepEqual(reasons[ReasonEntities].Shared, want) {
+		t.Errorf("shared entities = %v, want %v", reasons[ReasonEntities].Shared, want)
+	}
+	if want := []string{"energy"}; !reflect.DeepEqual(reasons[ReasonKeywords].Shared, want) {
+		t.Errorf("shared keywords = %v, want %v", reasons[ReasonKeywords].Shared, want)
+	}
+	if _, ok := reasons[ReasonTime]; !ok {
+		t.Error("time reason missing")
+	}
+	if got[0].Score <= 0 || got[0].Score > 1 {
+		t.Errorf("score = %v, want in (0, 1]", got[0].Score)
+	}
+}
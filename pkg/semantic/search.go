@@ -0,0 +1,94 @@
+// Package semantic searches articles by the meaning of a query instead of its
+// keywords. Query and articles are compared by the cosine similarity of their
+// embeddings.
+package semantic
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/Br0ce/articleDB/pkg/article"
+	"github.com/Br0ce/articleDB/pkg/vector"
+)
+
+// Result is an article found by a search.
+type Result struct {
+	Article article.Article
+	// Score is the cosine similarity of the article to the query.
+	Score float64
+}
+
+// Searcher performs k nearest neighbour searches over the embeddings of the stored
+// articles. The embeddings of the query and of the articles must be created by the
+// same vector.Embedder.
+type Searcher struct {
+	emb vector.Embedder
+	idx vector.Index
+	db  article.DB
+	log *slog.Logger
+}
+
+// New returns a Searcher. The idx has to be filled with the embeddings of the
+// articles of db, e.g. by the adder or by Reindex. Use a vector.Flat index for exact
+// results or a hnsw.Index for large collections.
+func New(emb vector.Embedder, idx vector.Index, db article.DB, log *slog.Logger) *Searcher {
+	return &Searcher{
+		emb: emb,
+		idx: idx,
+		db:  db,
+		log: log,
+	}
+}
+
+// Search returns up to k articles ordered by decreasing similarity to the query.
+func (s *Searcher) Search(ctx context.Context, query string, k int) ([]Result, error) {
+	s.log.Info("semantic search", "method", "Search", "k", k)
+
+	if query == "" {
+		return nil, errors.New("could not search, query is empty")
+	}
+
+	vec, err := s.emb.Embed(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	hits, err := s.idx.Search(vec, k)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]Result, 0, len(hits))
+	for _, hit := range hits {
+		ar, err := s.db.Get(ctx, hit.ID)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, Result{Article: ar, Score: hit.Score})
+	}
+
+	return results, nil
+}
+
+// Reindex adds the embeddings of all stored articles to the index. Articles without
+// embedding are skipped.
+func (s *Searcher) Reindex(ctx context.Context) error {
+	s.log.Info("reindex article embeddings", "method", "Reindex")
+
+	items, err := s.db.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		if len(item.Embedding) == 0 {
+			continue
+		}
+		if err := s.idx.Add(item.ID, item.Embedding); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,62 @@
+package semantic
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Br0ce/articleDB/pkg/article"
+	"github.com/Br0ce/articleDB/pkg/db/inmem"
+	"github.com/Br0ce/articleDB/pkg/extract/ngram"
+	"github.com/Br0ce/articleDB/pkg/logger"
+	"github.com/Br0ce/articleDB/pkg/vector"
+)
+
+func TestSearcher_Search(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.TODO()
+	log := logger.NewTest(false)
+	emb := ngram.NewClient(0)
+	db := inmem.NewArticle()
+
+	bodies := map[string]string{
+		"energy":   "Gas prices rise as the energy crisis hits households across Europe.",
+		"football": "The national football team won the final after a penalty shootout.",
+		"weather":  "Storms and heavy rain are expected over the weekend in the north.",
+	}
+	titles := make(map[string]string)
+	for title, body := range bodies {
+		vec, err := emb.Embed(ctx, body)
+		if err != nil {
+			t.Fatalf("could not embed, %s", err.Error())
+		}
+		id, err := db.Add(ctx, article.Article{Title: title, Body: body, Embedding: vec})
+		if err != nil {
+			t.Fatalf("could not add, %s", err.Error())
+		}
+		titles[id] = title
+	}
+
+	s := New(emb, vector.NewFlat(), db, log)
+	if err := s.Reindex(ctx); err != nil {
+		t.Fatalf("Searcher.Reindex() error = %v", err)
+	}
+
+	got, err := s.Search(ctx, "energy prices for households", 2)
+	if err != nil {
+		t.Fatalf("Searcher.Search() error = %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("Searcher.Search() len = %v, want 2", len(got))
+	}
+	if got[0].Article.Title != "energy" {
+		t.Errorf("Searcher.Search() first = %v, want energy", got[0].Article.Title)
+	}
+	if got[0].Score < got[1].Score {
+		t.Errorf("Searcher.Search() not ordered by score")
+	}
+
+	if _, err := s.Search(ctx, "", 2); err == nil {
+		t.Error("Searcher.Search() without error for empty query")
+	}
+}
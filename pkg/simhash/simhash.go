@@ -0,0 +1,66 @@
+// Package simhash implements the SimHash locality-sensitive fingerprint. Similar
+// texts result in fingerprints with a small hamming distance.
+package simhash
+
+import (
+	"hash/fnv"
+	"math/bits"
+	"strings"
+	"unicode"
+)
+
+// shingleSize is the number of consecutive words, that are hashed as one feature.
+const shingleSize = 3
+
+// Fingerprint returns the 64 bit SimHash of the given text. The text is split into
+// lowercased words and every shingle of consecutive words is used as a feature.
+// An empty text results in a zero fingerprint.
+func Fingerprint(text string) uint64 {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	if len(words) == 0 {
+		return 0
+	}
+
+	size := shingleSize
+	if len(words) < size {
+		size = len(words)
+	}
+
+	var weights [64]int
+	for i := 0; i+size <= len(words); i++ {
+		h := fnv.New64a()
+		// Writing to a hash never returns an error.
+		_, _ = h.Write([]byte(strings.Join(words[i:i+size], " ")))
+		sum := h.Sum64()
+
+		for bit := 0; bit < 64; bit++ {
+			if sum&(1<<bit) != 0 {
+				weights[bit]++
+			} else {
+				weights[bit]--
+			}
+		}
+	}
+
+	var fp uint64
+	for bit, w := range weights {
+		if w > 0 {
+			fp |= 1 << bit
+		}
+	}
+
+	return fp
+}
+
+// Distance returns the hamming distance of the fingerprints a and b.
+func Distance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+// Similarity returns the similarity of the fingerprints a and b in the range [0, 1],
+// where 1 means identical fingerprints.
+func Similarity(a, b uint64) float64 {
+	return 1 - float64(Distance(a, b))/64
+}
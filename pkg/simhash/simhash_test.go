@@ -0,0 +1,53 @@
+package simhash
+
+import (
+	"testing"
+)
+
+func TestFingerprint(t *testing.T) {
+	t.Parallel()
+
+	text := "The chancellor met the president of France in Berlin on Monday to discuss the energy crisis and the future of the European Union."
+	edited := "The chancellor met the president of France in Berlin on Monday to discuss the energy crisis and the future of the EU."
+	other := "Heavy rain caused floods in the south of the country, the weather service warned of more storms over the weekend."
+
+	if Fingerprint(text) != Fingerprint(text) {
+		t.Fatal("fingerprint is not deterministic")
+	}
+
+	if Fingerprint("") != 0 {
+		t.Error("fingerprint of empty text is not zero")
+	}
+
+	near := Similarity(Fingerprint(text), Fingerprint(edited))
+	far := Similarity(Fingerprint(text), Fingerprint(other))
+	if near <= far {
+		t.Errorf("similarity of edited text %v, want greater than other text %v", near, far)
+	}
+	if near < 0.8 {
+		t.Errorf("similarity of edited text %v, want at least 0.8", near)
+	}
+}
+
+func TestDistance(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		a    uint64
+		b    uint64
+		want int
+	}{
+		{name: "equal", a: 42, b: 42, want: 0},
+		{name: "one bit", a: 0b1000, b: 0b1001, want: 1},
+		{name: "all bits", a: 0, b: ^uint64(0), want: 64},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Distance(tt.a, tt.b); got != tt.want {
+				t.Errorf("Distance() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
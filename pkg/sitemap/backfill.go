@@ -0,0 +1,372 @@
+package sitemap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Br0ce/articleDB/pkg/article"
+	"github.com/Br0ce/articleDB/pkg/crawl"
+	"github.com/Br0ce/articleDB/pkg/db"
+)
+
+const (
+	DefaultWorkers    = 2
+	DefaultCheckpoint = 20
+)
+
+// Fetcher fetches the article at addr, e.g. the fetch.Fetcher.
+type Fetcher interface {
+	Fetch(ctx context.Context, addr string) (article.Article, error)
+}
+
+// Adder adds an article, e.g. the adder.Adder, that extracts the features of the
+// article before storing it.
+type Adder interface {
+	Add(ctx context.Context, ar article.Article) (string, error)
+}
+
+// Backfill ingests all articles listed by the sitemaps of a publisher.
+type Backfill struct {
+	client     *http.Client
+	fetcher    Fetcher
+	adder      Adder
+	store      ProgressStore
+	since      time.Time
+	until      time.Time
+	workers    int
+	checkpoint int
+	log        *slog.Logger
+}
+
+type Option func(b *Backfill)
+
+// New returns a Backfill, that fetches the listed articles with fetcher and adds
+// them with adder. Without options all entries are ingested by DefaultWorkers
+// workers, crawl.DefaultClient is used to fetch the sitemaps and the progress is
+// only kept in memory.
+func New(fetcher Fetcher, adder Adder, log *slog.Logger, opts ...Option) *Backfill {
+	b := &Backfill{
+		fetcher:    fetcher,
+		adder:      adder,
+		workers:    DefaultWorkers,
+		checkpoint: DefaultCheckpoint,
+		log:        log,
+	}
+
+	for _, opt := range opts {
+		opt(b)
+	}
+
+	if b.client == nil {
+		b.client = crawl.DefaultClient
+	}
+	if b.store == nil {
+		b.store = NewMemoryStore()
+	}
+
+	return b
+}
+
+// WithHTTPClient sets the client used to fetch the sitemaps. The client should use
+// a crawl.Transport shared with the other fetching paths.
+func WithHTTPClient(client *http.Client) Option {
+	return func(b *Backfill) {
+		b.client = client
+	}
+}
+
+// WithProgressStore sets the store of the progress. A backfill with a persistent
+// store continues where an earlier run with the same store stopped.
+func WithProgressStore(store ProgressStore) Option {
+	return func(b *Backfill) {
+		b.store = store
+	}
+}
+
+// WithSince skips all entries dated before t. Entries without date are not skipped.
+func WithSince(t time.Time) Option {
+	return func(b *Backfill) {
+		b.since = t
+	}
+}
+
+// WithUntil skips all entries dated after t. Entries without date are not skipped.
+func WithUntil(t time.Time) Option {
+	return func(b *Backfill) {
+		b.until = t
+	}
+}
+
+// WithWorkers sets the number of entries fetched concurrently.
+func WithWorkers(n int) Option {
+	return func(b *Backfill) {
+		if n > 0 {
+			b.workers = n
+		}
+	}
+}
+
+// WithCheckpoint sets after how many processed entries the progress is saved.
+func WithCheckpoint(n int) Option {
+	return func(b *Backfill) {
+		if n > 0 {
+			b.checkpoint = n
+		}
+	}
+}
+
+// Run discovers all sitemaps reachable from the sitemap or sitemap index at root and
+// ingests their entries. It returns the number of added articles. Entries, that
+// are rejected as duplicates by the adder, count as processed. Sitemaps and entries,
+// that failed temporarily, e.g. by a timeout or a server error, stay pending for the
+// next run. Those, that could not be ingested for other reasons, are recorded as
+// failed and are not retried. If ctx is done, the progress is saved and the next
+// run continues with the remaining entries. Sitemaps processed by an earlier run are
+// not fetched again.
+func (b *Backfill) Run(ctx context.Context, root string) (int, error) {
+	b.log.Info("start backfill", "method", "Run", "root", root)
+
+	p, err := b.store.Load()
+	if err != nil {
+		return 0, fmt.Errorf("could not load progress, %w", err)
+	}
+
+	if _, ok := p.Sitemaps[root]; !ok {
+		p.Sitemaps[root] = false
+	}
+
+	err = b.discover(ctx, &p)
+	if err != nil {
+		return 0, err
+	}
+
+	b.log.Info("sitemaps discovered", "method", "Run", "root", root, "queued", len(p.Queue))
+
+	added, err := b.ingest(ctx, &p)
+	if err != nil {
+		return added, err
+	}
+
+	b.log.Info("backfill done", "method", "Run", "root", root, "added", added, "failed", len(p.Failed))
+
+	return added, nil
+}
+
+// discover processes all pending sitemaps and queues their entries. The progress is
+// saved after every sitemap. A sitemap, that can not be fetched, is recorded as
+// failed. A sitemap, that failed temporarily, stays pending for the next run.
+func (b *Backfill) discover(ctx context.Context, p *Progress) error {
+	skipped := make(map[string]bool)
+	for {
+		addr, ok := pending(*p, skipped)
+		if !ok {
+			return nil
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		sm, err := b.fetch(ctx, addr)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if crawl.Temporary(err) {
+				b.log.Warn("sitemap temporarily unavailable", "method", "discover", "sitemap", addr, "err", err.Error())
+				skipped[addr] = true
+				continue
+			}
+			b.log.Error("could not fetch sitemap", "method", "discover", "sitemap", addr, "err", err.Error())
+			p.Failed[addr] = err.Error()
+		}
+
+		for _, s := range sm.Sitemaps {
+			if _, ok := p.Sitemaps[s]; !ok {
+				p.Sitemaps[s] = false
+			}
+		}
+		for _, e := range sm.Entries {
+			if b.wanted(e) && !p.Done[e.Loc] {
+				p.enqueue(e)
+			}
+		}
+		p.Sitemaps[addr] = true
+
+		err = b.store.Save(*p)
+		if err != nil {
+			return fmt.Errorf("could not save progress, %w", err)
+		}
+	}
+}
+
+// pending returns an unprocessed sitemap of the progress, that is not skipped. The
+// smallest url is returned, so the sitemaps are processed in a stable order.
+func pending(p Progress, skipped map[string]bool) (string, bool) {
+	var addrs []string
+	for addr, done := range p.Sitemaps {
+		if !done && !skipped[addr] {
+			addrs = append(addrs, addr)
+		}
+	}
+	if len(addrs) == 0 {
+		return "", false
+	}
+
+	sort.Strings(addrs)
+	return addrs[0], true
+}
+
+// fetch downloads and parses the sitemap at addr.
+func (b *Backfill) fetch(ctx context.Context, addr string) (Sitemap, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, addr, nil)
+	if err != nil {
+		return Sitemap{}, err
+	}
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return Sitemap{}, err
+	}
+	defer resp.Body.Close()
+
+	if crawl.TemporaryStatus(resp.StatusCode) {
+		return Sitemap{}, fmt.Errorf("unexpected status %s, %w", resp.Status, crawl.ErrTemporary)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return Sitemap{}, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	return Parse(io.LimitReader(resp.Body, crawl.DefaultMaxSize))
+}
+
+// wanted reports whether the entry is within the configured date range.
+func (b *Backfill) wanted(e Entry) bool {
+	date := e.Date()
+	if date.IsZero() {
+		return true
+	}
+	if !b.since.IsZero() && date.Before(b.since) {
+		return false
+	}
+	if !b.until.IsZero() && date.After(b.until) {
+		return false
+	}
+	return true
+}
+
+type result struct {
+	entry Entry
+	err   error
+}
+
+// ingest fetches and adds the queued entries with the configured number of workers.
+// The progress is saved every checkpoint entries and when all workers stopped.
+func (b *Backfill) ingest(ctx context.Context, p *Progress) (int, error) {
+	queue := append([]Entry(nil), p.Queue...)
+
+	entries := make(chan Entry)
+	go func() {
+		defer close(entries)
+		for _, e := range queue {
+			select {
+			case entries <- e:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	results := make(chan result)
+	var wg sync.WaitGroup
+	for i := 0; i < b.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for e := range entries {
+				results <- result{entry: e, err: b.ingestEntry(ctx, e)}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	var added, processed int
+	var errs []error
+	for r := range results {
+		// Entries interrupted by ctx stay queued for the next run.
+		if r.err != nil && ctx.Err() != nil {
+			continue
+		}
+		if r.err != nil && crawl.Temporary(r.err) {
+			b.log.Warn("entry temporarily unavailable", "method", "ingest", "addr", r.entry.Loc, "err", r.err.Error())
+			continue
+		}
+
+		p.Done[r.entry.Loc] = true
+		switch {
+		case r.err == nil:
+			added++
+		case errors.Is(r.err, db.ErrAlreadyExists):
+		default:
+			b.log.Error("could not ingest entry", "method", "ingest", "addr", r.entry.Loc, "err", r.err.Error())
+			p.Failed[r.entry.Loc] = r.err.Error()
+		}
+
+		processed++
+		if processed%b.checkpoint == 0 {
+			err := b.save(p)
+			if err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	err := b.save(p)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	if len(errs) > 0 {
+		return added, errors.Join(errs...)
+	}
+
+	return added, ctx.Err()
+}
+
+// ingestEntry fetches and adds the article of the entry. The title and publication
+// date of a news sitemap are used, if the page does not provide them.
+func (b *Backfill) ingestEntry(ctx context.Context, e Entry) error {
+	ar, err := b.fetcher.Fetch(ctx, e.Loc)
+	if err != nil {
+		return err
+	}
+
+	if ar.Title == "" {
+		ar.Title = e.Title
+	}
+	if ar.Published.IsZero() {
+		ar.Published = e.Published
+	}
+
+	_, err = b.adder.Add(ctx, ar)
+	return err
+}
+
+// save removes the processed entries from the queue and saves the progress.
+func (b *Backfill) save(p *Progress) error {
+	p.dequeueDone()
+
+	err := b.store.Save(*p)
+	if err != nil {
+		return fmt.Errorf("could not save progress, %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,326 @@
+package sitemap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Br0ce/articleDB/pkg/article"
+	"github.com/Br0ce/articleDB/pkg/logger"
+	"github.com/Br0ce/articleDB/pkg/mock"
+)
+
+// sitemapServer serves the fixtures as sitemap index at /sitemap.xml with a
+// sitemap and a news sitemap.
+func sitemapServer(t *testing.T) *httptest.Server {
+	files := map[string]string{
+		"/sitemap.xml":      "testdata/index.xml",
+		"/sitemap-2023.xml": "testdata/urlset.xml",
+		"/news-sitemap.xml": "testdata/news.xml",
+	}
+
+	var svr *httptest.Server
+	svr = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		file, ok := files[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/xml")
+		_, err := w.Write(fixture(t, file, svr.URL))
+		if err != nil {
+			t.Errorf("could not write fixture, %s", err.Error())
+		}
+	}))
+
+	return svr
+}
+
+// pageFetcher returns a fetcher, that returns an article without title and
+// publication date for every addr and records the fetched addrs.
+func pageFetcher(fetched *[]string, mu *sync.Mutex) *mock.Fetcher {
+	return &mock.Fetcher{FetchFn: func(ctx context.Context, addr string) (article.Article, error) {
+		mu.Lock()
+		*fetched = append(*fetched, addr)
+		mu.Unlock()
+
+		u, err := url.Parse(addr)
+		if err != nil {
+			return article.Article{}, err
+		}
+		return article.Article{Addr: *u, Body: "body of " + addr}, nil
+	}}
+}
+
+func TestBackfill_Run(t *testing.T) {
+	t.Parallel()
+
+	svr := sitemapServer(t)
+	defer svr.Close()
+
+	var fetched []string
+	var mu sync.Mutex
+	fetcher := pageFetcher(&fetched, &mu)
+	inner := fetcher.FetchFn
+	fetcher.FetchFn = func(ctx context.Context, addr string) (article.Article, error) {
+		ar, err := inner(ctx, addr)
+		if addr == svr.URL+"/about" {
+			return article.Article{}, errors.New("no article")
+		}
+		return ar, err
+	}
+
+	added := make(map[string]article.Article)
+	adder := &mock.Adder{AddFn: func(ctx context.Context, ar article.Article) (string, error) {
+		added[ar.Addr.String()] = ar
+		return "1234", nil
+	}}
+
+	store := NewMemoryStore()
+	b := New(fetcher, adder, logger.NewTest(false),
+		WithHTTPClient(svr.Client()),
+		WithProgressStore(store),
+		WithSince(time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)),
+		WithWorkers(1))
+
+	got, err := b.Run(context.TODO(), svr.URL+"/sitemap.xml")
+	if err != nil {
+		t.Fatalf("Backfill.Run() error = %v", err)
+	}
+	if got != 2 {
+		t.Errorf("Backfill.Run() = %v, want 2", got)
+	}
+
+	sort.Strings(fetched)
+	want := []string{
+		svr.URL + "/2023/11/election-results",
+		svr.URL + "/2023/12/energy-prices",
+		svr.URL + "/about",
+	}
+	if !reflect.DeepEqual(fetched, want) {
+		t.Errorf("fetched = %v, want %v", fetched, want)
+	}
+
+	energy := added[svr.URL+"/2023/12/energy-prices"]
+	if energy.Title != "Energy prices keep rising" || !energy.Published.Equal(time.Date(2023, 12, 1, 8, 0, 0, 0, time.UTC)) {
+		t.Errorf("news metadata not used, got %+v", energy)
+	}
+
+	p, err := store.Load()
+	if err != nil {
+		t.Fatalf("MemoryStore.Load() error = %v", err)
+	}
+	if len(p.Queue) != 0 || len(p.Done) != 3 {
+		t.Errorf("progress queue %v, done %v", p.Queue, p.Done)
+	}
+	if _, ok := p.Failed[svr.URL+"/about"]; !ok || len(p.Failed) != 1 {
+		t.Errorf("progress failed = %v", p.Failed)
+	}
+	for addr, done := range p.Sitemaps {
+		if !done {
+			t.Errorf("sitemap %s not processed", addr)
+		}
+	}
+
+	// A second run has nothing left to do.
+	got, err = b.Run(context.TODO(), svr.URL+"/sitemap.xml")
+	if err != nil || got != 0 {
+		t.Errorf("Backfill.Run() again = %v, %v", got, err)
+	}
+}
+
+func TestBackfill_Run_resume(t *testing.T) {
+	t.Parallel()
+
+	svr := sitemapServer(t)
+	defer svr.Close()
+
+	store := NewFileStore(filepath.Join(t.TempDir(), "progress.json"))
+	adder := &mock.Adder{AddFn: func(ctx context.Context, ar article.Article) (string, error) {
+		return "1234", nil
+	}}
+
+	// The first run is interrupted while fetching the second entry.
+	var fetched []string
+	var mu sync.Mutex
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	fetcher := pageFetcher(&fetched, &mu)
+	inner := fetcher.FetchFn
+	fetcher.FetchFn = func(ctx context.Context, addr string) (article.Article, error) {
+		mu.Lock()
+		n := len(fetched)
+		mu.Unlock()
+		if n == 1 {
+			cancel()
+			return article.Article{}, ctx.Err()
+		}
+		return inner(ctx, addr)
+	}
+
+	b := New(fetcher, adder, logger.NewTest(false),
+		WithHTTPClient(svr.Client()),
+		WithProgressStore(store),
+		WithWorkers(1),
+		WithCheckpoint(1))
+
+	got, err := b.Run(ctx, svr.URL+"/sitemap.xml")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Backfill.Run() error = %v, want %v", err, context.Canceled)
+	}
+	if got != 1 {
+		t.Errorf("Backfill.Run() = %v, want 1", got)
+	}
+
+	// The second run continues with the remaining entries.
+	var resumed []string
+	b = New(pageFetcher(&resumed, &mu), adder, logger.NewTest(false),
+		WithHTTPClient(svr.Client()),
+		WithProgressStore(store),
+		WithWorkers(1))
+
+	got, err = b.Run(context.TODO(), svr.URL+"/sitemap.xml")
+	if err != nil {
+		t.Fatalf("Backfill.Run() error = %v", err)
+	}
+	if got != 3 {
+		t.Errorf("Backfill.Run() = %v, want 3", got)
+	}
+	for _, addr := range resumed {
+		if addr == fetched[0] {
+			t.Errorf("entry %s fetched again", addr)
+		}
+	}
+
+	p, err := store.Load()
+	if err != nil {
+		t.Fatalf("FileStore.Load() error = %v", err)
+	}
+	if len(p.Queue) != 0 || len(p.Done) != 4 || len(p.Failed) != 0 {
+		t.Errorf("progress queue %v, done %v, failed %v", p.Queue, p.Done, p.Failed)
+	}
+}
+
+func TestFileStore_Load(t *testing.T) {
+	t.Parallel()
+
+	store := NewFileStore(filepath.Join(t.TempDir(), "progress.json"))
+
+	got, err := store.Load()
+	if err != nil {
+		t.Fatalf("FileStore.Load() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, NewProgress()) {
+		t.Errorf("FileStore.Load() = %+v, want empty progress", got)
+	}
+
+	want := NewProgress()
+	want.Sitemaps["https://news.example.com/sitemap.xml"] = true
+	want.Queue = []Entry{{Loc: "https://news.example.com/a", LastMod: time.Date(2023, 1, 15, 0, 0, 0, 0, time.UTC)}}
+	want.Done["https://news.example.com/b"] = true
+
+	err = store.Save(want)
+	if err != nil {
+		t.Fatalf("FileStore.Save() error = %v", err)
+	}
+
+	got, err = store.Load()
+	if err != nil {
+		t.Fatalf("FileStore.Load() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FileStore.Load() = %+v, want %+v", got, want)
+	}
+}
+
+func TestBackfill_Run_temporary(t *testing.T) {
+	t.Parallel()
+
+	svr := sitemapServer(t)
+	defer svr.Close()
+
+	// The news sitemap is unavailable during the first run.
+	var unavailable atomic.Bool
+	unavailable.Store(true)
+	files := svr.Config.Handler
+	svr.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/news-sitemap.xml" && unavailable.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		files.ServeHTTP(w, r)
+	})
+
+	// The first fetched entry times out during the first run.
+	var fetched []string
+	var mu sync.Mutex
+	fetcher := pageFetcher(&fetched, &mu)
+	inner := fetcher.FetchFn
+	fetcher.FetchFn = func(ctx context.Context, addr string) (article.Article, error) {
+		mu.Lock()
+		n := len(fetched)
+		mu.Unlock()
+		if n == 0 {
+			_, _ = inner(ctx, addr)
+			return article.Article{}, fmt.Errorf("%s, %w", addr, context.DeadlineExceeded)
+		}
+		return inner(ctx, addr)
+	}
+	adder := &mock.Adder{AddFn: func(ctx context.Context, ar article.Article) (string, error) {
+		return "1234", nil
+	}}
+
+	store := NewMemoryStore()
+	b := New(fetcher, adder, logger.NewTest(false),
+		WithHTTPClient(svr.Client()),
+		WithProgressStore(store),
+		WithWorkers(1))
+
+	got, err := b.Run(context.TODO(), svr.URL+"/sitemap.xml")
+	if err != nil {
+		t.Fatalf("Backfill.Run() error = %v", err)
+	}
+	if got != 2 {
+		t.Errorf("Backfill.Run() = %v, want 2", got)
+	}
+
+	p, err := store.Load()
+	if err != nil {
+		t.Fatalf("MemoryStore.Load() error = %v", err)
+	}
+	if p.Sitemaps[svr.URL+"/news-sitemap.xml"] || len(p.Failed) != 0 {
+		t.Errorf("progress sitemaps %v, failed %v, want pending news sitemap", p.Sitemaps, p.Failed)
+	}
+	if len(p.Queue) != 1 || p.Queue[0].Loc != fetched[0] {
+		t.Errorf("progress queue %v, want %v", p.Queue, fetched[0])
+	}
+
+	// The second run retries the news sitemap and the timed out entry.
+	unavailable.Store(false)
+	got, err = b.Run(context.TODO(), svr.URL+"/sitemap.xml")
+	if err != nil {
+		t.Fatalf("Backfill.Run() error = %v", err)
+	}
+	if got != 2 {
+		t.Errorf("Backfill.Run() = %v, want 2", got)
+	}
+
+	p, err = store.Load()
+	if err != nil {
+		t.Fatalf("MemoryStore.Load() error = %v", err)
+	}
+	if len(p.Queue) != 0 || len(p.Done) != 4 || len(p.Failed) != 0 {
+		t.Errorf("progress queue %v, done %v, failed %v", p.Queue, p.Done, p.Failed)
+	}
+}
@@ -0,0 +1,168 @@
+// Package sitemap backfills the archive from the sitemaps of a publisher. Sitemap
+// indexes, sitemaps and Google News sitemaps are supported.
+package sitemap
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// MaxSize is the maximal size of an uncompressed sitemap (sitemaps.org protocol).
+const MaxSize = 50 << 20
+
+var (
+	ErrUnknownFormat = errors.New("unknown sitemap format")
+	ErrTooLarge      = errors.New("sitemap too large")
+)
+
+// Entry is an article url listed by a sitemap.
+type Entry struct {
+	Loc     string    `json:"loc"`
+	LastMod time.Time `json:"lastmod,omitempty"`
+	// Title and Published are only set by news sitemaps.
+	Title     string    `json:"title,omitempty"`
+	Published time.Time `json:"published,omitempty"`
+}
+
+// Date returns the publication date of the entry, if known, otherwise the date of
+// the last modification.
+func (e Entry) Date() time.Time {
+	if !e.Published.IsZero() {
+		return e.Published
+	}
+	return e.LastMod
+}
+
+// Sitemap is a parsed sitemap. A sitemap index only lists further sitemaps, a
+// sitemap only lists entries.
+type Sitemap struct {
+	Sitemaps []string
+	Entries  []Entry
+}
+
+type urlsetDTO struct {
+	URLs []struct {
+		Loc     string `xml:"loc"`
+		LastMod string `xml:"lastmod"`
+		News    struct {
+			Title           string `xml:"title"`
+			PublicationDate string `xml:"publication_date"`
+		} `xml:"http://www.google.com/schemas/sitemap-news/0.9 news"`
+	} `xml:"url"`
+}
+
+type indexDTO struct {
+	Sitemaps []struct {
+		Loc string `xml:"loc"`
+	} `xml:"sitemap"`
+}
+
+// Parse parses a sitemap or a sitemap index. Gzip compressed sitemaps are
+// decompressed. A sitemap larger than MaxSize after decompression is rejected with
+// ErrTooLarge.
+func Parse(r io.Reader) (Sitemap, error) {
+	br := bufio.NewReader(r)
+	if magic, err := br.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		zr, err := gzip.NewReader(br)
+		if err != nil {
+			return Sitemap{}, err
+		}
+		defer zr.Close()
+		r = zr
+	} else {
+		r = br
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r, MaxSize+1))
+	if err != nil {
+		return Sitemap{}, err
+	}
+	if len(data) > MaxSize {
+		return Sitemap{}, ErrTooLarge
+	}
+
+	root, err := rootName(data)
+	if err != nil {
+		return Sitemap{}, err
+	}
+
+	switch root {
+	case "sitemapindex":
+		var dto indexDTO
+		if err := xml.Unmarshal(data, &dto); err != nil {
+			return Sitemap{}, err
+		}
+
+		var sm Sitemap
+		for _, s := range dto.Sitemaps {
+			if loc := strings.TrimSpace(s.Loc); loc != "" {
+				sm.Sitemaps = append(sm.Sitemaps, loc)
+			}
+		}
+		return sm, nil
+	case "urlset":
+		var dto urlsetDTO
+		if err := xml.Unmarshal(data, &dto); err != nil {
+			return Sitemap{}, err
+		}
+
+		var sm Sitemap
+		for _, u := range dto.URLs {
+			loc := strings.TrimSpace(u.Loc)
+			if loc == "" {
+				continue
+			}
+			sm.Entries = append(sm.Entries, Entry{
+				Loc:       loc,
+				LastMod:   parseDate(u.LastMod),
+				Title:     strings.TrimSpace(u.News.Title),
+				Published: parseDate(u.News.PublicationDate),
+			})
+		}
+		return sm, nil
+	default:
+		return Sitemap{}, fmt.Errorf("root element %q, %w", root, ErrUnknownFormat)
+	}
+}
+
+// rootName returns the local name of the root element of the xml document.
+func rootName(data []byte) (string, error) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return "", fmt.Errorf("%s, %w", err.Error(), ErrUnknownFormat)
+		}
+		if start, ok := tok.(xml.StartElement); ok {
+			return start.Name.Local, nil
+		}
+	}
+}
+
+// dateLayouts are the W3C datetime formats allowed in sitemaps.
+var dateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02",
+	"2006-01",
+	"2006",
+}
+
+// parseDate parses a W3C datetime. It returns the zero time, if the date can not
+// be parsed.
+func parseDate(s string) time.Time {
+	s = strings.TrimSpace(s)
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.UTC()
+		}
+	}
+	return time.Time{}
+}
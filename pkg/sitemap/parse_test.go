@@ -0,0 +1,158 @@
+package sitemap
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fixture reads the sitemap fixture and replaces the {{base}} placeholder with base.
+func fixture(t *testing.T, file, base string) []byte {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("could not read fixture, %s", err.Error())
+	}
+	return bytes.ReplaceAll(data, []byte("{{base}}"), []byte(base))
+}
+
+func gzipped(t *testing.T, data []byte) []byte {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	_, err := zw.Write(data)
+	if err != nil {
+		t.Fatalf("could not compress, %s", err.Error())
+	}
+	err = zw.Close()
+	if err != nil {
+		t.Fatalf("could not compress, %s", err.Error())
+	}
+	return buf.Bytes()
+}
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	base := "https://news.example.com"
+	urlset := Sitemap{Entries: []Entry{
+		{Loc: base + "/2023/01/old-story", LastMod: time.Date(2023, 1, 15, 0, 0, 0, 0, time.UTC)},
+		{Loc: base + "/2023/11/election-results", LastMod: time.Date(2023, 11, 6, 17, 30, 0, 0, time.UTC)},
+		{Loc: base + "/about"},
+	}}
+
+	tests := []struct {
+		name    string
+		data    []byte
+		want    Sitemap
+		wantErr error
+	}{
+		{
+			name: "index",
+			data: fixture(t, "testdata/index.xml", base),
+			want: Sitemap{Sitemaps: []string{base + "/sitemap-2023.xml", base + "/news-sitemap.xml"}},
+		},
+		{
+			name: "urlset",
+			data: fixture(t, "testdata/urlset.xml", base),
+			want: urlset,
+		},
+		{
+			name: "gzip",
+			data: gzipped(t, fixture(t, "testdata/urlset.xml", base)),
+			want: urlset,
+		},
+		{
+			name: "news",
+			data: fixture(t, "testdata/news.xml", base),
+			want: Sitemap{Entries: []Entry{
+				{
+					Loc:       base + "/2023/12/energy-prices",
+					Title:     "Energy prices keep rising",
+					Published: time.Date(2023, 12, 1, 8, 0, 0, 0, time.UTC),
+				},
+				{
+					Loc:       base + "/2023/11/election-results",
+					Title:     "Election results",
+					Published: time.Date(2023, 11, 6, 17, 30, 0, 0, time.UTC),
+				},
+			}},
+		},
+		{
+			name:    "rss",
+			data:    []byte(`<rss version="2.0"><channel></channel></rss>`),
+			wantErr: ErrUnknownFormat,
+		},
+		{
+			name:    "no xml",
+			data:    []byte("not a sitemap"),
+			wantErr: ErrUnknownFormat,
+		},
+		{
+			// A small compressed sitemap may expand beyond the limit.
+			name:    "gzip bomb",
+			data:    gzipped(t, append([]byte("<urlset>"), bytes.Repeat([]byte(" "), MaxSize)...)),
+			wantErr: ErrTooLarge,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(bytes.NewReader(tt.data))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEntry_Date(t *testing.T) {
+	t.Parallel()
+
+	lastMod := time.Date(2023, 1, 15, 0, 0, 0, 0, time.UTC)
+	published := time.Date(2023, 1, 14, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		entry Entry
+		want  time.Time
+	}{
+		{name: "published", entry: Entry{LastMod: lastMod, Published: published}, want: published},
+		{name: "lastmod", entry: Entry{LastMod: lastMod}, want: lastMod},
+		{name: "none", entry: Entry{}, want: time.Time{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.entry.Date(); !got.Equal(tt.want) {
+				t.Errorf("Entry.Date() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{in: "2023-11-06T18:30:00+01:00", want: time.Date(2023, 11, 6, 17, 30, 0, 0, time.UTC)},
+		{in: "2023-11-06T18:30+01:00", want: time.Date(2023, 11, 6, 17, 30, 0, 0, time.UTC)},
+		{in: " 2023-11-06 ", want: time.Date(2023, 11, 6, 0, 0, 0, 0, time.UTC)},
+		{in: "2023-11", want: time.Date(2023, 11, 1, 0, 0, 0, 0, time.UTC)},
+		{in: "yesterday", want: time.Time{}},
+	}
+	for _, tt := range tests {
+		t.Run(strings.TrimSpace(tt.in), func(t *testing.T) {
+			if got := parseDate(tt.in); !got.Equal(tt.want) {
+				t.Errorf("parseDate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package sitemap
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Progress is the state of a backfill. It is saved regularly, so an interrupted
+// backfill can be resumed.
+type Progress struct {
+	// Sitemaps maps the url of every discovered sitemap to whether it has been
+	// processed.
+	Sitemaps map[string]bool `json:"sitemaps"`
+	// Queue holds the entries waiting to be fetched in the order of discovery.
+	Queue []Entry `json:"queue"`
+	// Done holds the urls of all processed entries.
+	Done map[string]bool `json:"done"`
+	// Failed maps the urls of entries, that could not be ingested, to the error.
+	// Failed entries are part of Done and are not retried. Entries, that failed
+	// temporarily, stay queued instead.
+	Failed map[string]string `json:"failed"`
+
+	// queued holds the urls of the entries of Queue. It is built on first use.
+	queued map[string]bool
+}
+
+// NewProgress returns an empty Progress.
+func NewProgress() Progress {
+	return Progress{
+		Sitemaps: make(map[string]bool),
+		Done:     make(map[string]bool),
+		Failed:   make(map[string]string),
+	}
+}
+
+// Queued reports whether the entry with the given url is waiting to be fetched.
+func (p *Progress) Queued(loc string) bool {
+	if p.queued == nil {
+		p.queued = make(map[string]bool, len(p.Queue))
+		for _, e := range p.Queue {
+			p.queued[e.Loc] = true
+		}
+	}
+	return p.queued[loc]
+}
+
+// enqueue appends the entry to the queue, unless it is already queued.
+func (p *Progress) enqueue(e Entry) {
+	if !p.Queued(e.Loc) {
+		p.Queue = append(p.Queue, e)
+		p.queued[e.Loc] = true
+	}
+}
+
+// dequeueDone removes the processed entries from the queue.
+func (p *Progress) dequeueDone() {
+	queue := p.Queue[:0]
+	for _, e := range p.Queue {
+		if p.Done[e.Loc] {
+			delete(p.queued, e.Loc)
+			continue
+		}
+		queue = append(queue, e)
+	}
+	p.Queue = queue
+}
+
+// ProgressStore persists the progress of a backfill.
+type ProgressStore interface {
+	Load() (Progress, error)
+	Save(p Progress) error
+}
+
+// FileStore stores the progress as json file.
+type FileStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+func NewFileStore(path string) *FileStore {
+	return &FileStore{path: path}
+}
+
+// Load reads the progress from the file. A missing file results in an empty progress.
+func (f *FileStore) Load() (Progress, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	data, err := os.ReadFile(f.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return NewProgress(), nil
+	}
+	if err != nil {
+		return Progress{}, err
+	}
+
+	p := NewProgress()
+	if err := json.Unmarshal(data, &p); err != nil {
+		return Progress{}, err
+	}
+
+	return p, nil
+}
+
+// Save writes the progress to the file. The file is replaced atomically, so a crash
+// while saving does not corrupt the earlier saved progress.
+func (f *FileStore) Save(p Progress) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	data, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(f.path), filepath.Base(f.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+
+	_, err = tmp.Write(data)
+	if err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	err = tmp.Close()
+	if err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	return os.Rename(tmp.Name(), f.path)
+}
+
+// MemoryStore keeps the progress in memory. It is useful for tests and one-off
+// backfills, that do not need to be resumed after a restart.
+type MemoryStore struct {
+	progress Progress
+	mu       sync.Mutex
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{progress: NewProgress()}
+}
+
+func (m *MemoryStore) Load() (Progress, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	data, err := json.Marshal(m.progress)
+	if err != nil {
+		return Progress{}, err
+	}
+
+	p := NewProgress()
+	return p, json.Unmarshal(data, &p)
+}
+
+func (m *MemoryStore) Save(p Progress) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	data, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+
+	m.progress = NewProgress()
+	return json.Unmarshal(data, &m.progress)
+}
@@ -0,0 +1,243 @@
+// Package suggest completes prefixes to the titles of articles and to the names of
+// the entities they mention, e.g. for an autocompletion of search queries.
+// Misspelled prefixes are completed within a small edit distance.
+package suggest
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/Br0ce/articleDB/pkg/analysis"
+	"github.com/Br0ce/articleDB/pkg/article"
+)
+
+// Kind is the kind of text of a suggestion.
+type Kind string
+
+const (
+	KindTitle  Kind = "title"
+	KindEntity Kind = "entity"
+)
+
+// Suggestion is a completion of a prefix.
+type Suggestion struct {
+	Text string
+	Kind Kind
+	// Type is the type of an entity and empty for a title.
+	Type article.EntityType
+	// Count is the number of articles with the title or mentioning the entity.
+	Count int
+	// Distance is the edit distance of the prefix to the beginning of a word of
+	// the text.
+	Distance int
+}
+
+// Suggester completes prefixes, e.g. the inmem.Article.
+type Suggester interface {
+	Suggest(ctx context.Context, prefix string, limit int) ([]Suggestion, error)
+}
+
+// entry identifies a text of a kind. Texts differing in case are the same entry.
+type entry struct {
+	kind Kind
+	typ  article.EntityType
+	text string
+}
+
+// item is an entry stored in the trie with the first added form of its text.
+type item struct {
+	text  string
+	count int
+}
+
+type node struct {
+	children map[rune]*node
+	// items are the entries of the texts with a word suffix ending at the node.
+	items map[entry]*item
+}
+
+// Trie is a prefix tree of texts. A text is found by a prefix of any of its word
+// suffixes, e.g. "Olaf Scholz" by "scho" as well as by "olaf s". The zero value is
+// an empty trie. A Trie is not safe for concurrent use.
+type Trie struct {
+	root node
+}
+
+// Add adds the text of the kind, the entity type is empty for a title. Adding
+// the same text again increases its count.
+func (t *Trie) Add(text string, kind Kind, typ article.EntityType) {
+	e := entry{kind: kind, typ: typ, text: strings.ToLower(strings.TrimSpace(text))}
+	for _, key := range keys(text) {
+		n := &t.root
+		for _, r := range key {
+			if n.children == nil {
+				n.children = make(map[rune]*node)
+			}
+			child, ok := n.children[r]
+			if !ok {
+				child = &node{}
+				n.children[r] = child
+			}
+			n = child
+		}
+		if n.items == nil {
+			n.items = make(map[entry]*item)
+		}
+		it, ok := n.items[e]
+		if !ok {
+			it = &item{text: strings.TrimSpace(text)}
+			n.items[e] = it
+		}
+		it.count++
+	}
+}
+
+// Remove decreases the count of the text of the kind and removes it at 0.
+func (t *Trie) Remove(text string, kind Kind, typ article.EntityType) {
+	e := entry{kind: kind, typ: typ, text: strings.ToLower(strings.TrimSpace(text))}
+	for _, key := range keys(text) {
+		t.root.remove([]rune(key), e)
+	}
+}
+
+// remove removes the entry from the node of the key below n and reports whether
+// n is empty afterwards.
+func (n *node) remove(key []rune, e entry) bool {
+	if len(key) == 0 {
+		if it, ok := n.items[e]; ok {
+			it.count--
+			if it.count <= 0 {
+				delete(n.items, e)
+			}
+		}
+	} else if child, ok := n.children[key[0]]; ok && child.remove(key[1:], e) {
+		delete(n.children, key[0])
+	}
+	return len(n.items) == 0 && len(n.children) == 0
+}
+
+// Complete returns up to limit texts, that have a word suffix starting with the
+// prefix. If there are less than limit of them, texts starting within the edit
+// distance of the prefix fill up the result, see analysis.Fuzziness. The
+// suggestions are ordered by distance, the most frequent text first. A limit of
+// 0 returns all suggestions.
+func (t *Trie) Complete(prefix string, limit int) []Suggestion {
+	key := []rune(strings.Join(analysis.Terms(prefix), " "))
+	if len(key) == 0 {
+		return nil
+	}
+
+	found := make(map[entry]Suggestion)
+	if n := t.root.find(key); n != nil {
+		n.collect(0, found)
+	}
+	if distance := analysis.Fuzziness(string(key), analysis.MaxDistance); distance > 0 && (limit == 0 || len(found) < limit) {
+		row := make([]int, len(key)+1)
+		for i := range row {
+			row[i] = i
+		}
+		for r, child := range t.root.children {
+			child.fuzzy(key, distance, r, 0, row, nil, distance+1, found)
+		}
+	}
+
+	result := make([]Suggestion, 0, len(found))
+	for _, s := range found {
+		result = append(result, s)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Distance != result[j].Distance {
+			return result[i].Distance < result[j].Distance
+		}
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].Text < result[j].Text
+	})
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	return result
+}
+
+// find returns the node of the key below n or nil.
+func (n *node) find(key []rune) *node {
+	for _, r := range key {
+		child, ok := n.children[r]
+		if !ok {
+			return nil
+		}
+		n = child
+	}
+	return n
+}
+
+// collect adds the entries of n and of the nodes below to found. An entry already
+// found keeps its smaller distance.
+func (n *node) collect(distance int, found map[entry]Suggestion) {
+	n.add(distance, found)
+	for _, child := range n.children {
+		child.collect(distance, found)
+	}
+}
+
+// add adds the entries of n to found. An entry already found keeps its smaller
+// distance.
+func (n *node) add(distance int, found map[entry]Suggestion) {
+	for e, it := range n.items {
+		if s, ok := found[e]; ok && s.Distance <= distance {
+			continue
+		}
+		found[e] = Suggestion{Text: it.text, Kind: e.kind, Type: e.typ, Count: it.count, Distance: distance}
+	}
+}
+
+// fuzzy collects the entries of n and of the nodes below, whose path starts
+// within the distance of the key. The rune r leads from the parent to n, prev is
+// the rune leading to the parent. The rows hold the edit distances of the
+// prefixes of the key to the path of the parent and the grandparent, see
+// analysis.EditDistance. best is the smallest distance of the key to a beginning
+// of the path of the parent.
+func (n *node) fuzzy(key []rune, distance int, r, prev rune, row, prevRow []int, best int, found map[entry]Suggestion) {
+	cur := make([]int, len(key)+1)
+	cur[0] = row[0] + 1
+	rowMin := cur[0]
+	for j := 1; j <= len(key); j++ {
+		cost := 1
+		if key[j-1] == r {
+			cost = 0
+		}
+		cur[j] = min(row[j]+1, cur[j-1]+1, row[j-1]+cost)
+		if prevRow != nil && j > 1 && key[j-1] == prev && key[j-2] == r {
+			cur[j] = min(cur[j], prevRow[j-2]+1)
+		}
+		rowMin = min(rowMin, cur[j])
+	}
+	best = min(best, cur[len(key)])
+
+	if rowMin > distance {
+		// No longer path gets closer to the key.
+		if best <= distance {
+			n.collect(best, found)
+		}
+		return
+	}
+	if best <= distance {
+		n.add(best, found)
+	}
+	for next, child := range n.children {
+		child.fuzzy(key, distance, next, r, cur, row, best, found)
+	}
+}
+
+// keys returns the normalized word suffixes of the text, e.g. "olaf scholz" and
+// "scholz" for "Olaf Scholz".
+func keys(text string) []string {
+	terms := analysis.Terms(text)
+	keys := make([]string, 0, len(terms))
+	for i := range terms {
+		keys = append(keys, strings.Join(terms[i:], " "))
+	}
+	return keys
+}
@@ -0,0 +1,109 @@
+package suggest
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Br0ce/articleDB/pkg/article"
+)
+
+func TestTrie_Complete(t *testing.T) {
+	t.Parallel()
+
+	var trie Trie
+	trie.Add("Talks on the energy crisis", KindTitle, "")
+	trie.Add("Olaf Scholz", KindEntity, article.Person)
+	trie.Add("olaf scholz", KindEntity, article.Person)
+	trie.Add("Scholz & Co", KindEntity, article.Organisation)
+	trie.Add("Schulz", KindEntity, article.Person)
+	trie.Add("EU", KindEntity, article.Organisation)
+
+	tests := []struct {
+		name   string
+		prefix string
+		limit  int
+		want   []Suggestion
+	}{
+		{
+			name:   "prefix of a later word",
+			prefix: "Scho",
+			want: []Suggestion{
+				{Text: "Olaf Scholz", Kind: KindEntity, Type: article.Person, Count: 2},
+				{Text: "Scholz & Co", Kind: KindEntity, Type: article.Organisation, Count: 1},
+				{Text: "Schulz", Kind: KindEntity, Type: article.Person, Count: 1, Distance: 1},
+			},
+		},
+		{
+			name:   "several words",
+			prefix: "energy cr",
+			want: []Suggestion{
+				{Text: "Talks on the energy crisis", Kind: KindTitle, Count: 1},
+			},
+		},
+		{
+			name:   "misspelled",
+			prefix: "enrgy",
+			want: []Suggestion{
+				{Text: "Talks on the energy crisis", Kind: KindTitle, Count: 1, Distance: 1},
+			},
+		},
+		{
+			name:   "transposition",
+			prefix: "olaf shcolz",
+			want: []Suggestion{
+				{Text: "Olaf Scholz", Kind: KindEntity, Type: article.Person, Count: 2, Distance: 1},
+			},
+		},
+		{
+			name:   "exact matches fill the limit",
+			prefix: "scholz",
+			limit:  2,
+			want: []Suggestion{
+				{Text: "Olaf Scholz", Kind: KindEntity, Type: article.Person, Count: 2},
+				{Text: "Scholz & Co", Kind: KindEntity, Type: article.Organisation, Count: 1},
+			},
+		},
+		{
+			name:   "short prefix is not fuzzy",
+			prefix: "ue",
+			want:   []Suggestion{},
+		},
+		{
+			name:   "no words",
+			prefix: " & ",
+			want:   nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := trie.Complete(tt.prefix, tt.limit); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Trie.Complete() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTrie_Remove(t *testing.T) {
+	t.Parallel()
+
+	var trie Trie
+	trie.Add("Olaf Scholz", KindEntity, article.Person)
+	trie.Add("Olaf Scholz", KindEntity, article.Person)
+	trie.Add("Olaf", KindEntity, article.Person)
+
+	trie.Remove("Olaf Scholz", KindEntity, article.Person)
+	want := []Suggestion{{Text: "Olaf Scholz", Kind: KindEntity, Type: article.Person, Count: 1}}
+	if got := trie.Complete("scholz", 0); !reflect.DeepEqual(got, want) {
+		t.Errorf("Trie.Complete() = %+v, want %+v", got, want)
+	}
+
+	trie.Remove("olaf scholz", KindEntity, article.Person)
+	want = []Suggestion{{Text: "Olaf", Kind: KindEntity, Type: article.Person, Count: 1}}
+	if got := trie.Complete("olaf", 0); !reflect.DeepEqual(got, want) {
+		t.Errorf("Trie.Complete() = %+v, want %+v", got, want)
+	}
+	if _, ok := trie.root.children['s']; ok {
+		t.Errorf("Trie.Remove() kept the nodes of the removed text")
+	}
+}
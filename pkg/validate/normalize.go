@@ -0,0 +1,141 @@
+package validate
+
+import (
+	"io"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+	"golang.org/x/text/unicode/norm"
+
+	"github.com/Br0ce/articleDB/pkg/article"
+)
+
+// Normalize returns ar with normalized title, author and body. Leftover html tags
+// are stripped, html entities are decoded, the text is converted to Unicode NFC
+// and the whitespace is collapsed. The paragraphs of the body are kept.
+func Normalize(ar article.Article) article.Article {
+	ar.Title = normalizeLine(ar.Title)
+	ar.Author = normalizeLine(ar.Author)
+	ar.Body = normalizeText(ar.Body)
+	return ar
+}
+
+// normalizeLine normalizes s and collapses all whitespace including line breaks.
+func normalizeLine(s string) string {
+	return strings.Join(strings.Fields(normalizeRunes(s)), " ")
+}
+
+// normalizeText normalizes s and collapses the whitespace within every line. Empty
+// lines are reduced to a single empty line between paragraphs.
+func normalizeText(s string) string {
+	s = normalizeRunes(s)
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	s = strings.ReplaceAll(s, "\r", "\n")
+
+	var lines []string
+	blank := false
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.Join(strings.Fields(line), " ")
+		if line == "" {
+			blank = len(lines) > 0
+			continue
+		}
+		if blank {
+			lines = append(lines, "")
+			blank = false
+		}
+		lines = append(lines, line)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// normalizeRunes strips html, replaces invalid utf-8 and converts s to NFC.
+func normalizeRunes(s string) string {
+	s = stripHTML(s)
+	s = strings.ToValidUTF8(s, string(unicode.ReplacementChar))
+	return norm.NFC.String(s)
+}
+
+// stripHTML removes the html tags of s and decodes its html entities. The content
+// of script and style elements is removed as well. Block elements and line breaks
+// are replaced by a line break, table cells by a space, so the text of adjacent
+// elements is not glued together. A trailing "<", that starts no tag, is kept as
+// text.
+func stripHTML(s string) string {
+	if !strings.ContainsAny(s, "<&") {
+		return s
+	}
+
+	var b strings.Builder
+	skip := 0
+	sep := ""
+	z := html.NewTokenizer(strings.NewReader(s))
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			if z.Err() != io.EOF {
+				return s
+			}
+			// The tokenizer returns an unterminated tag as raw error token.
+			if skip == 0 {
+				writeSeparated(&b, sep, string(z.Raw()))
+			}
+			return b.String()
+		case html.TextToken:
+			if skip == 0 {
+				writeSeparated(&b, sep, string(z.Text()))
+				sep = ""
+			}
+		case html.StartTagToken, html.SelfClosingTagToken, html.EndTagToken:
+			name, _ := z.TagName()
+			switch {
+			case string(name) == "script" || string(name) == "style":
+				if z.Token().Type == html.StartTagToken {
+					skip++
+				} else if skip > 0 {
+					skip--
+				}
+			case blockElements[string(name)]:
+				sep = "\n"
+			case cellElements[string(name)] && sep == "":
+				sep = " "
+			}
+		}
+	}
+}
+
+// writeSeparated writes text to b. The separator is written before, unless b or
+// text already have whitespace at the border.
+func writeSeparated(b *strings.Builder, sep, text string) {
+	if text == "" {
+		return
+	}
+	if sep != "" && b.Len() > 0 {
+		last, _ := utf8.DecodeLastRuneInString(b.String())
+		first, _ := utf8.DecodeRuneInString(text)
+		glued := !unicode.IsSpace(last) && !unicode.IsSpace(first)
+		if sep == "\n" {
+			glued = last != '\n' && first != '\n'
+		}
+		if glued {
+			b.WriteString(sep)
+		}
+	}
+	b.WriteString(text)
+}
+
+// blockElements are the elements, whose content starts and ends a line.
+var blockElements = map[string]bool{
+	"address": true, "article": true, "aside": true, "blockquote": true, "br": true,
+	"dd": true, "div": true, "dl": true, "dt": true, "figcaption": true, "figure": true,
+	"footer": true, "h1": true, "h2": true, "h3": true, "h4": true, "h5": true,
+	"h6": true, "header": true, "hr": true, "li": true, "main": true, "nav": true,
+	"ol": true, "p": true, "pre": true, "section": true, "table": true, "tr": true,
+	"ul": true,
+}
+
+// cellElements are the elements, whose content is separated by a space.
+var cellElements = map[string]bool{"td": true, "th": true}
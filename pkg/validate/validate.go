@@ -0,0 +1,166 @@
+// Package validate checks articles before they are enriched and stored, so no
+// extraction is spent on articles, that can not be stored.
+package validate
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Br0ce/articleDB/pkg/article"
+)
+
+var ErrInvalidArticle = errors.New("invalid article")
+
+// The names of the validated fields match the fields of the wire schema.
+const (
+	FieldTitle     = "title"
+	FieldURL       = "url"
+	FieldAuthor    = "author"
+	FieldPublished = "published_at"
+	FieldBody      = "body"
+	FieldLanguage  = "language"
+)
+
+// FieldError describes why a field of an article is invalid.
+type FieldError struct {
+	Field  string
+	Reason string
+}
+
+func (e FieldError) Error() string {
+	return e.Field + ": " + e.Reason
+}
+
+// Error holds all invalid fields of an article. It wraps ErrInvalidArticle.
+type Error struct {
+	Fields []FieldError
+}
+
+func (e *Error) Error() string {
+	reasons := make([]string, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		reasons = append(reasons, f.Error())
+	}
+	return fmt.Sprintf("%s, %s", strings.Join(reasons, "; "), ErrInvalidArticle.Error())
+}
+
+func (e *Error) Unwrap() error {
+	return ErrInvalidArticle
+}
+
+// Limits are the maximal sizes of the fields in bytes.
+type Limits struct {
+	Title  int
+	Author int
+	Body   int
+}
+
+// DefaultLimits are used, if no limits are set.
+var DefaultLimits = Limits{Title: 1 << 10, Author: 1 << 9, Body: 1 << 20}
+
+// DefaultClockSkew is the tolerance for published dates in the future, because the
+// clocks of the sources are not exact.
+const DefaultClockSkew = 10 * time.Minute
+
+// Validator validates articles.
+type Validator struct {
+	limits Limits
+	skew   time.Duration
+	now    func() time.Time
+}
+
+type Option func(v *Validator)
+
+// WithLimits sets the size limits of the fields. Without it, the DefaultLimits
+// are used.
+func WithLimits(limits Limits) Option {
+	return func(v *Validator) {
+		v.limits = limits
+	}
+}
+
+// WithClockSkew sets the tolerance for published dates in the future. Without it,
+// the DefaultClockSkew is used.
+func WithClockSkew(skew time.Duration) Option {
+	return func(v *Validator) {
+		v.skew = skew
+	}
+}
+
+// WithClock sets the function returning the current time. Without it, time.Now is
+// used.
+func WithClock(now func() time.Time) Option {
+	return func(v *Validator) {
+		v.now = now
+	}
+}
+
+func New(opts ...Option) *Validator {
+	v := &Validator{
+		limits: DefaultLimits,
+		skew:   DefaultClockSkew,
+		now:    time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(v)
+	}
+
+	return v
+}
+
+// Validate returns an *Error with all invalid fields of ar or nil, if ar is valid.
+// Title, addr and body are required, the addr must be an absolute http(s) url, the
+// published date must not be in the future and a set language must be supported.
+func (v *Validator) Validate(ar article.Article) error {
+	var fields []FieldError
+	invalid := func(field, reason string) {
+		fields = append(fields, FieldError{Field: field, Reason: reason})
+	}
+
+	text := []struct {
+		field string
+		value string
+		limit int
+	}{
+		{field: FieldTitle, value: ar.Title, limit: v.limits.Title},
+		{field: FieldAuthor, value: ar.Author, limit: v.limits.Author},
+		{field: FieldBody, value: ar.Body, limit: v.limits.Body},
+	}
+	for _, t := range text {
+		switch {
+		case t.value == "" && t.field != FieldAuthor:
+			invalid(t.field, "is required")
+		case !utf8.ValidString(t.value):
+			invalid(t.field, "is not valid utf-8")
+		case t.limit > 0 && len(t.value) > t.limit:
+			invalid(t.field, fmt.Sprintf("exceeds %d bytes", t.limit))
+		}
+	}
+
+	switch {
+	case ar.Addr == (url.URL{}):
+		invalid(FieldURL, "is required")
+	case !ar.Addr.IsAbs() || ar.Addr.Host == "":
+		invalid(FieldURL, "is not an absolute url")
+	case ar.Addr.Scheme != "http" && ar.Addr.Scheme != "https":
+		invalid(FieldURL, fmt.Sprintf("scheme %q is not http or https", ar.Addr.Scheme))
+	}
+
+	if ar.Published.After(v.now().Add(v.skew)) {
+		invalid(FieldPublished, "is in the future")
+	}
+
+	if lang, err := article.ParseLanguage(string(ar.Language)); err != nil || lang != ar.Language {
+		invalid(FieldLanguage, fmt.Sprintf("%q is not supported", ar.Language))
+	}
+
+	if len(fields) > 0 {
+		return &Error{Fields: fields}
+	}
+	return nil
+}
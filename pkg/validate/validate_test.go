@@ -0,0 +1,229 @@
+package validate
+
+import (
+	"errors"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Br0ce/articleDB/pkg/article"
+)
+
+func TestValidator_Validate(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+	v := New(
+		WithLimits(Limits{Title: 10, Author: 10, Body: 20}),
+		WithClock(func() time.Time { return now }),
+	)
+
+	valid := article.Article{
+		Title:     "Storm",
+		Addr:      url.URL{Scheme: "https", Host: "weather.example.com", Path: "/storm"},
+		Published: now.Add(5 * time.Minute),
+		Body:      "Heavy rain.",
+	}
+
+	tests := []struct {
+		name   string
+		modify func(ar *article.Article)
+		want   []FieldError
+	}{
+		{
+			name:   "valid",
+			modify: func(ar *article.Article) {},
+		},
+		{
+			name: "required",
+			modify: func(ar *article.Article) {
+				*ar = article.Article{}
+			},
+			want: []FieldError{
+				{Field: FieldTitle, Reason: "is required"},
+				{Field: FieldBody, Reason: "is required"},
+				{Field: FieldURL, Reason: "is required"},
+			},
+		},
+		{
+			name: "relative url",
+			modify: func(ar *article.Article) {
+				ar.Addr = url.URL{Path: "/storm"}
+			},
+			want: []FieldError{{Field: FieldURL, Reason: "is not an absolute url"}},
+		},
+		{
+			name: "no http url",
+			modify: func(ar *article.Article) {
+				ar.Addr = url.URL{Scheme: "ftp", Host: "weather.example.com", Path: "/storm"}
+			},
+			want: []FieldError{{Field: FieldURL, Reason: `scheme "ftp" is not http or https`}},
+		},
+		{
+			name: "published in the future",
+			modify: func(ar *article.Article) {
+				ar.Published = now.Add(time.Hour)
+			},
+			want: []FieldError{{Field: FieldPublished, Reason: "is in the future"}},
+		},
+		{
+			name: "unsupported language",
+			modify: func(ar *article.Article) {
+				ar.Language = "fr"
+			},
+			want: []FieldError{{Field: FieldLanguage, Reason: `"fr" is not supported`}},
+		},
+		{
+			name: "too long",
+			modify: func(ar *article.Article) {
+				ar.Title = strings.Repeat("a", 11)
+				ar.Author = strings.Repeat("a", 11)
+				ar.Body = strings.Repeat("a", 21)
+			},
+			want: []FieldError{
+				{Field: FieldTitle, Reason: "exceeds 10 bytes"},
+				{Field: FieldAuthor, Reason: "exceeds 10 bytes"},
+				{Field: FieldBody, Reason: "exceeds 20 bytes"},
+			},
+		},
+		{
+			name: "invalid utf-8",
+			modify: func(ar *article.Article) {
+				ar.Title = "St\xffrm"
+			},
+			want: []FieldError{{Field: FieldTitle, Reason: "is not valid utf-8"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ar := valid
+			tt.modify(&ar)
+
+			err := v.Validate(ar)
+			if tt.want == nil {
+				if err != nil {
+					t.Fatalf("Validator.Validate() error = %v, want nil", err)
+				}
+				return
+			}
+
+			if !errors.Is(err, ErrInvalidArticle) {
+				t.Fatalf("Validator.Validate() error = %v, want %v", err, ErrInvalidArticle)
+			}
+			var verr *Error
+			if !errors.As(err, &verr) {
+				t.Fatalf("Validator.Validate() error = %T, want *Error", err)
+			}
+			if !reflect.DeepEqual(verr.Fields, tt.want) {
+				t.Errorf("Validator.Validate() fields = %+v, want %+v", verr.Fields, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		ar   article.Article
+		want article.Article
+	}{
+		{
+			name: "whitespace",
+			ar: article.Article{
+				Title:  " Storm\twarning\n",
+				Author: "Jane  Doe",
+				Body:   "  Heavy   rain.  \r\n\r\n\r\n\n Floods\texpected. \n",
+			},
+			want: article.Article{
+				Title:  "Storm warning",
+				Author: "Jane Doe",
+				Body:   "Heavy rain.\n\nFloods expected.",
+			},
+		},
+		{
+			name: "html",
+			ar: article.Article{
+				Title: "Storm &amp; rain &#8211; live",
+				Body:  "<p>Heavy <b>rain</b>.</p><script>track()</script>\n<p>AT&T &lt;b&gt; 3 < 4</p>",
+			},
+			want: article.Article{
+				Title: "Storm & rain – live",
+				Body:  "Heavy rain.\nAT&T <b> 3 < 4",
+			},
+		},
+		{
+			name: "unterminated tag",
+			ar: article.Article{
+				Title: "if x<y then we win",
+				Body:  "<p>if x<y then we win",
+			},
+			want: article.Article{
+				Title: "if x<y then we win",
+				Body:  "if x<y then we win",
+			},
+		},
+		{
+			name: "adjacent paragraphs",
+			ar: article.Article{
+				Title: "<h1>Storm</h1><h2>Live</h2>",
+				Body:  "<p>One.</p><p>Two.</p><div>Three.</div>",
+			},
+			want: article.Article{
+				Title: "Storm Live",
+				Body:  "One.\nTwo.\nThree.",
+			},
+		},
+		{
+			name: "line breaks",
+			ar: article.Article{
+				Title: "Tom<br>Jerry",
+				Body:  "Jerry<br>next<br/>last",
+			},
+			want: article.Article{
+				Title: "Tom Jerry",
+				Body:  "Jerry\nnext\nlast",
+			},
+		},
+		{
+			name: "table cells",
+			ar: article.Article{
+				Title: "Prices",
+				Body:  "<table><tr><td>Gas</td><td>5%</td></tr><tr><td>Oil</td><td>3%</td></tr></table>",
+			},
+			want: article.Article{
+				Title: "Prices",
+				Body:  "Gas 5%\nOil 3%",
+			},
+		},
+		{
+			name: "nfc",
+			ar: article.Article{
+				Title: "Cafe\u0301",
+				Body:  "Ge\u0301rald",
+			},
+			want: article.Article{
+				Title: "Caf\u00e9",
+				Body:  "G\u00e9rald",
+			},
+		},
+		{
+			name: "invalid utf-8",
+			ar:   article.Article{Body: "St\xffrm"},
+			want: article.Article{Body: "St�rm"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Normalize(tt.ar)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Normalize() = %q, want %q", []string{got.Title, got.Author, got.Body}, []string{tt.want.Title, tt.want.Author, tt.want.Body})
+			}
+		})
+	}
+}
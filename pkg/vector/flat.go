@@ -0,0 +1,107 @@
+package vector
+
+import (
+	"sort"
+	"sync"
+)
+
+// Flat is an exact Index, that compares the query vector with every stored vector.
+type Flat struct {
+	vecs map[string][]float32
+	dim  int
+	mu   sync.RWMutex
+}
+
+// NewFlat returns an empty Flat index.
+func NewFlat() *Flat {
+	return &Flat{
+		vecs: make(map[string][]float32),
+	}
+}
+
+// Add adds the vector for the given ID. All vectors of the index must have the same
+// dimension, which is set by the first added vector.
+func (f *Flat) Add(id string, vec []float32) error {
+	if len(vec) == 0 {
+		return ErrEmpty
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.dim == 0 {
+		f.dim = len(vec)
+	}
+	if len(vec) != f.dim {
+		return ErrDimension
+	}
+
+	f.vecs[id] = Normalize(vec)
+
+	return nil
+}
+
+// Remove removes the vector of the given ID. An absent ID is ignored.
+func (f *Flat) Remove(id string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	delete(f.vecs, id)
+}
+
+// Search returns up to k hits ordered by decreasing cosine similarity to vec.
+func (f *Flat) Search(vec []float32, k int) ([]Hit, error) {
+	if len(vec) == 0 {
+		return nil, ErrEmpty
+	}
+	if k <= 0 {
+		return nil, nil
+	}
+
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	if len(f.vecs) == 0 {
+		return nil, nil
+	}
+	if len(vec) != f.dim {
+		return nil, ErrDimension
+	}
+
+	query := Normalize(vec)
+	hits := make([]Hit, 0, len(f.vecs))
+	for id, v := range f.vecs {
+		hits = append(hits, Hit{ID: id, Score: dot(query, v)})
+	}
+
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].ID < hits[j].ID
+	})
+
+	if k < len(hits) {
+		hits = hits[:k]
+	}
+
+	return hits, nil
+}
+
+// Len returns the number of stored vectors.
+func (f *Flat) Len() int {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return len(f.vecs)
+}
+
+// dot returns the dot product of a and b, which is the cosine similarity for
+// normalized vectors.
+func dot(a, b []float32) float64 {
+	var sum float64
+	for i := range a {
+		sum += float64(a[i]) * float64(b[i])
+	}
+	return sum
+}
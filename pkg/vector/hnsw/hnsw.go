@@ -0,0 +1,454 @@
+// Package hnsw implements a Hierarchical Navigable Small World graph, an approximate
+// nearest neighbour index for vectors. Searching is logarithmic in the number of
+// stored vectors instead of linear as with a brute force search.
+//
+// See Malkov and Yashunin, "Efficient and robust approximate nearest neighbor search
+// using Hierarchical Navigable Small World graphs", 2016.
+package hnsw
+
+import (
+	"container/heap"
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+
+	"github.com/Br0ce/articleDB/pkg/vector"
+)
+
+const (
+	DefaultM              = 16
+	DefaultEfConstruction = 200
+	DefaultEfSearch       = 64
+)
+
+type node struct {
+	id  string
+	vec []float32
+	// friends holds the neighbours of the node for every layer up to the
+	// level of the node.
+	friends [][]int
+	// deleted is set, if the vector of the node has been replaced or removed. The
+	// node stays in the graph to keep it connected, but is never returned as a hit.
+	deleted bool
+}
+
+// Index is a concurrent-safe HNSW graph. It implements the vector.Index interface.
+// Vectors are compared by cosine similarity.
+type Index struct {
+	// m is the number of neighbours connected to a new node per layer.
+	m int
+	// efConstruction is the size of the candidate list while inserting.
+	efConstruction int
+	// efSearch is the size of the candidate list while searching.
+	efSearch  int
+	levelMult float64
+
+	nodes    []node
+	ids      map[string]int
+	entry    int
+	maxLevel int
+	dim      int
+	rnd      *rand.Rand
+	mu       sync.RWMutex
+}
+
+var _ vector.Index = (*Index)(nil)
+
+type Option func(idx *Index)
+
+// New returns an empty Index. Without options the default parameters are used.
+func New(opts ...Option) *Index {
+	idx := &Index{
+		m:              DefaultM,
+		efConstruction: DefaultEfConstruction,
+		efSearch:       DefaultEfSearch,
+		ids:            make(map[string]int),
+		entry:          -1,
+		rnd:            rand.New(rand.NewSource(1)),
+	}
+
+	for _, opt := range opts {
+		opt(idx)
+	}
+
+	idx.levelMult = 1 / math.Log(float64(idx.m))
+
+	return idx
+}
+
+// WithM sets the number of neighbours per node and layer. Higher values improve the
+// recall for the cost of memory and insertion time. Values smaller than 2 are ignored.
+func WithM(m int) Option {
+	return func(idx *Index) {
+		if m >= 2 {
+			idx.m = m
+		}
+	}
+}
+
+// WithEfConstruction sets the size of the candidate list while inserting. Higher
+// values improve the quality of the graph for the cost of insertion time.
+func WithEfConstruction(ef int) Option {
+	return func(idx *Index) {
+		if ef > 0 {
+			idx.efConstruction = ef
+		}
+	}
+}
+
+// WithEfSearch sets the size of the candidate list while searching. Higher values
+// improve the recall for the cost of search time. The list is never smaller than
+// the number of requested hits.
+func WithEfSearch(ef int) Option {
+	return func(idx *Index) {
+		if ef > 0 {
+			idx.efSearch = ef
+		}
+	}
+}
+
+// WithSeed sets the seed for the random level of new nodes.
+func WithSeed(seed int64) Option {
+	return func(idx *Index) {
+		idx.rnd = rand.New(rand.NewSource(seed))
+	}
+}
+
+// Add adds the vector for the given ID. All vectors of the index must have the same
+// dimension, which is set by the first added vector. An already present vector for
+// the ID is replaced.
+func (idx *Index) Add(id string, vec []float32) error {
+	if len(vec) == 0 {
+		return vector.ErrEmpty
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if idx.dim == 0 {
+		idx.dim = len(vec)
+	}
+	if len(vec) != idx.dim {
+		return vector.ErrDimension
+	}
+
+	if old, ok := idx.ids[id]; ok {
+		idx.nodes[old].deleted = true
+	}
+
+	idx.insert(id, vector.Normalize(vec))
+
+	if len(idx.nodes)-len(idx.ids) > len(idx.ids) {
+		idx.compact()
+	}
+
+	return nil
+}
+
+// Remove removes the vector of the given ID. An absent ID is ignored. The node of
+// the vector stays in the graph, until the graph is compacted.
+func (idx *Index) Remove(id string) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	i, ok := idx.ids[id]
+	if !ok {
+		return
+	}
+	idx.nodes[i].deleted = true
+	delete(idx.ids, id)
+
+	if len(idx.nodes)-len(idx.ids) > len(idx.ids) {
+		idx.compact()
+	}
+}
+
+// insert links a new node of the normalized vector into the graph. The caller must
+// hold the write lock.
+func (idx *Index) insert(id string, vec []float32) {
+	level := int(math.Floor(-math.Log(1-idx.rnd.Float64()) * idx.levelMult))
+	n := node{
+		id:      id,
+		vec:     vec,
+		friends: make([][]int, level+1),
+	}
+	idx.nodes = append(idx.nodes, n)
+	cur := len(idx.nodes) - 1
+	idx.ids[id] = cur
+
+	if idx.entry < 0 {
+		idx.entry = cur
+		idx.maxLevel = level
+		return
+	}
+
+	q := idx.nodes[cur].vec
+	ep := idx.entry
+	for l := idx.maxLevel; l > level; l-- {
+		ep = idx.greedy(q, ep, l)
+	}
+
+	eps := []int{ep}
+	for l := min(level, idx.maxLevel); l >= 0; l-- {
+		candidates := idx.searchLayer(q, eps, idx.efConstruction, l)
+		neighbours := idx.selectNeighbours(candidates, idx.m)
+
+		idx.nodes[cur].friends[l] = neighbours
+		for _, nb := range neighbours {
+			idx.connect(nb, cur, l)
+		}
+
+		eps = eps[:0]
+		for _, c := range candidates {
+			eps = append(eps, c.node)
+		}
+	}
+
+	if level > idx.maxLevel {
+		idx.maxLevel = level
+		idx.entry = cur
+	}
+}
+
+// compact rebuilds the graph from the nodes, that have not been deleted. Deleted
+// nodes take up places in the candidate lists of searches, so the graph is rebuilt
+// once they outnumber the stored vectors. The caller must hold the write lock.
+func (idx *Index) compact() {
+	nodes := idx.nodes
+	idx.nodes = make([]node, 0, len(idx.ids))
+	idx.ids = make(map[string]int, len(idx.ids))
+	idx.entry = -1
+	idx.maxLevel = 0
+
+	for _, n := range nodes {
+		if !n.deleted {
+			idx.insert(n.id, n.vec)
+		}
+	}
+}
+
+// Search returns up to k hits ordered by decreasing cosine similarity to vec.
+func (idx *Index) Search(vec []float32, k int) ([]vector.Hit, error) {
+	if len(vec) == 0 {
+		return nil, vector.ErrEmpty
+	}
+	if k <= 0 {
+		return nil, nil
+	}
+
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	if idx.entry < 0 {
+		return nil, nil
+	}
+	if len(vec) != idx.dim {
+		return nil, vector.ErrDimension
+	}
+
+	q := vector.Normalize(vec)
+	ep := idx.entry
+	for l := idx.maxLevel; l > 0; l-- {
+		ep = idx.greedy(q, ep, l)
+	}
+
+	// Deleted nodes take up places in the candidate list, so the list is extended
+	// by their number, but at most doubled. Add compacts the graph, before the
+	// deleted nodes outnumber the stored vectors.
+	ef := max(idx.efSearch, k)
+	ef += min(len(idx.nodes)-len(idx.ids), ef)
+	candidates := idx.searchLayer(q, []int{ep}, ef, 0)
+
+	hits := make([]vector.Hit, 0, k)
+	for _, c := range candidates {
+		n := idx.nodes[c.node]
+		if n.deleted {
+			continue
+		}
+		hits = append(hits, vector.Hit{ID: n.id, Score: 1 - c.dist})
+		if len(hits) == k {
+			break
+		}
+	}
+
+	return hits, nil
+}
+
+// Len returns the number of stored vectors.
+func (idx *Index) Len() int {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	return len(idx.ids)
+}
+
+// distance returns the cosine distance of the normalized vector q to the node i.
+func (idx *Index) distance(q []float32, i int) float64 {
+	v := idx.nodes[i].vec
+	var dot float64
+	for j := range q {
+		dot += float64(q[j]) * float64(v[j])
+	}
+	return 1 - dot
+}
+
+// greedy walks from ep to the node on layer l, that is closest to q.
+func (idx *Index) greedy(q []float32, ep int, l int) int {
+	cur := ep
+	curDist := idx.distance(q, cur)
+
+	for changed := true; changed; {
+		changed = false
+		for _, nb := range idx.nodes[cur].friends[l] {
+			if d := idx.distance(q, nb); d < curDist {
+				cur, curDist = nb, d
+				changed = true
+			}
+		}
+	}
+
+	return cur
+}
+
+// searchLayer returns up to ef nodes of layer l closest to q, ordered by increasing
+// distance. The search starts at the entry points eps.
+func (idx *Index) searchLayer(q []float32, eps []int, ef int, l int) []candidate {
+	visited := make(map[int]struct{}, ef*idx.m)
+	cands := &minHeap{}
+	results := &maxHeap{}
+
+	for _, ep := range eps {
+		if _, ok := visited[ep]; ok {
+			continue
+		}
+		visited[ep] = struct{}{}
+		c := candidate{node: ep, dist: idx.distance(q, ep)}
+		heap.Push(cands, c)
+		heap.Push(results, c)
+	}
+
+	for cands.Len() > 0 {
+		c := heap.Pop(cands).(candidate)
+		if results.Len() >= ef && c.dist > (*results)[0].dist {
+			break
+		}
+
+		for _, nb := range idx.nodes[c.node].friends[l] {
+			if _, ok := visited[nb]; ok {
+				continue
+			}
+			visited[nb] = struct{}{}
+
+			d := idx.distance(q, nb)
+			if results.Len() < ef || d < (*results)[0].dist {
+				heap.Push(cands, candidate{node: nb, dist: d})
+				heap.Push(results, candidate{node: nb, dist: d})
+				if results.Len() > ef {
+					heap.Pop(results)
+				}
+			}
+		}
+	}
+
+	out := make([]candidate, results.Len())
+	for i := len(out) - 1; i >= 0; i-- {
+		out[i] = heap.Pop(results).(candidate)
+	}
+
+	return out
+}
+
+// selectNeighbours selects up to m neighbours from the candidates ordered by
+// increasing distance. A candidate is only selected, if it is closer to the new node
+// than to all already selected neighbours. This keeps the graph connected across
+// clusters. Remaining places are filled with the closest skipped candidates.
+func (idx *Index) selectNeighbours(candidates []candidate, m int) []int {
+	selected := make([]int, 0, m)
+	var skipped []int
+
+	for _, c := range candidates {
+		if len(selected) == m {
+			break
+		}
+
+		good := true
+		for _, s := range selected {
+			if idx.distance(idx.nodes[c.node].vec, s) < c.dist {
+				good = false
+				break
+			}
+		}
+
+		if good {
+			selected = append(selected, c.node)
+		} else {
+			skipped = append(skipped, c.node)
+		}
+	}
+
+	for _, s := range skipped {
+		if len(selected) == m {
+			break
+		}
+		selected = append(selected, s)
+	}
+
+	return selected
+}
+
+// connect adds the node as neighbour of nb on layer l. If nb then has too many
+// neighbours, the farthest ones are dropped.
+func (idx *Index) connect(nb, node, l int) {
+	friends := append(idx.nodes[nb].friends[l], node)
+
+	limit := idx.m
+	if l == 0 {
+		limit = 2 * idx.m
+	}
+
+	if len(friends) > limit {
+		q := idx.nodes[nb].vec
+		cands := make([]candidate, len(friends))
+		for i, f := range friends {
+			cands[i] = candidate{node: f, dist: idx.distance(q, f)}
+		}
+		sort.Slice(cands, func(i, j int) bool {
+			return cands[i].dist < cands[j].dist
+		})
+		friends = idx.selectNeighbours(cands, limit)
+	}
+
+	idx.nodes[nb].friends[l] = friends
+}
+
+type candidate struct {
+	node int
+	dist float64
+}
+
+type minHeap []candidate
+
+func (h minHeap) Len() int            { return len(h) }
+func (h minHeap) Less(i, j int) bool  { return h[i].dist < h[j].dist }
+func (h minHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *minHeap) Push(x interface{}) { *h = append(*h, x.(candidate)) }
+func (h *minHeap) Pop() interface{} {
+	old := *h
+	c := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return c
+}
+
+type maxHeap []candidate
+
+func (h maxHeap) Len() int            { return len(h) }
+func (h maxHeap) Less(i, j int) bool  { return h[i].dist > h[j].dist }
+func (h maxHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *maxHeap) Push(x interface{}) { *h = append(*h, x.(candidate)) }
+func (h *maxHeap) Pop() interface{} {
+	old := *h
+	c := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return c
+}
@@ -0,0 +1,387 @@
+package hnsw
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"math/rand"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/Br0ce/articleDB/pkg/vector"
+)
+
+func randomVecs(rnd *rand.Rand, num, dim int) [][]float32 {
+	vecs := make([][]float32, num)
+	for i := range vecs {
+		vecs[i] = make([]float32, dim)
+		for j := range vecs[i] {
+			vecs[i][j] = float32(rnd.NormFloat64())
+		}
+	}
+	return vecs
+}
+
+// recall returns the share of the exact k nearest neighbours found by idx,
+// averaged over all queries.
+func recall(t testing.TB, idx vector.Index, exact vector.Index, queries [][]float32, k int) float64 {
+	var found, total int
+	for _, q := range queries {
+		want, err := exact.Search(q, k)
+		if err != nil {
+			t.Fatalf("exact search error = %v", err)
+		}
+		got, err := idx.Search(q, k)
+		if err != nil {
+			t.Fatalf("Index.Search() error = %v", err)
+		}
+
+		ids := make(map[string]struct{}, len(got))
+		for _, h := range got {
+			ids[h.ID] = struct{}{}
+		}
+		for _, h := range want {
+			if _, ok := ids[h.ID]; ok {
+				found++
+			}
+		}
+		total += len(want)
+	}
+
+	return float64(found) / float64(total)
+}
+
+func TestIndex_recall(t *testing.T) {
+	t.Parallel()
+
+	rnd := rand.New(rand.NewSource(42))
+	vecs := randomVecs(rnd, 2000, 32)
+	queries := randomVecs(rnd, 50, 32)
+
+	idx := New(WithSeed(1))
+	exact := vector.NewFlat()
+	for i, v := range vecs {
+		id := fmt.Sprint(i)
+		if err := idx.Add(id, v); err != nil {
+			t.Fatalf("Index.Add() error = %v", err)
+		}
+		if err := exact.Add(id, v); err != nil {
+			t.Fatalf("Flat.Add() error = %v", err)
+		}
+	}
+
+	if idx.Len() != len(vecs) {
+		t.Errorf("Index.Len() = %v, want %v", idx.Len(), len(vecs))
+	}
+
+	if got := recall(t, idx, exact, queries, 10); got < 0.9 {
+		t.Errorf("recall = %v, want at least 0.9", got)
+	}
+}
+
+func TestIndex_Add(t *testing.T) {
+	t.Parallel()
+
+	idx := New()
+
+	if err := idx.Add("a", nil); err != vector.ErrEmpty {
+		t.Errorf("Index.Add() error = %v, want %v", err, vector.ErrEmpty)
+	}
+	if err := idx.Add("a", []float32{1, 0}); err != nil {
+		t.Fatalf("Index.Add() error = %v", err)
+	}
+	if err := idx.Add("b", []float32{1, 0, 0}); err != vector.ErrDimension {
+		t.Errorf("Index.Add() error = %v, want %v", err, vector.ErrDimension)
+	}
+	if err := idx.Add("b", []float32{0, 1}); err != nil {
+		t.Fatalf("Index.Add() error = %v", err)
+	}
+
+	// Replace the vector of a.
+	if err := idx.Add("a", []float32{0, -1}); err != nil {
+		t.Fatalf("Index.Add() error = %v", err)
+	}
+	if idx.Len() != 2 {
+		t.Errorf("Index.Len() = %v, want 2", idx.Len())
+	}
+
+	got, err := idx.Search([]float32{0, -1}, 5)
+	if err != nil {
+		t.Fatalf("Index.Search() error = %v", err)
+	}
+	if len(got) != 2 || got[0].ID != "a" || got[1].ID != "b" {
+		t.Errorf("Index.Search() = %v, want a and b", got)
+	}
+}
+
+func TestIndex_Remove(t *testing.T) {
+	t.Parallel()
+
+	rnd := rand.New(rand.NewSource(9))
+	vecs := randomVecs(rnd, 100, 8)
+	idx := New(WithSeed(1))
+	for i, v := range vecs {
+		if err := idx.Add(fmt.Sprint(i), v); err != nil {
+			t.Fatalf("Index.Add() error = %v", err)
+		}
+	}
+
+	idx.Remove("0")
+	idx.Remove("absent")
+	if idx.Len() != len(vecs)-1 {
+		t.Errorf("Index.Len() = %v, want %v", idx.Len(), len(vecs)-1)
+	}
+	got, err := idx.Search(vecs[0], 5)
+	if err != nil {
+		t.Fatalf("Index.Search() error = %v", err)
+	}
+	for _, h := range got {
+		if h.ID == "0" {
+			t.Errorf("Index.Search() = %v, found removed vector", got)
+		}
+	}
+
+	// Removing most vectors compacts the graph.
+	for i := 1; i < 90; i++ {
+		idx.Remove(fmt.Sprint(i))
+	}
+	if idx.Len() != 10 || len(idx.nodes) > 2*idx.Len() {
+		t.Errorf("Index has %v nodes for %v vectors, want at most twice as many", len(idx.nodes), idx.Len())
+	}
+	got, err = idx.Search(vecs[95], 1)
+	if err != nil {
+		t.Fatalf("Index.Search() error = %v", err)
+	}
+	if len(got) != 1 || got[0].ID != "95" {
+		t.Errorf("Index.Search() = %v, want 95", got)
+	}
+
+	// Re-adding a removed ID stores it again.
+	if err := idx.Add("0", vecs[0]); err != nil {
+		t.Fatalf("Index.Add() error = %v", err)
+	}
+	if idx.Len() != 11 {
+		t.Errorf("Index.Len() = %v, want 11", idx.Len())
+	}
+}
+
+func TestIndex_Add_parallel(t *testing.T) {
+	t.Parallel()
+
+	rnd := rand.New(rand.NewSource(7))
+	vecs := randomVecs(rnd, 500, 16)
+	idx := New()
+
+	eg := new(errgroup.Group)
+	for i, v := range vecs {
+		i, v := i, v
+		eg.Go(func() error {
+			return idx.Add(fmt.Sprint(i), v)
+		})
+		eg.Go(func() error {
+			_, err := idx.Search(v, 3)
+			return err
+		})
+	}
+
+	if err := eg.Wait(); err != nil {
+		t.Fatalf("finished with err, %s", err.Error())
+	}
+
+	if idx.Len() != len(vecs) {
+		t.Errorf("Index.Len() = %v, want %v", idx.Len(), len(vecs))
+	}
+}
+
+func TestIndex_SaveAndLoad(t *testing.T) {
+	t.Parallel()
+
+	rnd := rand.New(rand.NewSource(3))
+	vecs := randomVecs(rnd, 300, 8)
+	queries := randomVecs(rnd, 10, 8)
+
+	idx := New(WithM(8), WithEfSearch(32))
+	for i, v := range vecs {
+		if err := idx.Add(fmt.Sprint(i), v); err != nil {
+			t.Fatalf("Index.Add() error = %v", err)
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "index.hnsw")
+	if err := idx.SaveFile(path); err != nil {
+		t.Fatalf("Index.SaveFile() error = %v", err)
+	}
+
+	loaded, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+
+	if loaded.Len() != idx.Len() || loaded.m != 8 || loaded.efSearch != 32 {
+		t.Errorf("loaded index differs, len %v m %v efSearch %v", loaded.Len(), loaded.m, loaded.efSearch)
+	}
+
+	for _, q := range queries {
+		want, err := idx.Search(q, 5)
+		if err != nil {
+			t.Fatalf("Index.Search() error = %v", err)
+		}
+		got, err := loaded.Search(q, 5)
+		if err != nil {
+			t.Fatalf("Index.Search() error = %v", err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("loaded Index.Search() = %v, want %v", got, want)
+		}
+	}
+
+	if err := loaded.Add("new", queries[0]); err != nil {
+		t.Errorf("Index.Add() after load error = %v", err)
+	}
+
+	if _, err := Load(bytes.NewReader([]byte("no index"))); err == nil {
+		t.Error("Load() without error for invalid input")
+	}
+}
+
+func TestIndex_Add_compact(t *testing.T) {
+	t.Parallel()
+
+	rnd := rand.New(rand.NewSource(5))
+	queries := randomVecs(rnd, 20, 16)
+
+	idx := New(WithSeed(1))
+	exact := vector.NewFlat()
+	for round := 0; round < 5; round++ {
+		for i, v := range randomVecs(rnd, 200, 16) {
+			id := fmt.Sprint(i)
+			if err := idx.Add(id, v); err != nil {
+				t.Fatalf("Index.Add() error = %v", err)
+			}
+			if err := exact.Add(id, v); err != nil {
+				t.Fatalf("Flat.Add() error = %v", err)
+			}
+		}
+	}
+
+	if idx.Len() != 200 || len(idx.nodes) > 2*idx.Len() {
+		t.Errorf("Index has %v nodes for %v vectors, want at most twice as many", len(idx.nodes), idx.Len())
+	}
+	if got := recall(t, idx, exact, queries, 10); got < 0.9 {
+		t.Errorf("recall = %v, want at least 0.9", got)
+	}
+}
+
+func TestLoad_invalid(t *testing.T) {
+	t.Parallel()
+
+	valid := func() snapshotDTO {
+		return snapshotDTO{
+			Version:        formatVersion,
+			M:              2,
+			EfConstruction: 10,
+			EfSearch:       10,
+			Dim:            2,
+			Entry:          0,
+			MaxLevel:       1,
+			Nodes: []nodeDTO{
+				{ID: "a", Vec: []float32{1, 0}, Friends: [][]int{{1}, {}}},
+				{ID: "b", Vec: []float32{0, 1}, Friends: [][]int{{0}}},
+			},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(snap *snapshotDTO)
+		wantErr bool
+	}{
+		{name: "valid", modify: func(snap *snapshotDTO) {}},
+		{name: "empty", modify: func(snap *snapshotDTO) { snap.Nodes, snap.Entry, snap.Dim = nil, -1, 0 }},
+		{name: "version", modify: func(snap *snapshotDTO) { snap.Version = 0 }, wantErr: true},
+		{name: "m less than 2", modify: func(snap *snapshotDTO) { snap.M = 1 }, wantErr: true},
+		{name: "ef less than 1", modify: func(snap *snapshotDTO) { snap.EfSearch = 0 }, wantErr: true},
+		{name: "entry out of range", modify: func(snap *snapshotDTO) { snap.Entry = 2 }, wantErr: true},
+		{name: "negative entry", modify: func(snap *snapshotDTO) { snap.Entry = -1 }, wantErr: true},
+		{name: "entry below max level", modify: func(snap *snapshotDTO) { snap.Entry = 1 }, wantErr: true},
+		{name: "vector dimension", modify: func(snap *snapshotDTO) { snap.Nodes[1].Vec = []float32{1} }, wantErr: true},
+		{name: "no level", modify: func(snap *snapshotDTO) { snap.Nodes[1].Friends = nil }, wantErr: true},
+		{name: "level above max level", modify: func(snap *snapshotDTO) { snap.Nodes[1].Friends = [][]int{{0}, {0}, {0}} }, wantErr: true},
+		{name: "neighbour out of range", modify: func(snap *snapshotDTO) { snap.Nodes[0].Friends[0] = []int{5} }, wantErr: true},
+		{name: "neighbour below level", modify: func(snap *snapshotDTO) { snap.Nodes[0].Friends[1] = []int{1} }, wantErr: true},
+		{name: "duplicate id", modify: func(snap *snapshotDTO) { snap.Nodes[1].ID = "a" }, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			snap := valid()
+			tt.modify(&snap)
+			var buf bytes.Buffer
+			if err := gob.NewEncoder(&buf).Encode(snap); err != nil {
+				t.Fatalf("could not encode snapshot, %s", err.Error())
+			}
+
+			idx, err := Load(&buf)
+			if !tt.wantErr {
+				if err != nil {
+					t.Fatalf("Load() error = %v", err)
+				}
+				if _, err := idx.Search([]float32{1, 1}, 2); err != nil {
+					t.Errorf("Index.Search() error = %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrInvalidFormat) {
+				t.Errorf("Load() error = %v, want %v", err, ErrInvalidFormat)
+			}
+		})
+	}
+}
+
+func BenchmarkRecall(b *testing.B) {
+	rnd := rand.New(rand.NewSource(42))
+	vecs := randomVecs(rnd, 10000, 64)
+	queries := randomVecs(rnd, 100, 64)
+	k := 10
+
+	exact := vector.NewFlat()
+	for i, v := range vecs {
+		if err := exact.Add(fmt.Sprint(i), v); err != nil {
+			b.Fatalf("Flat.Add() error = %v", err)
+		}
+	}
+
+	idx := New()
+	for i, v := range vecs {
+		if err := idx.Add(fmt.Sprint(i), v); err != nil {
+			b.Fatalf("Index.Add() error = %v", err)
+		}
+	}
+
+	for _, ef := range []int{16, 64, 128} {
+		idx.efSearch = ef
+
+		b.Run(fmt.Sprintf("hnsw/efSearch=%d", ef), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := idx.Search(queries[i%len(queries)], k); err != nil {
+					b.Fatalf("Index.Search() error = %v", err)
+				}
+			}
+			b.StopTimer()
+			b.ReportMetric(recall(b, idx, exact, queries, k), "recall")
+		})
+	}
+
+	b.Run("flat", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := exact.Search(queries[i%len(queries)], k); err != nil {
+				b.Fatalf("Flat.Search() error = %v", err)
+			}
+		}
+		b.ReportMetric(1, "recall")
+	})
+}
@@ -0,0 +1,194 @@
+package hnsw
+
+import (
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"os"
+)
+
+// formatVersion is the version of the persisted format. It has to be increased on
+// every incompatible change.
+const formatVersion = 1
+
+var ErrInvalidFormat = errors.New("invalid hnsw format")
+
+type snapshotDTO struct {
+	Version        int
+	M              int
+	EfConstruction int
+	EfSearch       int
+	Dim            int
+	Entry          int
+	MaxLevel       int
+	Nodes          []nodeDTO
+}
+
+type nodeDTO struct {
+	ID      string
+	Vec     []float32
+	Friends [][]int
+	Deleted bool
+}
+
+// Save writes the index to w.
+func (idx *Index) Save(w io.Writer) error {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	snap := snapshotDTO{
+		Version:        formatVersion,
+		M:              idx.m,
+		EfConstruction: idx.efConstruction,
+		EfSearch:       idx.efSearch,
+		Dim:            idx.dim,
+		Entry:          idx.entry,
+		MaxLevel:       idx.maxLevel,
+		Nodes:          make([]nodeDTO, len(idx.nodes)),
+	}
+	for i, n := range idx.nodes {
+		snap.Nodes[i] = nodeDTO{ID: n.id, Vec: n.vec, Friends: n.friends, Deleted: n.deleted}
+	}
+
+	return gob.NewEncoder(w).Encode(snap)
+}
+
+// Load reads an index written by Save from r. The given options are applied after
+// loading, so the search parameters can be changed. Options changing the structure
+// of the graph only apply to nodes added afterwards. A snapshot, that is not a
+// consistent graph, is rejected with ErrInvalidFormat.
+func Load(r io.Reader, opts ...Option) (*Index, error) {
+	var snap snapshotDTO
+	err := gob.NewDecoder(r).Decode(&snap)
+	if err != nil {
+		return nil, fmt.Errorf("%s, %w", err.Error(), ErrInvalidFormat)
+	}
+
+	err = snap.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	idx := &Index{
+		m:              snap.M,
+		efConstruction: snap.EfConstruction,
+		efSearch:       snap.EfSearch,
+		dim:            snap.Dim,
+		entry:          snap.Entry,
+		maxLevel:       snap.MaxLevel,
+		nodes:          make([]node, len(snap.Nodes)),
+		ids:            make(map[string]int, len(snap.Nodes)),
+		rnd:            rand.New(rand.NewSource(1)),
+	}
+	if len(snap.Nodes) == 0 {
+		idx.entry = -1
+		idx.maxLevel = 0
+	}
+
+	for i, n := range snap.Nodes {
+		idx.nodes[i] = node{id: n.ID, vec: n.Vec, friends: n.Friends, deleted: n.Deleted}
+		if !n.Deleted {
+			idx.ids[n.ID] = i
+		}
+	}
+
+	for _, opt := range opts {
+		opt(idx)
+	}
+	idx.levelMult = 1 / math.Log(float64(idx.m))
+
+	return idx, nil
+}
+
+// validate checks, that the snapshot is a graph the index can search without
+// running out of range.
+func (snap snapshotDTO) validate() error {
+	if snap.Version != formatVersion {
+		return fmt.Errorf("unsupported version %d, %w", snap.Version, ErrInvalidFormat)
+	}
+	if snap.M < 2 {
+		return fmt.Errorf("m %d is less than 2, %w", snap.M, ErrInvalidFormat)
+	}
+	if snap.EfConstruction < 1 || snap.EfSearch < 1 {
+		return fmt.Errorf("ef is less than 1, %w", ErrInvalidFormat)
+	}
+	if len(snap.Nodes) == 0 {
+		return nil
+	}
+
+	if snap.Dim < 1 {
+		return fmt.Errorf("dimension %d is less than 1, %w", snap.Dim, ErrInvalidFormat)
+	}
+	if snap.MaxLevel < 0 {
+		return fmt.Errorf("max level %d is negative, %w", snap.MaxLevel, ErrInvalidFormat)
+	}
+	if snap.Entry < 0 || snap.Entry >= len(snap.Nodes) {
+		return fmt.Errorf("entry point out of range, %w", ErrInvalidFormat)
+	}
+	if len(snap.Nodes[snap.Entry].Friends) != snap.MaxLevel+1 {
+		return fmt.Errorf("entry point is not on the max level, %w", ErrInvalidFormat)
+	}
+
+	ids := make(map[string]bool, len(snap.Nodes))
+	for i, n := range snap.Nodes {
+		if len(n.Vec) != snap.Dim {
+			return fmt.Errorf("node %d has dimension %d, want %d, %w", i, len(n.Vec), snap.Dim, ErrInvalidFormat)
+		}
+		if len(n.Friends) < 1 || len(n.Friends) > snap.MaxLevel+1 {
+			return fmt.Errorf("node %d has %d levels, want 1 to %d, %w", i, len(n.Friends), snap.MaxLevel+1, ErrInvalidFormat)
+		}
+		for l, friends := range n.Friends {
+			for _, f := range friends {
+				if f < 0 || f >= len(snap.Nodes) || len(snap.Nodes[f].Friends) <= l {
+					return fmt.Errorf("neighbour of node %d out of range, %w", i, ErrInvalidFormat)
+				}
+			}
+		}
+		if !n.Deleted {
+			if ids[n.ID] {
+				return fmt.Errorf("duplicate id %q, %w", n.ID, ErrInvalidFormat)
+			}
+			ids[n.ID] = true
+		}
+	}
+
+	return nil
+}
+
+// SaveFile writes the index to the file at path. The file is replaced atomically,
+// so a crash while saving does not corrupt an earlier saved index.
+func (idx *Index) SaveFile(path string) error {
+	tmp := path + ".tmp"
+
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+
+	err = idx.Save(f)
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	err = f.Close()
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, path)
+}
+
+// LoadFile reads an index written by SaveFile from the file at path.
+func LoadFile(path string, opts ...Option) (*Index, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return Load(f, opts...)
+}
@@ -0,0 +1,82 @@
+// Package vector provides similarity functions and indexes for dense vectors such
+// as article embeddings.
+package vector
+
+import (
+	"context"
+	"errors"
+	"math"
+)
+
+var (
+	ErrDimension = errors.New("vector dimension mismatch")
+	ErrEmpty     = errors.New("vector is empty")
+)
+
+// Hit is a result of a nearest neighbour search.
+type Hit struct {
+	ID string
+	// Score is the cosine similarity of the hit to the query vector.
+	Score float64
+}
+
+// Index stores vectors by ID and finds the nearest neighbours of a query vector.
+type Index interface {
+	// Add adds the vector for the given ID. An already present vector for the ID is
+	// replaced.
+	Add(id string, vec []float32) error
+	// Remove removes the vector of the given ID. An absent ID is ignored.
+	Remove(id string)
+	// Search returns up to k hits ordered by decreasing cosine similarity to vec.
+	Search(vec []float32, k int) ([]Hit, error)
+	// Len returns the number of stored vectors.
+	Len() int
+}
+
+// Embedder maps a text to its embedding. The embeddings of an index must be created
+// by the same Embedder.
+type Embedder interface {
+	Embed(ctx context.Context, text string) ([]float32, error)
+}
+
+// Cosine returns the cosine similarity of a and b. It is 0 if the vectors have
+// different dimensions or one of them has zero length.
+func Cosine(a, b []float32) float64 {
+	if len(a) != len(b) {
+		return 0
+	}
+
+	var dot, na, nb float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		na += float64(a[i]) * float64(a[i])
+		nb += float64(b[i]) * float64(b[i])
+	}
+
+	if na == 0 || nb == 0 {
+		return 0
+	}
+
+	return dot / (math.Sqrt(na) * math.Sqrt(nb))
+}
+
+// Normalize returns a copy of vec scaled to unit length. A zero vector is returned
+// as a zero vector.
+func Normalize(vec []float32) []float32 {
+	var sum float64
+	for _, v := range vec {
+		sum += float64(v) * float64(v)
+	}
+
+	out := make([]float32, len(vec))
+	if sum == 0 {
+		return out
+	}
+
+	norm := math.Sqrt(sum)
+	for i, v := range vec {
+		out[i] = float32(float64(v) / norm)
+	}
+
+	return out
+}
@@ -0,0 +1,76 @@
+package vector
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCosine(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		a    []float32
+		b    []float32
+		want float64
+	}{
+		{name: "same direction", a: []float32{1, 2}, b: []float32{2, 4}, want: 1},
+		{name: "orthogonal", a: []float32{1, 0}, b: []float32{0, 3}, want: 0},
+		{name: "opposite", a: []float32{1, 1}, b: []float32{-1, -1}, want: -1},
+		{name: "zero vector", a: []float32{0, 0}, b: []float32{1, 1}, want: 0},
+		{name: "dimension mismatch", a: []float32{1, 0}, b: []float32{1, 0, 0}, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Cosine(tt.a, tt.b); math.Abs(got-tt.want) > 1e-6 {
+				t.Errorf("Cosine() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFlat(t *testing.T) {
+	t.Parallel()
+
+	f := NewFlat()
+	vecs := map[string][]float32{
+		"x":  {1, 0, 0},
+		"y":  {0, 1, 0},
+		"xy": {1, 1, 0},
+	}
+	for id, vec := range vecs {
+		if err := f.Add(id, vec); err != nil {
+			t.Fatalf("Flat.Add() error = %v", err)
+		}
+	}
+
+	if err := f.Add("z", []float32{0, 1}); err != ErrDimension {
+		t.Errorf("Flat.Add() error = %v, want %v", err, ErrDimension)
+	}
+	if f.Len() != len(vecs) {
+		t.Errorf("Flat.Len() = %v, want %v", f.Len(), len(vecs))
+	}
+
+	got, err := f.Search([]float32{2, 0.1, 0}, 2)
+	if err != nil {
+		t.Fatalf("Flat.Search() error = %v", err)
+	}
+	if len(got) != 2 || got[0].ID != "x" || got[1].ID != "xy" {
+		t.Errorf("Flat.Search() = %v, want x and xy", got)
+	}
+
+	if _, err := f.Search([]float32{1, 0}, 2); err != ErrDimension {
+		t.Errorf("Flat.Search() error = %v, want %v", err, ErrDimension)
+	}
+
+	f.Remove("x")
+	f.Remove("absent")
+	got, err = f.Search([]float32{2, 0.1, 0}, 1)
+	if err != nil {
+		t.Fatalf("Flat.Search() error = %v", err)
+	}
+	if f.Len() != 2 || len(got) != 1 || got[0].ID != "xy" {
+		t.Errorf("Flat.Search() after Flat.Remove() = %v, len %v, want xy and 2", got, f.Len())
+	}
+}
@@ -0,0 +1,169 @@
+package warc
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/Br0ce/articleDB/pkg/article"
+	"github.com/Br0ce/articleDB/pkg/db"
+	"github.com/Br0ce/articleDB/pkg/readability"
+)
+
+// SourceKind is the kind of the source of articles ingested from WARC files.
+const SourceKind = "warc"
+
+// errNoPage is returned for records, that are no successful html responses.
+var errNoPage = errors.New("no html page")
+
+// Adder adds an article, e.g. the adder.Adder, that extracts the features of the
+// article before storing it.
+type Adder interface {
+	Add(ctx context.Context, ar article.Article) (string, error)
+}
+
+// Summary counts the outcomes of an import.
+type Summary struct {
+	// Records is the number of read records.
+	Records int
+	// Pages is the number of successful html responses.
+	Pages int
+	// Added is the number of added articles.
+	Added int
+	// Skipped is the number of pages without article and of duplicates.
+	Skipped int
+	// Failed is the number of pages, that could not be added.
+	Failed int
+}
+
+// Importer ingests the article pages of WARC files.
+type Importer struct {
+	adder Adder
+	log   *slog.Logger
+}
+
+// NewImporter returns an Importer, that adds the articles with adder.
+func NewImporter(adder Adder, log *slog.Logger) *Importer {
+	return &Importer{adder: adder, log: log}
+}
+
+// Import reads the WARC file in r and adds the main article of every captured html
+// page. Pages without article and duplicates are skipped, pages, that can not be
+// added, are logged and counted as failed. Import only fails, if r is no valid WARC
+// file or ctx is done.
+func (i *Importer) Import(ctx context.Context, r io.Reader) (Summary, error) {
+	i.log.Info("start warc import", "method", "Import")
+
+	rd, err := NewReader(r)
+	if err != nil {
+		return Summary{}, err
+	}
+
+	var sum Summary
+	for {
+		if err := ctx.Err(); err != nil {
+			return sum, err
+		}
+
+		rec, err := rd.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return sum, err
+		}
+		sum.Records++
+
+		if rec.Type != "response" {
+			continue
+		}
+
+		ar, err := page(rec)
+		if errors.Is(err, errNoPage) {
+			continue
+		}
+		sum.Pages++
+		if errors.Is(err, readability.ErrNoContent) {
+			sum.Skipped++
+			continue
+		}
+		if err != nil {
+			i.log.Error("could not read page", "method", "Import", "record", rec.ID, "addr", rec.TargetURI, "err", err.Error())
+			sum.Failed++
+			continue
+		}
+
+		_, err = i.adder.Add(ctx, ar)
+		switch {
+		case err == nil:
+			sum.Added++
+		case errors.Is(err, db.ErrAlreadyExists):
+			sum.Skipped++
+		default:
+			i.log.Error("could not add article", "method", "Import", "record", rec.ID, "addr", rec.TargetURI, "err", err.Error())
+			sum.Failed++
+		}
+	}
+
+	i.log.Info("warc import done", "method", "Import",
+		"records", sum.Records, "pages", sum.Pages, "added", sum.Added, "skipped", sum.Skipped, "failed", sum.Failed)
+
+	return sum, nil
+}
+
+// page returns the article of the response record. It returns errNoPage, if the
+// record is no successful html response, and readability.ErrNoContent, if the page
+// has no article.
+func page(rec Record) (article.Article, error) {
+	mediaType, _, _ := mime.ParseMediaType(rec.Header.Get("Content-Type"))
+	if mediaType != "application/http" {
+		return article.Article{}, errNoPage
+	}
+
+	addr, err := url.Parse(rec.TargetURI)
+	if err != nil || (addr.Scheme != "http" && addr.Scheme != "https") {
+		return article.Article{}, errNoPage
+	}
+
+	resp, err := http.ReadResponse(bufio.NewReader(rec.Block), nil)
+	if err != nil {
+		return article.Article{}, fmt.Errorf("%s, %w", err.Error(), ErrInvalidRecord)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return article.Article{}, errNoPage
+	}
+	mediaType, _, err = mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if err != nil || (mediaType != "text/html" && mediaType != "application/xhtml+xml") {
+		return article.Article{}, errNoPage
+	}
+
+	body := io.Reader(resp.Body)
+	if strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		zr, err := gzip.NewReader(body)
+		if err != nil {
+			return article.Article{}, err
+		}
+		defer zr.Close()
+		body = zr
+	}
+
+	doc, err := readability.Extract(body)
+	if err != nil {
+		return article.Article{}, err
+	}
+
+	ar := doc.Article(*addr)
+	ar.Source = article.Source{Kind: SourceKind, ID: rec.ID, Captured: rec.Date}
+
+	return ar, nil
+}
@@ -0,0 +1,77 @@
+package warc
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/Br0ce/articleDB/pkg/article"
+	"github.com/Br0ce/articleDB/pkg/db"
+	"github.com/Br0ce/articleDB/pkg/logger"
+	"github.com/Br0ce/articleDB/pkg/mock"
+)
+
+func TestImporter_Import(t *testing.T) {
+	t.Parallel()
+
+	f, err := os.Open("testdata/example.warc")
+	if err != nil {
+		t.Fatalf("could not open fixture, %s", err.Error())
+	}
+	defer f.Close()
+
+	var added []article.Article
+	adder := &mock.Adder{AddFn: func(ctx context.Context, ar article.Article) (string, error) {
+		if ar.Addr.Host == "weather.example.com" {
+			return "", db.ErrAlreadyExists
+		}
+		added = append(added, ar)
+		return "1234", nil
+	}}
+
+	got, err := NewImporter(adder, logger.NewTest(false)).Import(context.TODO(), f)
+	if err != nil {
+		t.Fatalf("Importer.Import() error = %v", err)
+	}
+
+	want := Summary{Records: 8, Pages: 3, Added: 1, Skipped: 2}
+	if got != want {
+		t.Errorf("Importer.Import() = %+v, want %+v", got, want)
+	}
+
+	if len(added) != 1 {
+		t.Fatalf("added %v articles, want 1", len(added))
+	}
+	ar := added[0]
+	if ar.Title != "Energy prices keep rising" || ar.Addr.String() != "https://news.example.com/energy-prices" {
+		t.Errorf("added article = %+v", ar)
+	}
+	wantSource := article.Source{
+		Kind:     SourceKind,
+		ID:       "urn:uuid:6a1b0c4e-0000-4000-8000-000000000003",
+		Captured: time.Date(2023, 6, 1, 12, 0, 2, 0, time.UTC),
+	}
+	if ar.Source != wantSource {
+		t.Errorf("Article.Source = %+v, want %+v", ar.Source, wantSource)
+	}
+}
+
+func TestImporter_Import_invalid(t *testing.T) {
+	t.Parallel()
+
+	f, err := os.Open("../readability/testdata/jsonld.html")
+	if err != nil {
+		t.Fatalf("could not open fixture, %s", err.Error())
+	}
+	defer f.Close()
+
+	adder := &mock.Adder{}
+	_, err = NewImporter(adder, logger.NewTest(false)).Import(context.TODO(), f)
+	if err == nil {
+		t.Error("Importer.Import() without error")
+	}
+	if adder.AddInvoked {
+		t.Error("Adder.Add() invoked")
+	}
+}
@@ -0,0 +1,112 @@
+// Package warc reads web archives in the WARC format (ISO 28500) and ingests the
+// article pages they contain. Uncompressed and record-wise gzip compressed files
+// are supported.
+package warc
+
+import (
+	"bufio"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidRecord = errors.New("invalid warc record")
+
+// Record is a record of a WARC file.
+type Record struct {
+	// Type is the WARC-Type, e.g. "response" or "request".
+	Type string
+	// ID is the WARC-Record-ID without the enclosing angle brackets.
+	ID string
+	// Date is the WARC-Date, the time the content was captured.
+	Date time.Time
+	// TargetURI is the WARC-Target-URI, the url of the captured content.
+	TargetURI string
+	Header    textproto.MIMEHeader
+	// Block is the content of the record. It is only valid until the next call of
+	// Reader.Next.
+	Block io.Reader
+}
+
+// Reader reads the records of a WARC file.
+type Reader struct {
+	r     *bufio.Reader
+	tp    *textproto.Reader
+	block *io.LimitedReader
+}
+
+// NewReader returns a Reader for the WARC file in r. Gzip compressed files are
+// decompressed.
+func NewReader(r io.Reader) (*Reader, error) {
+	br := bufio.NewReader(r)
+	if magic, err := br.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		zr, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+		br = bufio.NewReader(zr)
+	}
+
+	return &Reader{r: br, tp: textproto.NewReader(br)}, nil
+}
+
+// Next returns the next record. It returns io.EOF, if there are no more records.
+func (r *Reader) Next() (Record, error) {
+	// The unread rest of the previous block is skipped.
+	if r.block != nil {
+		_, err := io.Copy(io.Discard, r.block)
+		if err != nil {
+			return Record{}, err
+		}
+		r.block = nil
+	}
+
+	version, err := r.versionLine()
+	if err != nil {
+		return Record{}, err
+	}
+	if !strings.HasPrefix(version, "WARC/") {
+		return Record{}, fmt.Errorf("version line %q, %w", version, ErrInvalidRecord)
+	}
+
+	header, err := r.tp.ReadMIMEHeader()
+	if err != nil {
+		return Record{}, fmt.Errorf("%s, %w", err.Error(), ErrInvalidRecord)
+	}
+
+	length, err := strconv.ParseInt(header.Get("Content-Length"), 10, 64)
+	if err != nil || length < 0 {
+		return Record{}, fmt.Errorf("content length %q, %w", header.Get("Content-Length"), ErrInvalidRecord)
+	}
+
+	date, _ := time.Parse(time.RFC3339Nano, header.Get("WARC-Date"))
+	r.block = &io.LimitedReader{R: r.r, N: length}
+
+	return Record{
+		Type:      header.Get("WARC-Type"),
+		ID:        strings.Trim(header.Get("WARC-Record-ID"), "<>"),
+		Date:      date.UTC(),
+		TargetURI: strings.Trim(header.Get("WARC-Target-URI"), "<>"),
+		Header:    header,
+		Block:     r.block,
+	}, nil
+}
+
+// versionLine returns the first line of the next record. The empty lines separating
+// two records are skipped.
+func (r *Reader) versionLine() (string, error) {
+	for {
+		line, err := r.tp.ReadLine()
+		if err != nil {
+			return "", err
+		}
+		if line != "" {
+			return line, nil
+		}
+	}
+}
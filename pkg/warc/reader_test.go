@@ -0,0 +1,141 @@
+package warc
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// gzipRecords compresses every record of the WARC file as its own gzip member, like
+// .warc.gz files do.
+func gzipRecords(t *testing.T, data []byte) []byte {
+	var buf bytes.Buffer
+	for _, rec := range bytes.SplitAfter(data, []byte("\r\n\r\nWARC/")) {
+		zw := gzip.NewWriter(&buf)
+		if _, err := zw.Write(rec); err != nil {
+			t.Fatalf("could not compress, %s", err.Error())
+		}
+		if err := zw.Close(); err != nil {
+			t.Fatalf("could not compress, %s", err.Error())
+		}
+	}
+	return buf.Bytes()
+}
+
+func TestReader_Next(t *testing.T) {
+	t.Parallel()
+
+	data, err := os.ReadFile("testdata/example.warc")
+	if err != nil {
+		t.Fatalf("could not read fixture, %s", err.Error())
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "plain", data: data},
+		{name: "gzip", data: gzipRecords(t, data)},
+	}
+
+	wantTypes := []string{"warcinfo", "request", "response", "response", "response", "response", "response", "metadata"}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := NewReader(bytes.NewReader(tt.data))
+			if err != nil {
+				t.Fatalf("NewReader() error = %v", err)
+			}
+
+			var types []string
+			var last Record
+			for {
+				rec, err := r.Next()
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				if err != nil {
+					t.Fatalf("Reader.Next() error = %v", err)
+				}
+				types = append(types, rec.Type)
+				if rec.Type == "response" {
+					last = rec
+				}
+			}
+
+			if !reflect.DeepEqual(types, wantTypes) {
+				t.Errorf("record types = %v, want %v", types, wantTypes)
+			}
+			if last.ID != "urn:uuid:6a1b0c4e-0000-4000-8000-000000000007" {
+				t.Errorf("Record.ID = %v", last.ID)
+			}
+			if want := time.Date(2023, 6, 2, 8, 30, 0, 5e8, time.UTC); !last.Date.Equal(want) {
+				t.Errorf("Record.Date = %v, want %v", last.Date, want)
+			}
+			if last.TargetURI != "https://weather.example.com/storm-warning" {
+				t.Errorf("Record.TargetURI = %v", last.TargetURI)
+			}
+		})
+	}
+}
+
+func TestReader_Next_block(t *testing.T) {
+	t.Parallel()
+
+	data := "WARC/1.0\r\nWARC-Type: resource\r\nContent-Length: 5\r\n\r\nhello\r\n\r\n" +
+		"WARC/1.0\r\nWARC-Type: resource\r\nContent-Length: 5\r\n\r\nworld\r\n\r\n"
+
+	r, err := NewReader(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("NewReader() error = %v", err)
+	}
+
+	// The first block is not read, so it is skipped by the next call.
+	_, err = r.Next()
+	if err != nil {
+		t.Fatalf("Reader.Next() error = %v", err)
+	}
+
+	rec, err := r.Next()
+	if err != nil {
+		t.Fatalf("Reader.Next() error = %v", err)
+	}
+	block, err := io.ReadAll(rec.Block)
+	if err != nil || string(block) != "world" {
+		t.Errorf("Record.Block = %q, %v, want world", block, err)
+	}
+}
+
+func TestReader_Next_invalid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		data string
+	}{
+		{name: "no warc", data: "<html></html>\r\n"},
+		{name: "no content length", data: "WARC/1.0\r\nWARC-Type: resource\r\n\r\n"},
+		{name: "negative content length", data: "WARC/1.0\r\nContent-Length: -1\r\n\r\n"},
+		{name: "truncated header", data: "WARC/1.0\r\nWARC-Type: resource\r\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := NewReader(strings.NewReader(tt.data))
+			if err != nil {
+				t.Fatalf("NewReader() error = %v", err)
+			}
+
+			_, err = r.Next()
+			if !errors.Is(err, ErrInvalidRecord) {
+				t.Errorf("Reader.Next() error = %v, want %v", err, ErrInvalidRecord)
+			}
+		})
+	}
+}